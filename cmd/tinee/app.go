@@ -0,0 +1,413 @@
+package main
+
+import (
+	"context"
+	"net"
+	stdhttp "net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.uber.org/zap"
+	stdgrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+
+	"tinee/internal/config"
+	"tinee/internal/gateway"
+	"tinee/internal/geoip"
+	"tinee/internal/grpc"
+	"tinee/internal/health"
+	"tinee/internal/http"
+	"tinee/internal/lifecycle"
+	"tinee/internal/logging"
+	"tinee/internal/metrics"
+	"tinee/internal/mongodb"
+	"tinee/internal/redis"
+	"tinee/internal/service"
+	"tinee/internal/tls"
+	"tinee/internal/tracing"
+	"tinee/pkg/pb"
+)
+
+// app is tinee application, its components set its fields as they start.
+type app struct {
+	cfg config.Config
+	// path is path of configuration file reloaded on SIGHUP.
+	path string
+	// level is level of the global logger.
+	level zap.AtomicLevel
+	// migrate applies MongoDB migrations at startup.
+	migrate bool
+
+	tp  *tracing.Provider
+	mgo *mongodb.DB
+	rds *redis.DB
+	geo *geoip.DB
+	m   *metrics.Metrics
+	s   *service.Service
+	hc  *health.Health
+	gw  stdhttp.Handler
+}
+
+// newApp creates and returns a new app instance.
+func newApp(cfg config.Config, migrate bool) *app {
+	return &app{cfg: cfg, migrate: migrate && cfg.MongoDB.Migrate, m: metrics.New()}
+}
+
+// dependencies returns components of the service and its dependencies.
+func (a *app) dependencies() []lifecycle.Component {
+	return []lifecycle.Component{
+		{
+			Name: "tracing",
+			Start: func(ctx context.Context) (err error) {
+				a.tp, err = tracing.Open(ctx, a.cfg.Tracing)
+				return err
+			},
+			Stop: func(ctx context.Context) error {
+				return a.tp.Close(ctx)
+			},
+		},
+		{
+			Name:  "MongoDB",
+			Start: a.startMongoDB,
+			Stop: func(ctx context.Context) error {
+				return a.mgo.Close(ctx)
+			},
+		},
+		{
+			Name: "Redis",
+			Start: func(ctx context.Context) (err error) {
+				a.rds, err = redis.Open(ctx, a.cfg.Redis)
+				return err
+			},
+			Stop: func(ctx context.Context) error {
+				return a.rds.Close()
+			},
+		},
+		{
+			Name: "GeoIP database",
+			Start: func(ctx context.Context) (err error) {
+				if a.cfg.GeoIP.Database != "" {
+					a.geo, err = geoip.Open(a.cfg.GeoIP)
+				}
+				return err
+			},
+			Stop: func(ctx context.Context) error {
+				if a.geo == nil {
+					return nil
+				}
+				return a.geo.Close()
+			},
+		},
+		{
+			Name:  "service",
+			Start: a.startService,
+		},
+	}
+}
+
+// servers returns components serving HTTP and gRPC APIs, and metrics if
+// they are enabled. Servers are stopped after tinee reported it is not
+// ready for the drain delay.
+func (a *app) servers() []lifecycle.Component {
+	components := []lifecycle.Component{
+		a.reloader(),
+		httpServer("HTTP server", a.cfg.HTTPServer.Addr, a.handler,
+			a.cfg.HTTPServer.TLSCertFile, a.cfg.HTTPServer.TLSKeyFile, "", a.cfg.HTTPServer.ShutdownTimeout),
+		a.grpcServer(),
+	}
+	if a.cfg.GRPCServer.GatewayAddr != "" {
+		components = append(components, a.gateway(), a.gatewayServer())
+	}
+	if a.cfg.Metrics.Addr != "" {
+		components = append(components, httpServer("metrics server", a.cfg.Metrics.Addr, func() stdhttp.Handler {
+			mux := stdhttp.NewServeMux()
+			mux.Handle("/metrics", a.m.Handler())
+			return mux
+		}, "", "", "", 0))
+	}
+
+	// drain is stopped first
+	return append(components, lifecycle.Component{
+		Name: "drain",
+		Stop: func(ctx context.Context) error {
+			a.hc.Drain()
+			zap.S().Infof("draining for %v", a.cfg.Health.DrainDelay)
+			time.Sleep(a.cfg.Health.DrainDelay)
+			return nil
+		},
+		StopTimeout: a.cfg.Health.DrainDelay + time.Second,
+	})
+}
+
+// reloader returns component reloading configuration on SIGHUP. Only log
+// level and batch limits are reloaded, other settings are applied after
+// restart.
+func (a *app) reloader() lifecycle.Component {
+	var (
+		hup  = make(chan os.Signal, 1)
+		done = make(chan struct{})
+	)
+
+	return lifecycle.Component{
+		Name: "configuration reloader",
+		Start: func(ctx context.Context) error {
+			signal.Notify(hup, syscall.SIGHUP)
+			return nil
+		},
+		Serve: func() error {
+			for {
+				select {
+				case <-hup:
+					a.reload()
+				case <-done:
+					return nil
+				}
+			}
+		},
+		Stop: func(ctx context.Context) error {
+			signal.Stop(hup)
+			close(done)
+			return nil
+		},
+	}
+}
+
+// reload reloads configuration, invalid configuration is not applied.
+func (a *app) reload() {
+	cfg, err := config.Load(a.path)
+	if err != nil {
+		zap.L().Error("reloading configuration: " + err.Error())
+		return
+	}
+
+	// configuration was validated
+	_ = a.level.UnmarshalText([]byte(cfg.Logging.Level))
+	a.s.Reload(cfg.Service)
+
+	restart := cfg
+	restart.Logging.Level = a.cfg.Logging.Level
+	restart.Service.BatchWorkers, restart.Service.MaxBatchSize = a.cfg.Service.BatchWorkers, a.cfg.Service.MaxBatchSize
+	if restart != a.cfg {
+		zap.L().Warn("configuration changes other than log level and batch limits are applied after restart")
+	}
+	// applied changes are not reported again by the next reload
+	a.cfg.Logging.Level = cfg.Logging.Level
+	a.cfg.Service.BatchWorkers, a.cfg.Service.MaxBatchSize = cfg.Service.BatchWorkers, cfg.Service.MaxBatchSize
+	zap.L().Info("reloaded configuration")
+}
+
+// startMongoDB connects to MongoDB and applies migrations.
+func (a *app) startMongoDB(ctx context.Context) (err error) {
+	if a.mgo, err = mongodb.Open(ctx, a.cfg.MongoDB); err != nil {
+		return err
+	}
+
+	if a.migrate {
+		applied, err := mongodb.Migrate(ctx, a.mgo, mongodb.Migrations)
+		if err != nil {
+			return err
+		}
+		zap.S().Infof("applied %d MongoDB migrations", len(applied))
+	}
+
+	return nil
+}
+
+// startService creates service and health checks of its dependencies.
+func (a *app) startService(ctx context.Context) error {
+	repo := tracing.NewLinkRepo(tracing.DBMongoDB,
+		metrics.NewLinkRepo(a.m, metrics.StoreMongoDB, mongodb.NewLinkRepo(a.mgo)))
+	cache := tracing.NewLinkCache(tracing.DBRedis,
+		metrics.NewLinkCache(a.m, metrics.StoreRedis, redis.NewLinkCache(a.rds)))
+	counter := tracing.NewVariantCounter(tracing.DBRedis,
+		metrics.NewVariantCounter(a.m, metrics.StoreRedis, redis.NewVariantCounter(a.rds)))
+	a.s = service.New(a.cfg.Service, repo, cache, counter)
+
+	a.hc = health.New(a.cfg.Health, map[string]health.Pinger{
+		"mongodb": a.mgo,
+		"redis":   a.rds,
+	}, grpc.ServiceName)
+
+	return nil
+}
+
+// handler returns HTTP API handler with its middleware and health checks.
+func (a *app) handler() stdhttp.Handler {
+	var geo http.GeoIP
+	if a.geo != nil {
+		geo = a.geo
+	}
+
+	// health checks are not logged and measured
+	mux := stdhttp.NewServeMux()
+	mux.HandleFunc("/healthz", a.hc.Healthz)
+	mux.HandleFunc("/readyz", a.hc.Readyz)
+	mux.Handle("/", tracing.HTTP(logging.HTTP(a.m.HTTP(http.NewHandler(a.cfg.HTTPServer, a.s, geo)))))
+
+	return mux
+}
+
+// grpcServer returns component serving gRPC API and health checks.
+func (a *app) grpcServer() lifecycle.Component {
+	var (
+		srv *stdgrpc.Server
+		l   net.Listener
+	)
+
+	return lifecycle.Component{
+		Name: "gRPC server",
+		Start: func(ctx context.Context) (err error) {
+			opts := a.grpcInterceptors()
+			if cfg := a.cfg.GRPCServer; cfg.TLSCertFile != "" {
+				tc, err := tls.ServerConfig(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile)
+				if err != nil {
+					return err
+				}
+				opts = append(opts, stdgrpc.Creds(credentials.NewTLS(tc)))
+			}
+
+			if l, err = net.Listen("tcp", a.cfg.GRPCServer.Addr); err != nil {
+				return err
+			}
+			srv = stdgrpc.NewServer(opts...)
+			pb.RegisterTineeURLServer(srv, grpc.NewHandler(a.cfg.GRPCServer, a.s))
+			grpc_health_v1.RegisterHealthServer(srv, a.hc)
+			zap.S().Infof("gRPC server listening on %s", l.Addr())
+			return nil
+		},
+		Serve: func() error {
+			return srv.Serve(l)
+		},
+		Stop: func(ctx context.Context) error {
+			done := make(chan struct{})
+			go func() {
+				srv.GracefulStop()
+				close(done)
+			}()
+
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				srv.Stop()
+				return ctx.Err()
+			}
+		},
+		StopTimeout: a.cfg.GRPCServer.ShutdownTimeout,
+	}
+}
+
+// grpcInterceptors returns options of gRPC servers with interceptors of
+// tracing, logging and metrics.
+func (a *app) grpcInterceptors() []stdgrpc.ServerOption {
+	return []stdgrpc.ServerOption{
+		stdgrpc.ChainUnaryInterceptor(otelgrpc.UnaryServerInterceptor(), logging.UnaryInterceptor, a.m.UnaryInterceptor),
+		stdgrpc.ChainStreamInterceptor(otelgrpc.StreamServerInterceptor(), logging.StreamInterceptor, a.m.StreamInterceptor),
+	}
+}
+
+// gateway returns component serving gRPC API to REST gateway over
+// in-memory connection. TLS settings of gRPC server are applied by gateway
+// server instead, it is stopped after the server stopped serving the
+// gateway.
+func (a *app) gateway() lifecycle.Component {
+	var (
+		srv  *stdgrpc.Server
+		l    *bufconn.Listener
+		conn *stdgrpc.ClientConn
+	)
+
+	return lifecycle.Component{
+		Name: "REST gateway",
+		Start: func(ctx context.Context) (err error) {
+			l = bufconn.Listen(1 << 20)
+			srv = stdgrpc.NewServer(a.grpcInterceptors()...)
+			pb.RegisterTineeURLServer(srv, grpc.NewHandler(a.cfg.GRPCServer, a.s))
+
+			conn, err = stdgrpc.DialContext(ctx, "bufconn",
+				stdgrpc.WithInsecure(),
+				stdgrpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+					return l.DialContext(ctx)
+				}),
+				stdgrpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor()),
+				stdgrpc.WithStreamInterceptor(otelgrpc.StreamClientInterceptor()),
+			)
+			if err != nil {
+				return err
+			}
+			a.gw, err = gateway.New(ctx, conn)
+			return err
+		},
+		Serve: func() error {
+			return srv.Serve(l)
+		},
+		Stop: func(ctx context.Context) error {
+			_ = conn.Close()
+			srv.Stop()
+			return nil
+		},
+	}
+}
+
+// gatewayServer returns component serving REST gateway on its own address
+// with TLS settings of gRPC server, so clients of the gateway are required
+// to present certificates like gRPC clients are.
+func (a *app) gatewayServer() lifecycle.Component {
+	cfg := a.cfg.GRPCServer
+
+	// gateway calls are logged and measured by gRPC interceptors
+	return httpServer("REST gateway server", cfg.GatewayAddr, func() stdhttp.Handler { return tracing.HTTP(a.gw) },
+		cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile, cfg.ShutdownTimeout)
+}
+
+// httpServer returns component serving HTTP handler on address, the
+// handler is created when the component starts. It serves HTTPS if
+// certificate and key files are not empty, and requires client
+// certificates signed by CAs of clientCAFile if it is not empty.
+func httpServer(name, addr string, handler func() stdhttp.Handler, certFile, keyFile, clientCAFile string, stopTimeout time.Duration) lifecycle.Component {
+	var (
+		srv *stdhttp.Server
+		l   net.Listener
+	)
+
+	return lifecycle.Component{
+		Name: name,
+		Start: func(ctx context.Context) (err error) {
+			srv = &stdhttp.Server{Addr: addr}
+			if certFile != "" {
+				if srv.TLSConfig, err = tls.ServerConfig(certFile, keyFile, clientCAFile); err != nil {
+					return err
+				}
+			}
+
+			if l, err = net.Listen("tcp", addr); err != nil {
+				return err
+			}
+			srv.Handler = handler()
+			zap.S().Infof("%s listening on %s", name, l.Addr())
+			return nil
+		},
+		Serve: func() error {
+			var err error
+			if srv.TLSConfig != nil {
+				// certificate is got from TLS configuration
+				err = srv.ServeTLS(l, "", "")
+			} else {
+				err = srv.Serve(l)
+			}
+			if err != stdhttp.ErrServerClosed {
+				return err
+			}
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			return srv.Shutdown(ctx)
+		},
+		StopTimeout: stopTimeout,
+	}
+}
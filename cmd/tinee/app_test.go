@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"net"
+	stdhttp "net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"tinee/internal/config"
+	"tinee/internal/lifecycle"
+	"tinee/internal/metrics"
+	"tinee/internal/service"
+	"tinee/internal/tls"
+	"tinee/internal/tls/tlstest"
+)
+
+// freeAddr returns loopback address with free port.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	return l.Addr().String()
+}
+
+func TestApp_GatewayServer(t *testing.T) {
+	testcases := []struct {
+		name       string
+		clientCert bool
+		expStatus  int
+	}{
+		{name: "client certificate is missing", clientCert: false, expStatus: 0},
+		// listing is unimplemented as admin token is not configured
+		{name: "client certificate is verified", clientCert: true, expStatus: stdhttp.StatusNotImplemented},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			expStatus := tc.expStatus
+			ctx := context.Background()
+			ca := tlstest.NewAuthority(t)
+			caFile := ca.WriteCA(t)
+			serverCertFile, serverKeyFile := ca.Write(t, "localhost", 2)
+			a := &app{m: metrics.New(), cfg: config.Config{GRPCServer: config.GRPCServer{
+				GatewayAddr:     freeAddr(t),
+				TLSCertFile:     serverCertFile,
+				TLSKeyFile:      serverKeyFile,
+				TLSClientCAFile: caFile,
+			}}}
+			for _, c := range []lifecycle.Component{a.gateway(), a.gatewayServer()} {
+				c := c
+				is.NoErr(c.Start(ctx))
+				go func() { _ = c.Serve() }()
+				t.Cleanup(func() { _ = c.Stop(ctx) })
+			}
+			certFile, keyFile := "", ""
+			if tc.clientCert {
+				certFile, keyFile = ca.Write(t, "client", 3)
+			}
+			clientCfg, err := tls.ClientConfig(caFile, certFile, keyFile)
+			is.NoErr(err)
+			clientCfg.ServerName = "localhost"
+			hc := &stdhttp.Client{Transport: &stdhttp.Transport{TLSClientConfig: clientCfg}}
+
+			resp, err := hc.Get("https://" + a.cfg.GRPCServer.GatewayAddr + "/v1/links")
+
+			if expStatus == 0 {
+				is.True(err != nil) // request is refused
+				return
+			}
+			is.NoErr(err)
+			defer resp.Body.Close()
+			is.Equal(expStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestApp_Reload(t *testing.T) {
+	is := is.New(t)
+	core, logs := observer.New(zapcore.DebugLevel)
+	t.Cleanup(zap.ReplaceGlobals(zap.New(core)))
+	path := filepath.Join(t.TempDir(), "tinee.yaml")
+	write := func(data string) {
+		is.NoErr(os.WriteFile(path, []byte("profile: dev\n"+data), 0o600))
+	}
+	write("logging:\n  level: info\n")
+	cfg, err := config.Load(path)
+	is.NoErr(err)
+	a := &app{cfg: cfg, path: path, level: zap.NewAtomicLevel(), s: service.New(cfg.Service, nil, nil, nil)}
+
+	write("logging:\n  level: debug\n")
+	a.reload()
+	write("logging:\n  level: debug\nservice:\n  maxbatchsize: 10\n")
+	a.reload()
+
+	is.Equal(2, logs.FilterMessage("reloaded configuration").Len())
+	is.Equal(0, logs.FilterLevelExact(zapcore.WarnLevel).Len()) // applied changes are not reported
+	is.Equal(zapcore.DebugLevel, a.level.Level())
+	is.Equal("debug", a.cfg.Logging.Level)
+	is.Equal(10, a.cfg.Service.MaxBatchSize)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"tinee/internal/mongodb"
+	"tinee/internal/service"
+	"tinee/internal/transfer"
+)
+
+// usage is the usage of tinee commands.
+const usage = `usage:
+  tinee [-config file]                    serve HTTP and gRPC APIs
+  tinee export [-format csv|jsonl] [-o file]
+  tinee import [-format csv|jsonl|bitly|yourls|kutt] [-conflict skip|overwrite|fail]
+               [-dry-run] [file]
+  tinee migrate [-status]`
+
+// run runs the command from args.
+func run(ctx context.Context, s *service.Service, db *mongodb.DB, args []string) error {
+	switch args[0] {
+	case "migrate":
+		return migrate(ctx, db, args[1:])
+	case "export":
+		return export(ctx, s, args[1:])
+	case "import":
+		return importLinks(ctx, s, args[1:])
+	}
+
+	return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+}
+
+// export exports all links to a file or stdout.
+func export(ctx context.Context, s *service.Service, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", transfer.FormatJSONL, "export format, csv or jsonl")
+	output := fs.String("o", "", "output file, stdout if empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	enc, err := transfer.NewEncoder(w, *format)
+	if err != nil {
+		return err
+	}
+	if err = s.Export(ctx, enc.Encode); err != nil {
+		return err
+	}
+
+	return enc.Flush()
+}
+
+// importLinks imports links from a file or stdin and prints the report.
+func importLinks(ctx context.Context, s *service.Service, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := fs.String("format", transfer.FormatJSONL, "import format, csv, jsonl, bitly, yourls or kutt")
+	conflict := fs.String("conflict", string(service.ConflictSkip),
+		"conflict policy, skip, overwrite or fail; links of other shorteners are always merged")
+	dryRun := fs.Bool("dry-run", false, "validate links and report conflicts without saving")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if fs.NArg() > 0 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	dec, err := transfer.NewDecoder(r, *format)
+	if err != nil {
+		return err
+	}
+
+	report, err := s.Import(ctx, dec.Decode, service.ImportOptions{
+		Conflict: service.ConflictPolicy(*conflict),
+		DryRun:   *dryRun,
+		Foreign:  transfer.Foreign(*format),
+	})
+	for _, e := range report.Errors {
+		fmt.Fprintln(os.Stderr, e.Error())
+	}
+	for _, e := range report.Conflicts {
+		fmt.Fprintf(os.Stderr, "skipped %v\n", e)
+	}
+	if *dryRun {
+		fmt.Fprint(os.Stderr, "dry run, ")
+	}
+	fmt.Fprintf(os.Stderr, "imported: %d, skipped: %d, failed: %d\n", report.Imported, report.Skipped, report.Failed)
+
+	return err
+}
+
+// migrate applies pending migrations of the database or prints their
+// statuses.
+func migrate(ctx context.Context, db *mongodb.DB, args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	status := fs.Bool("status", false, "print statuses of migrations without applying them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *status {
+		statuses, err := mongodb.MigrationStatuses(ctx, db, mongodb.Migrations)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if !s.AppliedAt.IsZero() {
+				applied = "applied " + s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%d\t%s\t%s\n", s.Version, applied, s.Description)
+		}
+		return nil
+	}
+
+	applied, err := mongodb.Migrate(ctx, db, mongodb.Migrations)
+	for _, v := range applied {
+		fmt.Fprintf(os.Stderr, "applied migration %d\n", v)
+	}
+	if err == nil && len(applied) == 0 {
+		fmt.Fprintln(os.Stderr, "no pending migrations")
+	}
+
+	return err
+}
@@ -2,23 +2,15 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
-	"net"
-	stdhttp "net/http"
 	"os"
-	"os/signal"
-	"time"
 
 	"go.uber.org/zap"
-	stdgrpc "google.golang.org/grpc"
 
 	"tinee/internal/config"
-	"tinee/internal/grpc"
-	"tinee/internal/http"
-	"tinee/internal/mongodb"
-	"tinee/internal/redis"
-	"tinee/internal/service"
-	"tinee/pkg/pb"
+	"tinee/internal/lifecycle"
+	"tinee/internal/logging"
 )
 
 func main() {
@@ -29,73 +21,43 @@ func main() {
 		log.Fatal(err)
 	}
 	undo := zap.ReplaceGlobals(logger)
-	defer undo()
-
-	cfg := config.Get()
 
-	mgo, err := mongodb.Open(ctx, cfg.MongoDB)
-	if err != nil {
-		zap.L().Fatal(err.Error())
-	}
-	zap.L().Info("connected to MongoDB")
+	configPath := flag.String("config", os.Getenv("TINEE_CONFIG"), "path of YAML or TOML configuration file")
+	flag.Parse()
 
-	rds, err := redis.Open(ctx, cfg.Redis)
+	cfg, err := config.Load(*configPath)
 	if err != nil {
 		zap.L().Fatal(err.Error())
 	}
-	zap.L().Info("connected to Redis")
-
-	repo := mongodb.NewLinkRepo(mgo)
-	cache := redis.NewLinkCache(rds)
-	s := service.New(cfg.Service, repo, cache)
-
-	httpServer := &stdhttp.Server{
-		Addr:    cfg.HTTPServer.Addr,
-		Handler: http.NewHandler(s),
-	}
-
-	grpcServer := stdgrpc.NewServer()
-	pb.RegisterTineeURLServer(grpcServer, grpc.NewHandler(s))
-	l, err := net.Listen("tcp", cfg.GRPCServer.Addr)
+	logger, level, err := logging.New(cfg.Logging)
 	if err != nil {
 		zap.L().Fatal(err.Error())
 	}
-
-	go func() {
-		if err := httpServer.ListenAndServe(); err != nil && err != stdhttp.ErrServerClosed {
-			zap.L().Fatal(err.Error())
-		}
-	}()
-	zap.S().Infof("starting HTTP server on %s", cfg.HTTPServer.Addr)
-
-	go func() {
-		if err := grpcServer.Serve(l); err != nil {
-			zap.L().Fatal(err.Error())
+	undo()
+	undo = zap.ReplaceGlobals(logger)
+	defer undo()
+	zap.L().Info("loaded configuration", zap.Any("config", cfg.Redact()))
+
+	// migrate command applies migrations itself
+	a := newApp(cfg, flag.Arg(0) != "migrate")
+	a.path, a.level = *configPath, level
+	lc := lifecycle.New(cfg.Lifecycle, a.dependencies()...)
+
+	if flag.NArg() > 0 {
+		if err = lc.Start(ctx); err == nil {
+			if err = run(ctx, a.s, a.mgo, flag.Args()); err != nil {
+				zap.L().Error(err.Error())
+			}
+			if stopErr := lc.Stop(); err == nil {
+				err = stopErr
+			}
 		}
-	}()
-	zap.S().Infof("starting gRPC server on %s", cfg.GRPCServer.Addr)
-
-	done := make(chan os.Signal, 1)
-	signal.Notify(done, os.Interrupt)
-	<-done
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := httpServer.Shutdown(ctx); err != nil {
-		zap.L().Fatal(err.Error())
+	} else {
+		lc.Append(a.servers()...)
+		err = lc.Run(ctx)
 	}
-	zap.L().Info("HTTP server shut down gracefully")
-
-	grpcServer.GracefulStop()
-	zap.L().Info("gRPC server shut down gracefully")
 
-	if err = mgo.Close(ctx); err != nil {
-		zap.L().Error(err.Error())
-	}
-	zap.L().Info("disconnected from MongoDB")
-
-	if err = rds.Close(); err != nil {
-		zap.L().Error(err.Error())
+	if err != nil {
+		os.Exit(1)
 	}
-	zap.L().Info("disconnected from Redis")
 }
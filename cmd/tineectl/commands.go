@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc/status"
+
+	"tinee/pkg/pb"
+)
+
+const (
+	// formatText is human-readable output format.
+	formatText = "text"
+	// formatJSON is output format with a JSON object per result.
+	formatJSON = "json"
+)
+
+// result is the result of shortening URL or resolving alias.
+type result struct {
+	URL      string `json:"url,omitempty"`
+	Alias    string `json:"alias,omitempty"`
+	TineeURL string `json:"tineeUrl,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// output prints results in an output format.
+type output struct {
+	w      io.Writer
+	format string
+}
+
+// newOutput creates and returns a new output instance.
+func newOutput(w io.Writer, format string) (*output, error) {
+	if format != formatText && format != formatJSON {
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+
+	return &output{w: w, format: format}, nil
+}
+
+// print prints the result, text is its representation in text format.
+func (o *output) print(r result, text string) error {
+	if o.format == formatJSON {
+		return json.NewEncoder(o.w).Encode(r)
+	}
+
+	_, err := fmt.Fprintln(o.w, text)
+	return err
+}
+
+// errorMessage returns message of gRPC error.
+func errorMessage(err error) string {
+	return status.Convert(err).Message()
+}
+
+// run runs the command from args.
+func run(ctx context.Context, c pb.TineeURLClient, o *output, args []string) error {
+	switch args[0] {
+	case "shorten":
+		return shorten(ctx, c, o, args[1:])
+	case "resolve":
+		return resolve(ctx, c, o, args[1:])
+	case "bulk":
+		return bulk(ctx, c, o, args[1:])
+	}
+
+	return errUsage
+}
+
+// shorten shortens URL with optional custom alias.
+func shorten(ctx context.Context, c pb.TineeURLClient, o *output, args []string) error {
+	if len(args) != 1 && len(args) != 2 {
+		return errUsage
+	}
+	r := &pb.ShortenRequest{Url: args[0]}
+	if len(args) == 2 {
+		r.Alias = args[1]
+	}
+
+	resp, err := c.Shorten(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	return o.print(result{URL: r.Url, Alias: r.Alias, TineeURL: resp.GetTineeUrl()}, resp.GetTineeUrl())
+}
+
+// resolve prints URLs of aliases. Only URL is printed in text format
+// if a single alias is resolved.
+func resolve(ctx context.Context, c pb.TineeURLClient, o *output, aliases []string) error {
+	if len(aliases) == 0 {
+		return errUsage
+	}
+	if len(aliases) == 1 {
+		resp, err := c.UrlByAlias(ctx, &pb.UrlByAliasRequest{Alias: aliases[0]})
+		if err != nil {
+			return err
+		}
+
+		return o.print(result{Alias: aliases[0], URL: resp.GetUrl()}, resp.GetUrl())
+	}
+
+	resp, err := c.BatchUrlByAlias(ctx, &pb.BatchUrlByAliasRequest{Aliases: aliases})
+	if err != nil {
+		return err
+	}
+
+	var failed bool
+	for _, a := range aliases {
+		r, text := result{Alias: a}, ""
+		if URL, ok := resp.GetUrls()[a]; ok {
+			r.URL, text = URL, a+"\t"+URL
+		} else {
+			r.Error, text, failed = "link not found", a+"\terror: link not found", true
+		}
+		if err = o.print(r, text); err != nil {
+			return err
+		}
+	}
+	if failed {
+		return errFailed
+	}
+
+	return nil
+}
+
+// bulk shortens URLs from file or stdin over shortening stream. Every
+// non-empty line that does not start with # is URL optionally followed
+// by custom alias.
+func bulk(ctx context.Context, c pb.TineeURLClient, o *output, args []string) error {
+	if len(args) > 1 {
+		return errUsage
+	}
+	var in io.Reader = os.Stdin
+	if len(args) == 1 && args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	stream, err := c.ShortenStream(ctx)
+	if err != nil {
+		return err
+	}
+
+	// requests are sent while responses are received, sent requests are
+	// passed to the receiver to be matched with responses in order
+	sent := make(chan *pb.ShortenRequest, 100)
+	sendErr := make(chan error, 1)
+	go func() {
+		defer close(sent)
+		s := bufio.NewScanner(in)
+		for s.Scan() {
+			fields := strings.Fields(s.Text())
+			if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+				continue
+			}
+			r := &pb.ShortenRequest{Url: fields[0]}
+			if len(fields) > 1 {
+				r.Alias = fields[1]
+			}
+
+			if err := stream.Send(r); err != nil {
+				sendErr <- err
+				return
+			}
+			sent <- r
+		}
+		if err := s.Err(); err != nil {
+			sendErr <- err
+			return
+		}
+		sendErr <- stream.CloseSend()
+	}()
+
+	var failed bool
+	for r := range sent {
+		resp, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		res := result{URL: r.Url, Alias: r.Alias, TineeURL: resp.GetTineeUrl(), Error: resp.GetError()}
+		text := r.Url + "\t" + res.TineeURL
+		if res.Error != "" {
+			text, failed = r.Url+"\terror: "+res.Error, true
+		}
+		if err = o.print(res, text); err != nil {
+			return err
+		}
+	}
+
+	if err = <-sendErr; err == io.EOF {
+		// stream was closed by server, its status is returned by Recv
+		_, err = stream.Recv()
+	}
+	if err != nil {
+		return err
+	}
+	if failed {
+		return errFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+	"google.golang.org/grpc"
+
+	"tinee/pkg/pb"
+	"tinee/pkg/tineetest"
+)
+
+// newClient returns client of the tinee server.
+func newClient(t *testing.T, s *tineetest.Server) pb.TineeURLClient {
+	t.Helper()
+	conn, err := grpc.Dial(s.GRPCAddr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return pb.NewTineeURLClient(conn)
+}
+
+func TestRun(t *testing.T) {
+	testcases := []struct {
+		name       string
+		args       []string
+		format     string
+		input      string
+		expOut     string
+		expErr     error
+		expMessage string
+	}{
+		{
+			name:   "URL is shortened",
+			args:   []string{"shorten", "https://y.yy", "yyyy"},
+			format: formatText,
+			expOut: "tinee.test/yyyy\n",
+		},
+		{
+			name:   "URL is shortened with JSON output",
+			args:   []string{"shorten", "https://y.yy", "yyyy"},
+			format: formatJSON,
+			expOut: `{"url":"https://y.yy","alias":"yyyy","tineeUrl":"tinee.test/yyyy"}` + "\n",
+		},
+		{
+			name:       "invalid URL",
+			args:       []string{"shorten", "y.yy"},
+			format:     formatText,
+			expMessage: "invalid URL",
+		},
+		{
+			name:   "alias is resolved",
+			args:   []string{"resolve", "xxxx"},
+			format: formatText,
+			expOut: "https://x.xx\n",
+		},
+		{
+			name:       "alias is not found",
+			args:       []string{"resolve", "none"},
+			format:     formatText,
+			expMessage: "link not found",
+		},
+		{
+			name:   "aliases are resolved",
+			args:   []string{"resolve", "xxxx", "none"},
+			format: formatText,
+			expOut: "xxxx\thttps://x.xx\nnone\terror: link not found\n",
+			expErr: errFailed,
+		},
+		{
+			name:   "aliases are resolved with JSON output",
+			args:   []string{"resolve", "xxxx", "none"},
+			format: formatJSON,
+			expOut: `{"url":"https://x.xx","alias":"xxxx"}` + "\n" +
+				`{"alias":"none","error":"link not found"}` + "\n",
+			expErr: errFailed,
+		},
+		{
+			name:   "URLs are shortened in bulk",
+			args:   []string{"bulk"},
+			format: formatText,
+			input:  "# links\nhttps://y.yy yyyy\n\nhttps://z.zz zzzz\n",
+			expOut: "https://y.yy\ttinee.test/yyyy\nhttps://z.zz\ttinee.test/zzzz\n",
+		},
+		{
+			name:   "bulk with failed URLs",
+			args:   []string{"bulk"},
+			format: formatJSON,
+			input:  "y.yy\nhttps://y.yy yyyy\nhttps://z.zz xxxx\n",
+			expOut: `{"url":"y.yy","error":"invalid URL"}` + "\n" +
+				`{"url":"https://y.yy","alias":"yyyy","tineeUrl":"tinee.test/yyyy"}` + "\n" +
+				`{"url":"https://z.zz","alias":"xxxx","error":"alias is taken"}` + "\n",
+			expErr: errFailed,
+		},
+		{
+			name:   "unknown command",
+			args:   []string{"delete", "xxxx"},
+			format: formatText,
+			expErr: errUsage,
+		},
+		{
+			name:   "missing arguments",
+			args:   []string{"shorten"},
+			format: formatText,
+			expErr: errUsage,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			s := tineetest.NewServer(t, tineetest.Config{})
+			s.Seed("https://x.xx", "xxxx")
+			c := newClient(t, s)
+			args := tc.args
+			if tc.input != "" {
+				file := filepath.Join(t.TempDir(), "urls.txt")
+				is.NoErr(os.WriteFile(file, []byte(tc.input), 0o600))
+				args = append(args, file)
+			}
+			var buf bytes.Buffer
+			o, err := newOutput(&buf, tc.format)
+			is.NoErr(err)
+
+			err = run(context.Background(), c, o, args)
+
+			if tc.expMessage != "" {
+				is.Equal(tc.expMessage, errorMessage(err))
+			} else {
+				is.Equal(tc.expErr, err)
+			}
+			is.Equal(tc.expOut, buf.String())
+		})
+	}
+}
+
+func TestNewOutput_UnknownFormat(t *testing.T) {
+	is := is.New(t)
+
+	_, err := newOutput(&bytes.Buffer{}, "yaml")
+
+	is.True(err != nil)
+}
@@ -0,0 +1,146 @@
+// Command tineectl is command-line client of tinee gRPC API.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"tinee/internal/tls"
+	"tinee/pkg/pb"
+)
+
+// usage is the usage of tineectl.
+const usage = `usage: tineectl [flags] command [args]
+
+commands:
+  shorten URL [alias]   shorten URL, optionally with custom alias
+  resolve alias...      print URLs of aliases
+  bulk [file]           shorten URLs from file or stdin, one "URL [alias]" per line
+
+flags:`
+
+var (
+	// errUsage is returned when command is used incorrectly.
+	errUsage = errors.New("invalid usage")
+	// errFailed is returned when some of URLs or aliases were not
+	// processed, its details are already printed.
+	errFailed = errors.New("some of requests failed")
+)
+
+func main() {
+	os.Exit(ctl(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// ctl runs tineectl with args and returns its exit code. Results are
+// printed to stdout, errors and usage to stderr.
+func ctl(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("tineectl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, usage)
+		fs.PrintDefaults()
+	}
+
+	addr := fs.String("addr", env("TINEE_ADDR", "localhost:8081"), "address of tinee gRPC API, or TINEE_ADDR")
+	useTLS := fs.Bool("tls", envBool("TINEE_TLS"), "connect using TLS, or TINEE_TLS")
+	ca := fs.String("ca", env("TINEE_CA", ""), "PEM file of CA certificates verifying server, or TINEE_CA")
+	cert := fs.String("cert", env("TINEE_CERT", ""), "PEM file of client certificate for mTLS, or TINEE_CERT")
+	key := fs.String("key", env("TINEE_KEY", ""), "PEM file of client key for mTLS, or TINEE_KEY")
+	apiKey := fs.String("api-key", env("TINEE_API_KEY", ""), "API key sent with requests, or TINEE_API_KEY")
+	format := fs.String("o", env("TINEE_OUTPUT", formatText), "output format, text or json, or TINEE_OUTPUT")
+	timeout := fs.Duration("timeout", 30*time.Second, "timeout of command")
+	if err := fs.Parse(args); err == flag.ErrHelp {
+		return 0
+	} else if err != nil {
+		return 2
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	out, err := newOutput(stdout, *format)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	opts := []grpc.DialOption{grpc.WithBlock()}
+	secure := *useTLS || *ca != "" || *cert != ""
+	if secure {
+		tlsCfg, err := tls.ClientConfig(*ca, *cert, *key)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+	if *apiKey != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(apiKeyCredentials{key: *apiKey, secure: secure}))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, *addr, opts...)
+	if err != nil {
+		fmt.Fprintf(stderr, "connecting to %s: %v\n", *addr, err)
+		return 1
+	}
+	defer conn.Close()
+
+	if err = run(ctx, pb.NewTineeURLClient(conn), out, fs.Args()); err == errUsage {
+		fs.Usage()
+		return 2
+	} else if err != nil {
+		if err != errFailed {
+			fmt.Fprintln(stderr, errorMessage(err))
+		}
+		return 1
+	}
+
+	return 0
+}
+
+// env returns value of environment variable key, or def if it is not set.
+func env(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+
+	return def
+}
+
+// envBool returns boolean value of environment variable key.
+func envBool(key string) bool {
+	b, _ := strconv.ParseBool(os.Getenv(key))
+
+	return b
+}
+
+// apiKeyCredentials sends API key as bearer token of requests.
+type apiKeyCredentials struct {
+	key    string
+	secure bool
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials interface.
+func (c apiKeyCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + c.key}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials interface.
+func (c apiKeyCredentials) RequireTransportSecurity() bool {
+	return c.secure
+}
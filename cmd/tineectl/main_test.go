@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"tinee/pkg/tineetest"
+)
+
+func TestCtl(t *testing.T) {
+	testcases := []struct {
+		name      string
+		args      []string
+		expCode   int
+		expStdout string
+		expStderr string
+	}{
+		{
+			name:      "command succeeded",
+			args:      []string{"resolve", "xxxx"},
+			expCode:   0,
+			expStdout: "https://x.xx\n",
+		},
+		{
+			name:      "some of requests failed",
+			args:      []string{"resolve", "xxxx", "none"},
+			expCode:   1,
+			expStdout: "xxxx\thttps://x.xx\nnone\terror: link not found\n",
+		},
+		{
+			name:      "request failed",
+			args:      []string{"shorten", "x.xx"},
+			expCode:   1,
+			expStderr: "invalid URL\n",
+		},
+		{
+			name:    "invalid usage",
+			args:    []string{"shorten"},
+			expCode: 2,
+		},
+		{
+			name:      "unknown output format",
+			args:      []string{"-o", "yaml", "resolve", "xxxx"},
+			expCode:   2,
+			expStderr: "unknown output format \"yaml\"\n",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			s := tineetest.NewServer(t, tineetest.Config{})
+			s.Seed("https://x.xx", "xxxx")
+			var stdout, stderr bytes.Buffer
+
+			code := ctl(append([]string{"-addr", s.GRPCAddr, "-timeout", "5s"}, tc.args...), &stdout, &stderr)
+
+			is.Equal(tc.expCode, code)
+			is.Equal(tc.expStdout, stdout.String())
+			if tc.expCode == 2 && tc.expStderr == "" {
+				is.True(bytes.HasPrefix(stderr.Bytes(), []byte("usage: tineectl"))) // usage is printed
+			} else {
+				is.Equal(tc.expStderr, stderr.String())
+			}
+		})
+	}
+}
@@ -2,62 +2,241 @@
 package config
 
 import (
-	"sync"
+	"net"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
-	"go.uber.org/zap"
 )
 
-var (
-	cfg  Config
-	once sync.Once
+const (
+	// ProfileDev allows default credentials for local development.
+	ProfileDev = "dev"
+	// ProfileProduction requires credentials to be set.
+	ProfileProduction = "production"
 )
 
-// Config is configuration for all application components.
+// Config is configuration for all application components. In configuration
+// file, components are tables named by tags, and their settings are keys
+// named by lowercase field names. Settings tagged as secret can be read from
+// files at paths of their env variables with _FILE suffix.
 type Config struct {
-	Service
-	MongoDB
-	HTTPServer
-	GRPCServer
-	Redis
+	// Profile is dev or production, default credentials are refused
+	// outside dev profile.
+	Profile string `envconfig:"TINEE_PROFILE" default:"production" yaml:"profile" toml:"profile" json:"profile"`
+
+	Service    `yaml:"service" toml:"service" json:"service"`
+	MongoDB    `yaml:"mongodb" toml:"mongodb" json:"mongodb"`
+	HTTPServer `yaml:"httpserver" toml:"httpserver" json:"httpserver"`
+	GRPCServer `yaml:"grpcserver" toml:"grpcserver" json:"grpcserver"`
+	Redis      `yaml:"redis" toml:"redis" json:"redis"`
+	GeoIP      `yaml:"geoip" toml:"geoip" json:"geoip"`
+	Metrics    `yaml:"metrics" toml:"metrics" json:"metrics"`
+	Tracing    `yaml:"tracing" toml:"tracing" json:"tracing"`
+	Logging    `yaml:"logging" toml:"logging" json:"logging"`
+	Health     `yaml:"health" toml:"health" json:"health"`
+	Lifecycle  `yaml:"lifecycle" toml:"lifecycle" json:"lifecycle"`
 }
 
 // Service is configuration for service.
 type Service struct {
 	Domain string `envconfig:"SERVICE_DOMAIN" default:"tinee.io"`
+	// BatchWorkers is the number of URLs of a batch shortened concurrently.
+	BatchWorkers int `envconfig:"SERVICE_BATCH_WORKERS" default:"8"`
+	// MaxBatchSize is max number of URLs in a batch, 0 means no limit.
+	MaxBatchSize int `envconfig:"SERVICE_MAX_BATCH_SIZE" default:"1000"`
 }
 
 // MongoDB is configuration for MongoDB database.
 type MongoDB struct {
 	URL      string `envconfig:"MONGO_URL" default:"mongodb://localhost:27017"`
 	Username string `envconfig:"MONGO_USERNAME" default:"root"`
-	Password string `envconfig:"MONGO_PASSWORD" default:"password"`
+	Password string `envconfig:"MONGO_PASSWORD" default:"password" secret:"true"`
 	DbName   string `envconfig:"MONGO_DBNAME" default:"tinee"`
+	// Migrate applies pending migrations of the database at startup.
+	Migrate bool `envconfig:"MONGO_MIGRATE" default:"true"`
+	// TLS enables TLS of connections to the database, it is also enabled
+	// by tls option of URL.
+	TLS bool `envconfig:"MONGO_TLS"`
+	// TLSCAFile is path of CA certificates of the database, system CAs
+	// are trusted if it is empty.
+	TLSCAFile string `envconfig:"MONGO_TLS_CA_FILE"`
+	// TLSCertFile and TLSKeyFile are paths of client certificate and key
+	// presented to the database, if they are not empty.
+	TLSCertFile string `envconfig:"MONGO_TLS_CERT_FILE"`
+	TLSKeyFile  string `envconfig:"MONGO_TLS_KEY_FILE"`
 }
 
 // HTTPServer is configuration for HTTP server.
 type HTTPServer struct {
 	Addr string `envconfig:"HTTPSERVER_ADDR" default:":8080"`
+	// AdminToken is bearer token of admin endpoints, they are disabled
+	// if it is empty.
+	AdminToken string `envconfig:"HTTPSERVER_ADMIN_TOKEN" secret:"true"`
+	// ShutdownTimeout is timeout of draining requests on shutdown.
+	ShutdownTimeout time.Duration `envconfig:"HTTPSERVER_SHUTDOWN_TIMEOUT" default:"10s"`
+	// TLSCertFile and TLSKeyFile are paths of certificate and key of
+	// the server, it serves plaintext if they are empty. They are
+	// reloaded when they change.
+	TLSCertFile string `envconfig:"HTTPSERVER_TLS_CERT_FILE"`
+	TLSKeyFile  string `envconfig:"HTTPSERVER_TLS_KEY_FILE"`
+	// TrustedProxies are comma-separated IPs or CIDRs of reverse proxies,
+	// client IP of requests from them is read from X-Forwarded-For or
+	// X-Real-IP header.
+	TrustedProxies string `envconfig:"HTTPSERVER_TRUSTED_PROXIES"`
+}
+
+// Proxies returns networks of TrustedProxies, single IPs are returned as
+// networks of one address.
+func (c HTTPServer) Proxies() ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, p := range strings.Split(c.TrustedProxies, ",") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		if !strings.Contains(p, "/") {
+			ip := net.ParseIP(p)
+			if ip == nil {
+				return nil, &net.ParseError{Type: "IP address", Text: p}
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(p)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+
+	return nets, nil
 }
 
 // GRPCServer is configuration for gRPC server.
 type GRPCServer struct {
 	Addr string `envconfig:"GRPCSERVER_ADDR" default:":8081"`
+	// AdminToken is bearer token of admin methods, they are disabled if
+	// it is empty.
+	AdminToken string `envconfig:"GRPCSERVER_ADMIN_TOKEN" secret:"true"`
+	// ShutdownTimeout is timeout of draining requests on shutdown, they
+	// are canceled after it.
+	ShutdownTimeout time.Duration `envconfig:"GRPCSERVER_SHUTDOWN_TIMEOUT" default:"10s"`
+	// TLSCertFile and TLSKeyFile are paths of certificate and key of
+	// the server, it serves plaintext if they are empty. They are
+	// reloaded when they change.
+	TLSCertFile string `envconfig:"GRPCSERVER_TLS_CERT_FILE"`
+	TLSKeyFile  string `envconfig:"GRPCSERVER_TLS_KEY_FILE"`
+	// TLSClientCAFile is path of CA certificates of clients, clients are
+	// required to present certificate signed by them if it is not empty.
+	TLSClientCAFile string `envconfig:"GRPCSERVER_TLS_CLIENT_CA_FILE"`
+	// GatewayAddr is address of REST gateway of the gRPC API, it is not
+	// served if it is empty. The gateway is served with TLS settings of
+	// the server, including client certificates.
+	GatewayAddr string `envconfig:"GRPCSERVER_GATEWAY_ADDR"`
 }
 
 // Redis is configuration for Redis.
 type Redis struct {
 	Addr     string `envconfig:"REDIS_ADDR" default:"localhost:6379"`
-	Password string `envconfig:"REDIS_PASSWORD" default:"password"`
+	Password string `envconfig:"REDIS_PASSWORD" default:"password" secret:"true"`
+	// TLS enables TLS of connections to Redis.
+	TLS bool `envconfig:"REDIS_TLS"`
+	// TLSCAFile is path of CA certificates of Redis, system CAs are
+	// trusted if it is empty.
+	TLSCAFile string `envconfig:"REDIS_TLS_CA_FILE"`
+	// TLSCertFile and TLSKeyFile are paths of client certificate and key
+	// presented to Redis, if they are not empty.
+	TLSCertFile string `envconfig:"REDIS_TLS_CERT_FILE"`
+	TLSKeyFile  string `envconfig:"REDIS_TLS_KEY_FILE"`
+}
+
+// GeoIP is configuration for GeoIP database.
+type GeoIP struct {
+	// Database is path to MaxMind-format database file, GeoIP lookups
+	// are disabled if it is empty.
+	Database string `envconfig:"GEOIP_DATABASE"`
+}
+
+// Metrics is configuration for Prometheus metrics.
+type Metrics struct {
+	// Addr is address of HTTP server exposing metrics at /metrics, they
+	// are not exposed if it is empty.
+	Addr string `envconfig:"METRICS_ADDR" default:":9090"`
 }
 
-// Get creates Config singleton instance and returns it.
-func Get() Config {
-	once.Do(func() {
-		if err := envconfig.Process("", &cfg); err != nil {
-			zap.L().Fatal(err.Error())
+// Tracing is configuration for OpenTelemetry tracing.
+type Tracing struct {
+	// Exporter is exporter of spans, otlp or stdout, spans are not
+	// exported if it is empty.
+	Exporter string `envconfig:"TRACING_EXPORTER"`
+	// Endpoint is address of OTLP gRPC collector.
+	Endpoint string `envconfig:"TRACING_ENDPOINT" default:"localhost:4317"`
+	// Insecure disables TLS of connection to OTLP collector.
+	Insecure bool `envconfig:"TRACING_INSECURE"`
+	// SampleRatio is the ratio of sampled traces not sampled by callers.
+	SampleRatio float64 `envconfig:"TRACING_SAMPLE_RATIO" default:"1"`
+	ServiceName string  `envconfig:"TRACING_SERVICE_NAME" default:"tinee"`
+}
+
+// Logging is configuration for logging.
+type Logging struct {
+	// Level is min level of logged entries, debug, info, warn or error.
+	Level string `envconfig:"LOG_LEVEL" default:"info"`
+	// Format is format of logged entries, json or console.
+	Format string `envconfig:"LOG_FORMAT" default:"json"`
+	// SamplingInitial is number of entries with the same level and
+	// message logged every second before sampling starts, sampling is
+	// disabled if it is zero.
+	SamplingInitial int `envconfig:"LOG_SAMPLING_INITIAL" default:"100"`
+	// SamplingThereafter is sampling rate of entries, every n-th of them
+	// is logged.
+	SamplingThereafter int `envconfig:"LOG_SAMPLING_THEREAFTER" default:"100"`
+}
+
+// Health is configuration for health checks.
+type Health struct {
+	// Timeout is timeout of pings of dependencies.
+	Timeout time.Duration `envconfig:"HEALTH_TIMEOUT" default:"1s"`
+	// WatchInterval is interval of checks of gRPC health watches.
+	WatchInterval time.Duration `envconfig:"HEALTH_WATCH_INTERVAL" default:"5s"`
+	// DrainDelay is time tinee reports it is not ready before servers are
+	// shut down, so it is removed from load balancing.
+	DrainDelay time.Duration `envconfig:"HEALTH_DRAIN_DELAY" default:"5s"`
+}
+
+// Lifecycle is configuration for start and stop of components.
+type Lifecycle struct {
+	// StartTimeout is timeout of start of all components.
+	StartTimeout time.Duration `envconfig:"LIFECYCLE_START_TIMEOUT" default:"30s"`
+	// StopTimeout is timeout of stop of a component without its own.
+	StopTimeout time.Duration `envconfig:"LIFECYCLE_STOP_TIMEOUT" default:"10s"`
+}
+
+// Load loads Config from configuration file at path, if it is not empty,
+// env variables overriding it and secret files. Settings set in neither of them have
+// default values. Returned error is ValidationError if Config is invalid.
+func Load(path string) (Config, error) {
+	var cfg Config
+	if err := envconfig.Process("", &cfg); err != nil {
+		return Config{}, err
+	}
+
+	if path != "" {
+		env := cfg
+		if err := decodeFile(path, &cfg); err != nil {
+			return Config{}, err
 		}
-	})
+		override(reflect.ValueOf(&cfg).Elem(), reflect.ValueOf(env))
+	}
+
+	if err := readSecretFiles(reflect.ValueOf(&cfg).Elem()); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
 
-	return cfg
+	return cfg, nil
 }
@@ -0,0 +1,280 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestLoad(t *testing.T) {
+	testcases := []struct {
+		name   string
+		file   string
+		data   string
+		env    map[string]string
+		check  func(is *is.I, cfg Config)
+		expErr error
+	}{
+		{
+			name: "defaults without file",
+			check: func(is *is.I, cfg Config) {
+				is.Equal(":8080", cfg.HTTPServer.Addr)
+				is.Equal("info", cfg.Logging.Level)
+				is.Equal(5*time.Second, cfg.Health.DrainDelay)
+			},
+		},
+		{
+			name: "YAML file with env overrides",
+			file: "tinee.yaml",
+			data: `
+httpserver:
+  addr: ":8000"
+logging:
+  level: debug
+  format: console
+health:
+  draindelay: 1s
+`,
+			env: map[string]string{"LOG_LEVEL": "warn"},
+			check: func(is *is.I, cfg Config) {
+				is.Equal(":8000", cfg.HTTPServer.Addr)
+				is.Equal("warn", cfg.Logging.Level)
+				is.Equal("console", cfg.Logging.Format)
+				is.Equal(time.Second, cfg.Health.DrainDelay)
+				is.Equal(":8081", cfg.GRPCServer.Addr) // default is kept
+			},
+		},
+		{
+			name: "TOML file with env overrides",
+			file: "tinee.toml",
+			data: `
+[service]
+domain = "tinee.xx"
+maxBatchSize = 10
+
+[health]
+drainDelay = "1s"
+`,
+			env: map[string]string{"SERVICE_MAX_BATCH_SIZE": "20"},
+			check: func(is *is.I, cfg Config) {
+				is.Equal("tinee.xx", cfg.Service.Domain)
+				is.Equal(20, cfg.Service.MaxBatchSize)
+				is.Equal(time.Second, cfg.Health.DrainDelay)
+			},
+		},
+		{
+			name: "empty file",
+			file: "tinee.yml",
+			data: "",
+			check: func(is *is.I, cfg Config) {
+				is.Equal(":8080", cfg.HTTPServer.Addr)
+			},
+		},
+		{
+			name:   "unknown file format",
+			file:   "tinee.json",
+			data:   "{}",
+			expErr: ErrUnknownFileFormat,
+		},
+		{
+			name: "invalid settings",
+			file: "tinee.yaml",
+			data: `
+logging:
+  level: verbose
+tracing:
+  sampleratio: 2
+`,
+			env: map[string]string{"HTTPSERVER_ADDR": "", "HTTPSERVER_TRUSTED_PROXIES": "10.0.0.0/8, proxy"},
+			expErr: ValidationError{
+				"HTTPSERVER_ADDR: must not be empty",
+				`HTTPSERVER_TRUSTED_PROXIES: must be comma-separated IPs or CIDRs, got "10.0.0.0/8, proxy"`,
+				"TRACING_SAMPLE_RATIO: must be between 0 and 1, got 2",
+				`LOG_LEVEL: must be debug, info, warn or error, got "verbose"`,
+			},
+		},
+		{
+			name: "incomplete TLS settings",
+			env: map[string]string{
+				"HTTPSERVER_TLS_KEY_FILE":       "tinee.key",
+				"GRPCSERVER_TLS_CLIENT_CA_FILE": "ca.crt",
+				"GRPCSERVER_GATEWAY_ADDR":       ":8080",
+				"REDIS_TLS_CA_FILE":             "ca.crt",
+			},
+			expErr: ValidationError{
+				"HTTPSERVER_TLS_CERT_FILE: must be set with HTTPSERVER_TLS_KEY_FILE",
+				"GRPCSERVER_TLS_CLIENT_CA_FILE: requires GRPCSERVER_TLS_CERT_FILE to be set",
+				"GRPCSERVER_GATEWAY_ADDR: must differ from HTTPSERVER_ADDR",
+				"REDIS_TLS: must be enabled if TLS files are set",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			setenv(t, "TINEE_PROFILE", ProfileDev)
+			for k, v := range tc.env {
+				setenv(t, k, v)
+			}
+			var path string
+			if tc.file != "" {
+				path = filepath.Join(t.TempDir(), tc.file)
+				is.NoErr(os.WriteFile(path, []byte(tc.data), 0o600))
+			}
+
+			cfg, err := Load(path)
+
+			var verr ValidationError
+			if errors.As(err, &verr) {
+				is.Equal(tc.expErr, verr)
+			} else {
+				is.Equal(tc.expErr, err)
+			}
+			if tc.check != nil {
+				tc.check(is, cfg)
+			}
+		})
+	}
+}
+
+func TestLoad_UnknownKey(t *testing.T) {
+	testcases := []struct {
+		name string
+		file string
+		data string
+	}{
+		{
+			name: "YAML",
+			file: "tinee.yaml",
+			data: "httpserver:\n  adress: \":8000\"\n",
+		},
+		{
+			name: "TOML",
+			file: "tinee.toml",
+			data: "[httpserver]\nadress = \":8000\"\n",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			path := filepath.Join(t.TempDir(), tc.file)
+			is.NoErr(os.WriteFile(path, []byte(tc.data), 0o600))
+
+			_, err := Load(path)
+
+			is.True(err != nil) // typo is not ignored
+		})
+	}
+}
+
+func TestLoad_Secrets(t *testing.T) {
+	testcases := []struct {
+		name        string
+		profile     string
+		env         map[string]string
+		secretFiles map[string]string
+		expMongo    string
+		expRedis    string
+		expErr      error
+	}{
+		{
+			name:     "default credentials in dev profile",
+			profile:  ProfileDev,
+			expMongo: "password",
+			expRedis: "password",
+		},
+		{
+			name:    "default credentials in production profile",
+			profile: ProfileProduction,
+			env:     map[string]string{"MONGO_PASSWORD": "password"},
+			expErr: ValidationError{
+				"MONGO_PASSWORD: must not be default outside dev profile",
+				"REDIS_PASSWORD: must not be default outside dev profile",
+			},
+		},
+		{
+			name:        "secrets from files",
+			profile:     ProfileProduction,
+			env:         map[string]string{"REDIS_PASSWORD": "xxxx"},
+			secretFiles: map[string]string{"MONGO_PASSWORD": "mongo\n"},
+			expMongo:    "mongo",
+			expRedis:    "xxxx",
+		},
+		{
+			name:        "secret from both env and file",
+			profile:     ProfileDev,
+			env:         map[string]string{"MONGO_PASSWORD": "xxxx"},
+			secretFiles: map[string]string{"MONGO_PASSWORD": "mongo"},
+			expErr:      errors.New("MONGO_PASSWORD and MONGO_PASSWORD_FILE are both set"),
+		},
+		{
+			name:    "unknown profile",
+			profile: "staging",
+			env:     map[string]string{"MONGO_PASSWORD": "xxxx", "REDIS_PASSWORD": "xxxx"},
+			expErr:  ValidationError{`TINEE_PROFILE: must be dev or production, got "staging"`},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			setenv(t, "TINEE_PROFILE", tc.profile)
+			for k, v := range tc.env {
+				setenv(t, k, v)
+			}
+			for k, v := range tc.secretFiles {
+				path := filepath.Join(t.TempDir(), k)
+				is.NoErr(os.WriteFile(path, []byte(v), 0o600))
+				setenv(t, k+"_FILE", path)
+			}
+
+			cfg, err := Load("")
+
+			if tc.expErr != nil {
+				is.Equal(tc.expErr.Error(), err.Error())
+				return
+			}
+			is.NoErr(err)
+			is.Equal(tc.expMongo, cfg.MongoDB.Password)
+			is.Equal(tc.expRedis, cfg.Redis.Password)
+		})
+	}
+}
+
+func TestConfig_Redact(t *testing.T) {
+	is := is.New(t)
+	cfg := Config{
+		MongoDB:    MongoDB{Username: "root", Password: "xxxx"},
+		HTTPServer: HTTPServer{Addr: ":8080"},
+		Redis:      Redis{Password: "yyyy"},
+	}
+
+	r := cfg.Redact()
+
+	is.Equal(Redacted, r.MongoDB.Password)
+	is.Equal(Redacted, r.Redis.Password)
+	is.Equal("", r.HTTPServer.AdminToken) // empty secret is not redacted
+	is.Equal("root", r.MongoDB.Username)
+	is.Equal("xxxx", cfg.MongoDB.Password) // original is not changed
+}
+
+// setenv sets env variable for the test.
+func setenv(t *testing.T, key, value string) {
+	prev, ok := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if ok {
+			_ = os.Setenv(key, prev)
+		} else {
+			_ = os.Unsetenv(key)
+		}
+	})
+}
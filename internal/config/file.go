@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnknownFileFormat is returned when configuration file is neither
+// YAML nor TOML file.
+var ErrUnknownFileFormat = errors.New("unknown configuration file format, expected .yaml, .yml or .toml")
+
+// decodeFile decodes YAML or TOML configuration file by its extension into
+// cfg. Unknown keys are errors, so that typos are not ignored.
+func decodeFile(path string, cfg *Config) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		dec := yaml.NewDecoder(f)
+		dec.KnownFields(true)
+		if err = dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("decoding %s: %w", path, err)
+		}
+	case ".toml":
+		md, err := toml.DecodeFile(path, cfg)
+		if err != nil {
+			return fmt.Errorf("decoding %s: %w", path, err)
+		}
+		if keys := md.Undecoded(); len(keys) > 0 {
+			return fmt.Errorf("decoding %s: unknown keys %v", path, keys)
+		}
+	default:
+		return ErrUnknownFileFormat
+	}
+
+	return nil
+}
+
+// override sets settings of dst, which are set by env variables, to values
+// of env.
+func override(dst, env reflect.Value) {
+	for i := 0; i < dst.NumField(); i++ {
+		f := dst.Type().Field(i)
+		if f.Type.Kind() == reflect.Struct {
+			override(dst.Field(i), env.Field(i))
+			continue
+		}
+
+		if _, ok := os.LookupEnv(f.Tag.Get("envconfig")); ok {
+			dst.Field(i).Set(env.Field(i))
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Redacted replaces values of secret settings in redacted Config.
+const Redacted = "[REDACTED]"
+
+// Redact returns a copy of Config with secret settings replaced by
+// Redacted, so that it can be logged.
+func (c Config) Redact() Config {
+	secrets(reflect.ValueOf(&c).Elem(), func(f reflect.StructField, v reflect.Value) {
+		if v.String() != "" {
+			v.SetString(Redacted)
+		}
+	})
+
+	return c
+}
+
+// readSecretFiles sets secret settings to contents of files at paths of
+// their env variables with _FILE suffix. Trailing newlines are trimmed.
+func readSecretFiles(cfg reflect.Value) (err error) {
+	secrets(cfg, func(f reflect.StructField, v reflect.Value) {
+		name := f.Tag.Get("envconfig")
+		path, ok := os.LookupEnv(name + "_FILE")
+		if !ok || err != nil {
+			return
+		}
+		if _, ok := os.LookupEnv(name); ok {
+			err = fmt.Errorf("%s and %s_FILE are both set", name, name)
+			return
+		}
+
+		b, rerr := os.ReadFile(path)
+		if rerr != nil {
+			err = fmt.Errorf("reading %s_FILE: %w", name, rerr)
+			return
+		}
+		v.SetString(strings.TrimRight(string(b), "\r\n"))
+	})
+
+	return err
+}
+
+// defaultSecrets returns env variables of secret settings with their
+// default values.
+func defaultSecrets(cfg Config) []string {
+	var names []string
+	secrets(reflect.ValueOf(&cfg).Elem(), func(f reflect.StructField, v reflect.Value) {
+		if def, ok := f.Tag.Lookup("default"); ok && v.String() == def {
+			names = append(names, f.Tag.Get("envconfig"))
+		}
+	})
+
+	return names
+}
+
+// secrets calls fn with every secret setting of cfg.
+func secrets(cfg reflect.Value, fn func(f reflect.StructField, v reflect.Value)) {
+	for i := 0; i < cfg.NumField(); i++ {
+		f := cfg.Type().Field(i)
+		if f.Type.Kind() == reflect.Struct {
+			secrets(cfg.Field(i), fn)
+		} else if f.Tag.Get("secret") == "true" {
+			fn(f, cfg.Field(i))
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// ValidationError is returned when Config is invalid, it lists all
+// problems of Config by env variables of invalid settings.
+type ValidationError []string
+
+func (e ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e, "; ")
+}
+
+// Validate validates Config, it returns ValidationError if it is invalid.
+func (c Config) Validate() error {
+	var v validator
+
+	v.check(c.Profile == ProfileDev || c.Profile == ProfileProduction,
+		"TINEE_PROFILE", "must be dev or production, got %q", c.Profile)
+	if c.Profile != ProfileDev {
+		for _, name := range defaultSecrets(c) {
+			v.check(false, name, "must not be default outside dev profile")
+		}
+	}
+
+	v.check(c.Service.Domain != "", "SERVICE_DOMAIN", "must not be empty")
+	v.check(c.Service.BatchWorkers > 0, "SERVICE_BATCH_WORKERS", "must be positive, got %d", c.Service.BatchWorkers)
+	v.check(c.Service.MaxBatchSize >= 0, "SERVICE_MAX_BATCH_SIZE", "must not be negative, got %d", c.Service.MaxBatchSize)
+
+	v.check(c.MongoDB.URL != "", "MONGO_URL", "must not be empty")
+	v.check(c.MongoDB.DbName != "", "MONGO_DBNAME", "must not be empty")
+	v.pair(c.MongoDB.TLSCertFile, c.MongoDB.TLSKeyFile, "MONGO_TLS_CERT_FILE", "MONGO_TLS_KEY_FILE")
+	v.check(c.MongoDB.TLS || (c.MongoDB.TLSCAFile == "" && c.MongoDB.TLSCertFile == ""),
+		"MONGO_TLS", "must be enabled if TLS files are set")
+
+	v.check(c.HTTPServer.Addr != "", "HTTPSERVER_ADDR", "must not be empty")
+	v.positive(c.HTTPServer.ShutdownTimeout, "HTTPSERVER_SHUTDOWN_TIMEOUT")
+	v.pair(c.HTTPServer.TLSCertFile, c.HTTPServer.TLSKeyFile, "HTTPSERVER_TLS_CERT_FILE", "HTTPSERVER_TLS_KEY_FILE")
+	_, err := c.HTTPServer.Proxies()
+	v.check(err == nil, "HTTPSERVER_TRUSTED_PROXIES", "must be comma-separated IPs or CIDRs, got %q", c.HTTPServer.TrustedProxies)
+	v.check(c.GRPCServer.Addr != "", "GRPCSERVER_ADDR", "must not be empty")
+	v.positive(c.GRPCServer.ShutdownTimeout, "GRPCSERVER_SHUTDOWN_TIMEOUT")
+	v.pair(c.GRPCServer.TLSCertFile, c.GRPCServer.TLSKeyFile, "GRPCSERVER_TLS_CERT_FILE", "GRPCSERVER_TLS_KEY_FILE")
+	v.check(c.GRPCServer.TLSClientCAFile == "" || c.GRPCServer.TLSCertFile != "",
+		"GRPCSERVER_TLS_CLIENT_CA_FILE", "requires GRPCSERVER_TLS_CERT_FILE to be set")
+	v.check(c.GRPCServer.GatewayAddr == "" || c.GRPCServer.GatewayAddr != c.HTTPServer.Addr,
+		"GRPCSERVER_GATEWAY_ADDR", "must differ from HTTPSERVER_ADDR")
+
+	v.check(c.Redis.Addr != "", "REDIS_ADDR", "must not be empty")
+	v.pair(c.Redis.TLSCertFile, c.Redis.TLSKeyFile, "REDIS_TLS_CERT_FILE", "REDIS_TLS_KEY_FILE")
+	v.check(c.Redis.TLS || (c.Redis.TLSCAFile == "" && c.Redis.TLSCertFile == ""),
+		"REDIS_TLS", "must be enabled if TLS files are set")
+
+	v.check(c.Tracing.Exporter == "" || c.Tracing.Exporter == "otlp" || c.Tracing.Exporter == "stdout",
+		"TRACING_EXPORTER", "must be otlp, stdout or empty, got %q", c.Tracing.Exporter)
+	v.check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1,
+		"TRACING_SAMPLE_RATIO", "must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+
+	var level zapcore.Level
+	v.check(level.Set(c.Logging.Level) == nil,
+		"LOG_LEVEL", "must be debug, info, warn or error, got %q", c.Logging.Level)
+	v.check(c.Logging.Format == "json" || c.Logging.Format == "console",
+		"LOG_FORMAT", "must be json or console, got %q", c.Logging.Format)
+	v.check(c.Logging.SamplingInitial >= 0, "LOG_SAMPLING_INITIAL", "must not be negative, got %d", c.Logging.SamplingInitial)
+	v.check(c.Logging.SamplingInitial == 0 || c.Logging.SamplingThereafter > 0,
+		"LOG_SAMPLING_THEREAFTER", "must be positive if sampling is enabled, got %d", c.Logging.SamplingThereafter)
+
+	v.positive(c.Health.Timeout, "HEALTH_TIMEOUT")
+	v.positive(c.Health.WatchInterval, "HEALTH_WATCH_INTERVAL")
+	v.check(c.Health.DrainDelay >= 0, "HEALTH_DRAIN_DELAY", "must not be negative, got %v", c.Health.DrainDelay)
+
+	v.positive(c.Lifecycle.StartTimeout, "LIFECYCLE_START_TIMEOUT")
+	v.positive(c.Lifecycle.StopTimeout, "LIFECYCLE_STOP_TIMEOUT")
+
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+
+	return nil
+}
+
+// validator collects problems of settings.
+type validator struct {
+	errs ValidationError
+}
+
+// check adds problem of setting if ok is false.
+func (v *validator) check(ok bool, setting, format string, args ...interface{}) {
+	if !ok {
+		v.errs = append(v.errs, setting+": "+fmt.Sprintf(format, args...))
+	}
+}
+
+// pair checks both or none of certificate and key files are set.
+func (v *validator) pair(certFile, keyFile, certSetting, keySetting string) {
+	v.check(certFile != "" || keyFile == "", certSetting, "must be set with %s", keySetting)
+	v.check(keyFile != "" || certFile == "", keySetting, "must be set with %s", certSetting)
+}
+
+// positive checks duration setting is positive.
+func (v *validator) positive(d time.Duration, setting string) {
+	v.check(d > 0, setting, "must be positive, got %v", d)
+}
@@ -0,0 +1,49 @@
+// Package gateway provides REST gateway of tinee gRPC API generated from
+// HTTP bindings of its protobuf definition.
+package gateway
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc"
+
+	"tinee/internal/logging"
+	"tinee/pkg/pb"
+)
+
+// New returns HTTP handler translating JSON/HTTP requests to gRPC calls
+// over conn. Fields of JSON messages are named as in protobuf definition,
+// and fields with default values are not omitted. Request ID is passed in
+// X-Request-ID header like it is by HTTP API.
+func New(ctx context.Context, conn *grpc.ClientConn) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{OrigName: true, EmitDefaults: true}),
+		runtime.WithIncomingHeaderMatcher(incomingHeader),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeader),
+	)
+	if err := pb.RegisterTineeURLHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+
+	return mux, nil
+}
+
+// incomingHeader maps HTTP header of request to gRPC metadata.
+func incomingHeader(key string) (string, bool) {
+	if key == http.CanonicalHeaderKey(logging.RequestIDHeader) {
+		return logging.RequestIDMetadata, true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// outgoingHeader maps gRPC header metadata to HTTP header of response.
+func outgoingHeader(key string) (string, bool) {
+	if key == logging.RequestIDMetadata {
+		return logging.RequestIDHeader, true
+	}
+
+	return runtime.MetadataHeaderPrefix + key, true
+}
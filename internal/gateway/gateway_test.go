@@ -0,0 +1,181 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"tinee/internal/logging"
+	"tinee/pkg/pb"
+)
+
+type mockServer struct {
+	pb.UnimplementedTineeURLServer
+	shorten    func(ctx context.Context, req *pb.ShortenRequest) (*pb.ShortenResponse, error)
+	urlByAlias func(ctx context.Context, req *pb.UrlByAliasRequest) (*pb.UrlByAliasResponse, error)
+}
+
+func (s *mockServer) Shorten(ctx context.Context, req *pb.ShortenRequest) (*pb.ShortenResponse, error) {
+	return s.shorten(ctx, req)
+}
+
+func (s *mockServer) UrlByAlias(ctx context.Context, req *pb.UrlByAliasRequest) (*pb.UrlByAliasResponse, error) {
+	return s.urlByAlias(ctx, req)
+}
+
+// newGateway returns gateway of gRPC server serving srv over in-memory
+// connection.
+func newGateway(t *testing.T, srv pb.TineeURLServer) http.Handler {
+	t.Helper()
+	l := bufconn.Listen(1 << 16)
+	s := grpc.NewServer()
+	pb.RegisterTineeURLServer(s, srv)
+	go func() { _ = s.Serve(l) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufconn", grpc.WithInsecure(), grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return l.DialContext(ctx)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	gw, err := New(context.Background(), conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return gw
+}
+
+func TestGateway_Shorten(t *testing.T) {
+	testcases := []struct {
+		name      string
+		body      string
+		err       error
+		expStatus int
+		expBody   map[string]interface{}
+	}{
+		{
+			name:      "URL is shortened",
+			body:      `{"url": "https://x.xx", "alias": "xxxx"}`,
+			expStatus: http.StatusOK,
+			expBody:   map[string]interface{}{"tinee_url": "tinee.io/xxxx"},
+		},
+		{
+			name:      "invalid argument",
+			body:      `{"url": "x"}`,
+			err:       status.Error(codes.InvalidArgument, "invalid URL"),
+			expStatus: http.StatusBadRequest,
+			expBody:   map[string]interface{}{"code": float64(codes.InvalidArgument), "message": "invalid URL", "error": "invalid URL", "details": []interface{}{}},
+		},
+		{
+			name:      "alias is taken",
+			body:      `{"url": "https://x.xx", "alias": "xxxx"}`,
+			err:       status.Error(codes.AlreadyExists, "alias is taken"),
+			expStatus: http.StatusConflict,
+			expBody:   map[string]interface{}{"code": float64(codes.AlreadyExists), "message": "alias is taken", "error": "alias is taken", "details": []interface{}{}},
+		},
+		{
+			name:      "malformed JSON",
+			body:      `{"url":`,
+			expStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			expErr := tc.err
+			gw := newGateway(t, &mockServer{
+				shorten: func(ctx context.Context, req *pb.ShortenRequest) (*pb.ShortenResponse, error) {
+					if expErr != nil {
+						return nil, expErr
+					}
+					return &pb.ShortenResponse{TineeUrl: "tinee.io/" + req.GetAlias()}, nil
+				},
+			})
+			w := httptest.NewRecorder()
+
+			gw.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/links", strings.NewReader(tc.body)))
+
+			is.Equal(tc.expStatus, w.Code)
+			is.Equal("application/json", w.Header().Get("Content-Type"))
+			if tc.expBody != nil {
+				var body map[string]interface{}
+				is.NoErr(json.Unmarshal(w.Body.Bytes(), &body))
+				is.Equal(tc.expBody, body)
+			}
+		})
+	}
+}
+
+func TestGateway_UrlByAlias(t *testing.T) {
+	testcases := []struct {
+		name      string
+		err       error
+		expStatus int
+	}{
+		{name: "URL is found", err: nil, expStatus: http.StatusOK},
+		{name: "link not found", err: status.Error(codes.NotFound, "link not found"), expStatus: http.StatusNotFound},
+		{name: "internal error", err: status.Error(codes.Internal, "internal error"), expStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			expErr := tc.err
+			var alias string
+			gw := newGateway(t, &mockServer{
+				urlByAlias: func(ctx context.Context, req *pb.UrlByAliasRequest) (*pb.UrlByAliasResponse, error) {
+					alias = req.GetAlias()
+					if expErr != nil {
+						return nil, expErr
+					}
+					return &pb.UrlByAliasResponse{Url: "https://x.xx"}, nil
+				},
+			})
+			w := httptest.NewRecorder()
+
+			gw.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/urls/xxxx", nil))
+
+			is.Equal(tc.expStatus, w.Code)
+			is.Equal("xxxx", alias) // alias is taken from path
+		})
+	}
+}
+
+func TestGateway_RequestID(t *testing.T) {
+	is := is.New(t)
+	var ID string
+	gw := newGateway(t, &mockServer{
+		urlByAlias: func(ctx context.Context, req *pb.UrlByAliasRequest) (*pb.UrlByAliasResponse, error) {
+			md, _ := metadata.FromIncomingContext(ctx)
+			if IDs := md.Get(logging.RequestIDMetadata); len(IDs) > 0 {
+				ID = IDs[0]
+			}
+			_ = grpc.SetHeader(ctx, metadata.Pairs(logging.RequestIDMetadata, ID))
+			return &pb.UrlByAliasResponse{}, nil
+		},
+	})
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/v1/urls/xxxx", nil)
+	r.Header.Set(logging.RequestIDHeader, "xxxx")
+
+	gw.ServeHTTP(w, r)
+
+	is.Equal("xxxx", ID)
+	is.Equal("xxxx", w.Header().Get(logging.RequestIDHeader))
+}
@@ -0,0 +1,50 @@
+// Package geoip resolves countries of IP addresses with MaxMind-format
+// GeoIP database.
+package geoip
+
+import (
+	"net"
+
+	"github.com/oschwald/maxminddb-golang"
+
+	"tinee/internal/config"
+)
+
+// DB represents GeoIP database.
+type DB struct {
+	cfg    config.GeoIP
+	reader *maxminddb.Reader
+}
+
+// Open opens the database file and returns DB instance.
+func Open(cfg config.GeoIP) (*DB, error) {
+	reader, err := maxminddb.Open(cfg.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DB{cfg: cfg, reader: reader}, nil
+}
+
+// record is the part of GeoIP database record with country.
+type record struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+}
+
+// Country returns ISO 3166-1 alpha-2 code of the country of IP address.
+// Empty string is returned if the country is unknown.
+func (db *DB) Country(ip net.IP) (string, error) {
+	var r record
+	if err := db.reader.Lookup(ip, &r); err != nil {
+		return "", err
+	}
+
+	return r.Country.ISOCode, nil
+}
+
+// Close closes the database file.
+func (db *DB) Close() error {
+	return db.reader.Close()
+}
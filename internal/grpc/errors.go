@@ -0,0 +1,74 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"tinee/internal/logging"
+	"tinee/internal/qrcode"
+	"tinee/internal/service"
+)
+
+// statusError maps service error to gRPC status error. Invalid fields of
+// requests are described by BadRequest details, unexpected errors are
+// logged and hidden behind Internal status.
+func statusError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if err == service.ErrInvalidURL {
+		return badRequest(err, "url")
+	} else if err == service.ErrInvalidAlias {
+		return badRequest(err, "alias")
+	} else if err == service.ErrInvalidVariant {
+		return badRequest(err, "variants")
+	} else if err == service.ErrInvalidCursor {
+		return badRequest(err, "page_token")
+	} else if err == service.ErrInvalidListQuery || err == service.ErrBatchTooLarge || err == qrcode.ErrInvalidOptions {
+		return status.Error(codes.InvalidArgument, err.Error())
+	} else if err == service.ErrLinkNotFound {
+		return status.Error(codes.NotFound, err.Error())
+	} else if err == service.ErrVersionConflict {
+		return status.Error(codes.Aborted, err.Error())
+	} else if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	logging.FromContext(ctx).Error(err.Error())
+	return status.Error(codes.Internal, "internal error")
+}
+
+// shortenError maps error of shortening URL with alias to gRPC status
+// error. Valid custom alias is invalid only if it is taken by another link,
+// and generated alias is invalid if it collided with existing one.
+func shortenError(ctx context.Context, s Service, alias string, err error) error {
+	if err == service.ErrInvalidAlias {
+		if alias == "" {
+			return status.Error(codes.Aborted, "generated alias is taken")
+		} else if s.ValidateCustomAlias(alias) == nil {
+			return status.Error(codes.AlreadyExists, "alias is taken")
+		}
+	}
+
+	return statusError(ctx, err)
+}
+
+// badRequest returns InvalidArgument status error of err with violation
+// of field.
+func badRequest(err error, field string) error {
+	st, derr := status.New(codes.InvalidArgument, err.Error()).WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: err.Error()}},
+	})
+	if derr != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return st.Err()
+}
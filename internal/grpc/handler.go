@@ -3,37 +3,239 @@ package grpc
 
 import (
 	"context"
+	"crypto/subtle"
+	"io"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"tinee/internal/config"
+	"tinee/internal/qrcode"
 	"tinee/internal/service"
 	"tinee/pkg/pb"
 )
 
+// ServiceName is full name of tinee gRPC service.
+const ServiceName = "tinee.TineeURL"
+
 // Service is tinee service interface.
 type Service interface {
 	Shorten(ctx context.Context, URL, alias string) (tineeURL string, err error)
+	ShortenBatch(ctx context.Context, items []service.ShortenItem) ([]service.ShortenResult, error)
 	LinkByAlias(ctx context.Context, alias string) (l service.Link, err error)
+	LinksByAliases(ctx context.Context, aliases []string) (map[string]service.Link, error)
+	SetVariants(ctx context.Context, alias string, variants []service.Variant) error
+	Variants(ctx context.Context, alias string) ([]service.VariantClicks, error)
+	QRCode(ctx context.Context, alias string, o qrcode.Options) ([]byte, error)
+	ListLinks(ctx context.Context, q service.ListQuery) (service.LinkPage, error)
+	ValidateCustomAlias(alias string) error
 }
 
 // Handler is gRPC handler.
 type Handler struct {
-	s Service
+	cfg config.GRPCServer
+	s   Service
 }
 
 // NewHandler creates and returns a new Handler instance.
-func NewHandler(s Service) *Handler {
-	return &Handler{s: s}
+func NewHandler(cfg config.GRPCServer, s Service) *Handler {
+	return &Handler{cfg: cfg, s: s}
 }
 
 // Shorten shortens URL.
 func (h *Handler) Shorten(ctx context.Context, r *pb.ShortenRequest) (*pb.ShortenResponse, error) {
 	tineeURL, err := h.s.Shorten(ctx, r.GetUrl(), r.GetAlias())
 
-	return &pb.ShortenResponse{TineeUrl: tineeURL}, err
+	return &pb.ShortenResponse{TineeUrl: tineeURL}, shortenError(ctx, h.s, r.GetAlias(), err)
+}
+
+// streamBatchSize is max number of URLs of shortening stream
+// shortened as a batch.
+const streamBatchSize = 100
+
+// ShortenStream shortens stream of URLs. Requests received while a batch
+// is shortened are shortened as the next batch.
+func (h *Handler) ShortenStream(stream pb.TineeURL_ShortenStreamServer) error {
+	ctx := stream.Context()
+	reqs := make(chan *pb.ShortenRequest, streamBatchSize)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(reqs)
+		for {
+			r, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					errs <- err
+				}
+				return
+			}
+
+			select {
+			case reqs <- r:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for r := range reqs {
+		items := []service.ShortenItem{{URL: r.GetUrl(), Alias: r.GetAlias()}}
+	batch:
+		for len(items) < streamBatchSize {
+			select {
+			case r, ok := <-reqs:
+				if !ok {
+					break batch
+				}
+				items = append(items, service.ShortenItem{URL: r.GetUrl(), Alias: r.GetAlias()})
+			default:
+				break batch
+			}
+		}
+
+		results, err := h.s.ShortenBatch(ctx, items)
+		if err != nil {
+			return statusError(ctx, err)
+		}
+		for i, res := range results {
+			resp := &pb.ShortenStreamResponse{TineeUrl: res.TineeURL}
+			if res.Err != nil {
+				// errors are described like errors of Shorten
+				resp.Error = status.Convert(shortenError(ctx, h.s, items[i].Alias, res.Err)).Message()
+			}
+			if err = stream.Send(resp); err != nil {
+				return err
+			}
+		}
+	}
+
+	select {
+	case err := <-errs:
+		return err
+	default:
+		return nil
+	}
 }
 
 // UrlByAlias returns URL that corresponds to alias in request.
 func (h *Handler) UrlByAlias(ctx context.Context, r *pb.UrlByAliasRequest) (*pb.UrlByAliasResponse, error) {
 	l, err := h.s.LinkByAlias(ctx, r.GetAlias())
 
-	return &pb.UrlByAliasResponse{Url: l.URL}, err
+	return &pb.UrlByAliasResponse{Url: l.URL}, statusError(ctx, err)
+}
+
+// BatchUrlByAlias returns URLs that correspond to aliases in request.
+func (h *Handler) BatchUrlByAlias(ctx context.Context, r *pb.BatchUrlByAliasRequest) (*pb.BatchUrlByAliasResponse, error) {
+	links, err := h.s.LinksByAliases(ctx, r.GetAliases())
+
+	resp := &pb.BatchUrlByAliasResponse{Urls: make(map[string]string, len(links))}
+	for alias, l := range links {
+		resp.Urls[alias] = l.URL
+	}
+
+	return resp, statusError(ctx, err)
+}
+
+// SetVariants sets weighted destinations of the link with alias in request.
+// It is admin method.
+func (h *Handler) SetVariants(ctx context.Context, r *pb.SetVariantsRequest) (*pb.SetVariantsResponse, error) {
+	if err := h.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	variants := make([]service.Variant, 0, len(r.GetVariants()))
+	for _, v := range r.GetVariants() {
+		variants = append(variants, service.Variant{
+			Name:   v.GetName(),
+			URL:    v.GetUrl(),
+			Weight: int(v.GetWeight()),
+		})
+	}
+
+	return &pb.SetVariantsResponse{}, statusError(ctx, h.s.SetVariants(ctx, r.GetAlias(), variants))
+}
+
+// Variants returns weighted destinations of the link with alias in request.
+func (h *Handler) Variants(ctx context.Context, r *pb.VariantsRequest) (*pb.VariantsResponse, error) {
+	variants, err := h.s.Variants(ctx, r.GetAlias())
+
+	resp := &pb.VariantsResponse{Variants: make([]*pb.Variant, 0, len(variants))}
+	for _, v := range variants {
+		resp.Variants = append(resp.Variants, &pb.Variant{
+			Name:   v.Name,
+			Url:    v.URL,
+			Weight: uint32(v.Weight),
+			Clicks: v.Clicks,
+		})
+	}
+
+	return resp, statusError(ctx, err)
+}
+
+// QRCode returns QR code of shortened URL with alias in request.
+func (h *Handler) QRCode(ctx context.Context, r *pb.QRCodeRequest) (*pb.QRCodeResponse, error) {
+	o := qrcode.Options{
+		Format:     r.GetFormat(),
+		Size:       int(r.GetSize()),
+		Level:      r.GetLevel(),
+		Foreground: r.GetForeground(),
+		Background: r.GetBackground(),
+		QuietZone:  r.GetQuietZone(),
+	}
+	b, err := h.s.QRCode(ctx, r.GetAlias(), o)
+
+	return &pb.QRCodeResponse{ContentType: o.ContentType(), Image: b}, statusError(ctx, err)
+}
+
+// ListLinks lists links matching filters in request page by page. It is
+// admin method.
+func (h *Handler) ListLinks(ctx context.Context, r *pb.ListLinksRequest) (*pb.ListLinksResponse, error) {
+	if err := h.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	page, err := h.s.ListLinks(ctx, service.ListQuery{
+		Domain:      r.GetDomain(),
+		AliasPrefix: r.GetAliasPrefix(),
+		Search:      r.GetSearch(),
+		Order:       service.SortOrder(r.GetOrder()),
+		Limit:       int(r.GetPageSize()),
+		Cursor:      r.GetPageToken(),
+	})
+
+	resp := &pb.ListLinksResponse{
+		Links:         make([]*pb.Link, 0, len(page.Links)),
+		NextPageToken: page.NextCursor,
+	}
+	for _, l := range page.Links {
+		resp.Links = append(resp.Links, &pb.Link{
+			Id:         l.ID,
+			Url:        l.URL,
+			Aliases:    l.Aliases,
+			CreateTime: timestamppb.New(l.CreatedAt),
+		})
+	}
+
+	return resp, statusError(ctx, err)
+}
+
+// requireAdmin returns status error unless authorization metadata of
+// the call is bearer admin token. Admin methods are unimplemented if
+// admin token is not configured.
+func (h *Handler) requireAdmin(ctx context.Context) error {
+	if h.cfg.AdminToken == "" {
+		return status.Error(codes.Unimplemented, "admin methods are disabled")
+	}
+
+	token := []byte("Bearer " + h.cfg.AdminToken)
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get("authorization") {
+		if subtle.ConstantTimeCompare([]byte(v), token) == 1 {
+			return nil
+		}
+	}
+
+	return status.Error(codes.Unauthenticated, "invalid admin token")
 }
@@ -0,0 +1,386 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/matryer/is"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"tinee/internal/config"
+	"tinee/internal/qrcode"
+	"tinee/internal/service"
+	"tinee/pkg/pb"
+)
+
+type mockService struct {
+	shorten             func(ctx context.Context, URL, alias string) (string, error)
+	shortenBatch        func(ctx context.Context, items []service.ShortenItem) ([]service.ShortenResult, error)
+	linkByAlias         func(ctx context.Context, alias string) (service.Link, error)
+	setVariants         func(ctx context.Context, alias string, variants []service.Variant) error
+	listLinks           func(ctx context.Context, q service.ListQuery) (service.LinkPage, error)
+	validateCustomAlias func(alias string) error
+}
+
+func (s *mockService) Shorten(ctx context.Context, URL, alias string) (string, error) {
+	return s.shorten(ctx, URL, alias)
+}
+
+func (s *mockService) ShortenBatch(ctx context.Context, items []service.ShortenItem) ([]service.ShortenResult, error) {
+	return s.shortenBatch(ctx, items)
+}
+
+func (s *mockService) LinkByAlias(ctx context.Context, alias string) (service.Link, error) {
+	return s.linkByAlias(ctx, alias)
+}
+
+func (s *mockService) LinksByAliases(ctx context.Context, aliases []string) (map[string]service.Link, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *mockService) SetVariants(ctx context.Context, alias string, variants []service.Variant) error {
+	return s.setVariants(ctx, alias, variants)
+}
+
+func (s *mockService) Variants(ctx context.Context, alias string) ([]service.VariantClicks, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *mockService) QRCode(ctx context.Context, alias string, o qrcode.Options) ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *mockService) ListLinks(ctx context.Context, q service.ListQuery) (service.LinkPage, error) {
+	return s.listLinks(ctx, q)
+}
+
+func (s *mockService) ValidateCustomAlias(alias string) error {
+	return s.validateCustomAlias(alias)
+}
+
+type mockShortenStream struct {
+	pb.TineeURL_ShortenStreamServer
+	reqs  []*pb.ShortenRequest
+	resps []*pb.ShortenStreamResponse
+}
+
+func (s *mockShortenStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *mockShortenStream) Recv() (*pb.ShortenRequest, error) {
+	if len(s.reqs) == 0 {
+		return nil, io.EOF
+	}
+	r := s.reqs[0]
+	s.reqs = s.reqs[1:]
+
+	return r, nil
+}
+
+func (s *mockShortenStream) Send(resp *pb.ShortenStreamResponse) error {
+	s.resps = append(s.resps, resp)
+	return nil
+}
+
+// fieldViolations returns fields violated according to details of status
+// error.
+func fieldViolations(err error) []string {
+	var fields []string
+	for _, d := range status.Convert(err).Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				fields = append(fields, v.GetField())
+			}
+		}
+	}
+
+	return fields
+}
+
+func TestHandler_Shorten(t *testing.T) {
+	validAlias := func(alias string) error {
+		if alias == "x" {
+			return service.ErrInvalidAlias
+		}
+		return nil
+	}
+	testcases := []struct {
+		name          string
+		alias         string
+		err           error
+		expCode       codes.Code
+		expMessage    string
+		expViolations []string
+	}{
+		{
+			name:    "URL is shortened",
+			alias:   "xxxx",
+			err:     nil,
+			expCode: codes.OK,
+		},
+		{
+			name:          "invalid URL",
+			err:           service.ErrInvalidURL,
+			expCode:       codes.InvalidArgument,
+			expMessage:    "invalid URL",
+			expViolations: []string{"url"},
+		},
+		{
+			name:          "invalid alias",
+			alias:         "x",
+			err:           service.ErrInvalidAlias,
+			expCode:       codes.InvalidArgument,
+			expMessage:    "invalid alias",
+			expViolations: []string{"alias"},
+		},
+		{
+			name:       "alias is taken",
+			alias:      "xxxx",
+			err:        service.ErrInvalidAlias,
+			expCode:    codes.AlreadyExists,
+			expMessage: "alias is taken",
+		},
+		{
+			name:       "generated alias is taken",
+			alias:      "",
+			err:        service.ErrInvalidAlias,
+			expCode:    codes.Aborted,
+			expMessage: "generated alias is taken",
+		},
+		{
+			name:       "unexpected error",
+			alias:      "xxxx",
+			err:        errors.New("connection refused"),
+			expCode:    codes.Internal,
+			expMessage: "internal error",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			h := NewHandler(config.GRPCServer{}, &mockService{
+				shorten: func(ctx context.Context, URL, alias string) (string, error) {
+					return "tinee.io/" + alias, tc.err
+				},
+				validateCustomAlias: validAlias,
+			})
+
+			_, err := h.Shorten(context.Background(), &pb.ShortenRequest{Url: "https://x.xx", Alias: tc.alias})
+
+			is.Equal(tc.expCode, status.Code(err))
+			is.Equal(tc.expMessage, status.Convert(err).Message())
+			is.Equal(tc.expViolations, fieldViolations(err))
+		})
+	}
+}
+
+func TestHandler_ShortenStream(t *testing.T) {
+	testcases := []struct {
+		name     string
+		alias    string
+		err      error
+		expError string
+	}{
+		{name: "URL is shortened", alias: "xxxx", err: nil, expError: ""},
+		{name: "invalid URL", alias: "xxxx", err: service.ErrInvalidURL, expError: "invalid URL"},
+		{name: "invalid alias", alias: "x", err: service.ErrInvalidAlias, expError: "invalid alias"},
+		{name: "alias is taken", alias: "xxxx", err: service.ErrInvalidAlias, expError: "alias is taken"},
+		{name: "generated alias is taken", alias: "", err: service.ErrInvalidAlias, expError: "generated alias is taken"},
+		{name: "unexpected error", alias: "xxxx", err: errors.New("connection refused"), expError: "internal error"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			expErr := tc.err
+			h := NewHandler(config.GRPCServer{}, &mockService{
+				shortenBatch: func(ctx context.Context, items []service.ShortenItem) ([]service.ShortenResult, error) {
+					results := make([]service.ShortenResult, len(items))
+					for i, item := range items {
+						results[i] = service.ShortenResult{TineeURL: "tinee.io/" + item.Alias}
+					}
+					results[len(results)-1].Err = expErr
+					return results, nil
+				},
+				validateCustomAlias: func(alias string) error {
+					if alias == "x" {
+						return service.ErrInvalidAlias
+					}
+					return nil
+				},
+			})
+			stream := &mockShortenStream{reqs: []*pb.ShortenRequest{{Url: "https://x.xx", Alias: tc.alias}}}
+
+			err := h.ShortenStream(stream)
+
+			is.NoErr(err)
+			is.Equal(1, len(stream.resps))
+			is.Equal(tc.expError, stream.resps[0].GetError())
+		})
+	}
+}
+
+func TestHandler_UrlByAlias(t *testing.T) {
+	testcases := []struct {
+		name    string
+		err     error
+		expCode codes.Code
+	}{
+		{name: "URL is found", err: nil, expCode: codes.OK},
+		{name: "link not found", err: service.ErrLinkNotFound, expCode: codes.NotFound},
+		{name: "request canceled", err: context.Canceled, expCode: codes.Canceled},
+		{name: "unexpected error", err: errors.New("connection refused"), expCode: codes.Internal},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			h := NewHandler(config.GRPCServer{}, &mockService{
+				linkByAlias: func(ctx context.Context, alias string) (service.Link, error) {
+					return service.Link{URL: "https://x.xx"}, tc.err
+				},
+			})
+
+			resp, err := h.UrlByAlias(context.Background(), &pb.UrlByAliasRequest{Alias: "xxxx"})
+
+			is.Equal(tc.expCode, status.Code(err))
+			if err == nil {
+				is.Equal("https://x.xx", resp.GetUrl())
+			}
+		})
+	}
+}
+
+func TestHandler_SetVariants(t *testing.T) {
+	testcases := []struct {
+		name    string
+		md      metadata.MD
+		err     error
+		expCode codes.Code
+	}{
+		{
+			name:    "variants are set",
+			md:      metadata.Pairs("authorization", "Bearer token"),
+			expCode: codes.OK,
+		},
+		{
+			name:    "invalid variant",
+			md:      metadata.Pairs("authorization", "Bearer token"),
+			err:     service.ErrInvalidVariant,
+			expCode: codes.InvalidArgument,
+		},
+		{
+			name:    "missing admin token",
+			expCode: codes.Unauthenticated,
+		},
+		{
+			name:    "invalid admin token",
+			md:      metadata.Pairs("authorization", "Bearer other"),
+			expCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			expErr := tc.err
+			h := NewHandler(config.GRPCServer{AdminToken: "token"}, &mockService{
+				setVariants: func(ctx context.Context, alias string, variants []service.Variant) error {
+					return expErr
+				},
+			})
+			ctx := metadata.NewIncomingContext(context.Background(), tc.md)
+
+			_, err := h.SetVariants(ctx, &pb.SetVariantsRequest{Alias: "xxxx", Variants: []*pb.Variant{{Name: "a", Url: "https://x.xx", Weight: 1}}})
+
+			is.Equal(tc.expCode, status.Code(err))
+		})
+	}
+}
+
+func TestHandler_ListLinks(t *testing.T) {
+	testcases := []struct {
+		name       string
+		adminToken string
+		md         metadata.MD
+		expCode    codes.Code
+	}{
+		{
+			name:       "links are listed",
+			adminToken: "token",
+			md:         metadata.Pairs("authorization", "Bearer token"),
+			expCode:    codes.OK,
+		},
+		{
+			name:       "missing admin token",
+			adminToken: "token",
+			expCode:    codes.Unauthenticated,
+		},
+		{
+			name:       "invalid admin token",
+			adminToken: "token",
+			md:         metadata.Pairs("authorization", "Bearer other"),
+			expCode:    codes.Unauthenticated,
+		},
+		{
+			name:    "admin methods are disabled",
+			md:      metadata.Pairs("authorization", "Bearer "),
+			expCode: codes.Unimplemented,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			h := NewHandler(config.GRPCServer{AdminToken: tc.adminToken}, &mockService{
+				listLinks: func(ctx context.Context, q service.ListQuery) (service.LinkPage, error) {
+					return service.LinkPage{Links: []service.Link{{ID: "x", URL: "https://x.xx"}}}, nil
+				},
+			})
+			ctx := metadata.NewIncomingContext(context.Background(), tc.md)
+
+			resp, err := h.ListLinks(ctx, &pb.ListLinksRequest{})
+
+			is.Equal(tc.expCode, status.Code(err))
+			if err == nil {
+				is.Equal(1, len(resp.GetLinks()))
+			}
+		})
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	testcases := []struct {
+		name          string
+		err           error
+		expCode       codes.Code
+		expViolations []string
+	}{
+		{name: "invalid variant", err: service.ErrInvalidVariant, expCode: codes.InvalidArgument, expViolations: []string{"variants"}},
+		{name: "invalid cursor", err: service.ErrInvalidCursor, expCode: codes.InvalidArgument, expViolations: []string{"page_token"}},
+		{name: "invalid list query", err: service.ErrInvalidListQuery, expCode: codes.InvalidArgument},
+		{name: "batch is too large", err: service.ErrBatchTooLarge, expCode: codes.InvalidArgument},
+		{name: "invalid QR code options", err: qrcode.ErrInvalidOptions, expCode: codes.InvalidArgument},
+		{name: "version conflict", err: service.ErrVersionConflict, expCode: codes.Aborted},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, expCode: codes.DeadlineExceeded},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			err := statusError(context.Background(), tc.err)
+
+			is.Equal(tc.expCode, status.Code(err))
+			is.Equal(tc.err.Error(), status.Convert(err).Message())
+			is.Equal(tc.expViolations, fieldViolations(err))
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Check implements gRPC health checking protocol, it reports the server
+// or the service is serving if tinee is ready.
+func (h *Health) Check(ctx context.Context, r *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	if !h.services[r.GetService()] {
+		return nil, status.Error(codes.NotFound, "unknown service")
+	}
+
+	return &grpc_health_v1.HealthCheckResponse{Status: h.servingStatus(ctx)}, nil
+}
+
+// Watch implements gRPC health checking protocol, it sends serving status
+// of the server or the service whenever it changes. Readiness is checked
+// every watch interval.
+func (h *Health) Watch(r *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	ctx := stream.Context()
+	t := time.NewTicker(h.cfg.WatchInterval)
+	defer t.Stop()
+
+	var last *grpc_health_v1.HealthCheckResponse_ServingStatus
+	for {
+		st := grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN
+		if h.services[r.GetService()] {
+			st = h.servingStatus(ctx)
+		}
+		if last == nil || st != *last {
+			if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: st}); err != nil {
+				return err
+			}
+			last = &st
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// servingStatus returns serving status of readiness of tinee.
+func (h *Health) servingStatus(ctx context.Context) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	if h.Status(ctx).Ready {
+		return grpc_health_v1.HealthCheckResponse_SERVING
+	}
+
+	return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+}
@@ -0,0 +1,105 @@
+// Package health provides liveness and readiness checks of tinee over HTTP
+// and gRPC health checking protocol.
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"tinee/internal/config"
+	"tinee/internal/logging"
+)
+
+// Pinger is a dependency of tinee checked by pinging it.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// StatusDraining is status of readiness while tinee shuts down.
+const StatusDraining = "draining"
+
+// StatusOK is status of a dependency that responded to ping.
+const StatusOK = "ok"
+
+// StatusUnavailable is status of a dependency that did not respond to
+// ping, and of readiness if any dependency is unavailable.
+const StatusUnavailable = "unavailable"
+
+// Status is readiness status of tinee.
+type Status struct {
+	Ready bool
+	// Checks are statuses of dependencies by name, StatusOK or
+	// StatusUnavailable. Errors of pings are logged, not reported.
+	Checks map[string]string
+}
+
+// Health checks liveness and readiness of tinee.
+type Health struct {
+	grpc_health_v1.UnimplementedHealthServer
+
+	cfg      config.Health
+	deps     map[string]Pinger
+	services map[string]bool
+	draining int32
+}
+
+// New creates and returns a new Health instance checking dependencies
+// by name. Readiness of gRPC services is reported under their names.
+func New(cfg config.Health, deps map[string]Pinger, services ...string) *Health {
+	h := &Health{cfg: cfg, deps: deps, services: map[string]bool{"": true}}
+	for _, s := range services {
+		h.services[s] = true
+	}
+
+	return h
+}
+
+// Drain marks tinee as not ready, it is called when shutdown starts.
+func (h *Health) Drain() {
+	atomic.StoreInt32(&h.draining, 1)
+}
+
+// Draining reports whether tinee shuts down.
+func (h *Health) Draining() bool {
+	return atomic.LoadInt32(&h.draining) == 1
+}
+
+// Status pings all dependencies concurrently and returns readiness status.
+func (h *Health) Status(ctx context.Context) Status {
+	ctx, cancel := context.WithTimeout(ctx, h.cfg.Timeout)
+	defer cancel()
+
+	st := Status{Ready: !h.Draining(), Checks: make(map[string]string, len(h.deps))}
+	names := make([]string, 0, len(h.deps))
+	for name := range h.deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, p Pinger) {
+			defer wg.Done()
+			errs[i] = p.Ping(ctx)
+		}(i, h.deps[name])
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		st.Checks[name] = StatusOK
+		if errs[i] != nil {
+			st.Ready = false
+			st.Checks[name] = StatusUnavailable
+			logging.FromContext(ctx).Warn("ping failed", zap.String("dependency", name), zap.Error(errs[i]))
+		}
+	}
+
+	return st
+}
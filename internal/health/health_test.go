@@ -0,0 +1,173 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"tinee/internal/config"
+)
+
+type mockPinger struct {
+	ping func(context.Context) error
+}
+
+func (p *mockPinger) Ping(ctx context.Context) error {
+	return p.ping(ctx)
+}
+
+var (
+	healthy = &mockPinger{ping: func(ctx context.Context) error {
+		return nil
+	}}
+	unhealthy = &mockPinger{ping: func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}}
+	hanging = &mockPinger{ping: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+)
+
+var cfg = config.Health{Timeout: 10 * time.Millisecond, WatchInterval: time.Second}
+
+func TestHealth_Readyz(t *testing.T) {
+	testcases := []struct {
+		name      string
+		deps      map[string]Pinger
+		draining  bool
+		expCode   int
+		expOutput StatusOutput
+	}{
+		{
+			name:    "dependencies are healthy",
+			deps:    map[string]Pinger{"mongodb": healthy, "redis": healthy},
+			expCode: http.StatusOK,
+			expOutput: StatusOutput{
+				Status: StatusOK,
+				Checks: map[string]string{"mongodb": StatusOK, "redis": StatusOK},
+			},
+		},
+		{
+			name:    "dependency is unhealthy",
+			deps:    map[string]Pinger{"mongodb": healthy, "redis": unhealthy},
+			expCode: http.StatusServiceUnavailable,
+			expOutput: StatusOutput{
+				Status: StatusUnavailable,
+				Checks: map[string]string{"mongodb": StatusOK, "redis": StatusUnavailable},
+			},
+		},
+		{
+			name:    "dependency does not respond",
+			deps:    map[string]Pinger{"mongodb": hanging},
+			expCode: http.StatusServiceUnavailable,
+			expOutput: StatusOutput{
+				Status: StatusUnavailable,
+				Checks: map[string]string{"mongodb": StatusUnavailable},
+			},
+		},
+		{
+			name:     "tinee is draining",
+			deps:     map[string]Pinger{"mongodb": healthy},
+			draining: true,
+			expCode:  http.StatusServiceUnavailable,
+			expOutput: StatusOutput{
+				Status: StatusDraining,
+				Checks: map[string]string{"mongodb": StatusOK},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			h := New(cfg, tc.deps)
+			if tc.draining {
+				h.Drain()
+			}
+
+			w := httptest.NewRecorder()
+			h.Readyz(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			is.Equal(tc.expCode, w.Code)
+			var o StatusOutput
+			is.NoErr(json.NewDecoder(w.Body).Decode(&o))
+			is.Equal(tc.expOutput, o)
+		})
+	}
+}
+
+func TestHealth_Healthz(t *testing.T) {
+	is := is.New(t)
+	h := New(cfg, map[string]Pinger{"mongodb": unhealthy})
+
+	w := httptest.NewRecorder()
+	h.Healthz(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	is.Equal(http.StatusOK, w.Code) // liveness does not depend on dependencies
+}
+
+func TestHealth_Check(t *testing.T) {
+	testcases := []struct {
+		name      string
+		deps      map[string]Pinger
+		draining  bool
+		service   string
+		expStatus grpc_health_v1.HealthCheckResponse_ServingStatus
+		expCode   codes.Code
+	}{
+		{
+			name:      "server is serving",
+			deps:      map[string]Pinger{"mongodb": healthy},
+			expStatus: grpc_health_v1.HealthCheckResponse_SERVING,
+		},
+		{
+			name:      "service is serving",
+			deps:      map[string]Pinger{"mongodb": healthy},
+			service:   "tinee.TineeURL",
+			expStatus: grpc_health_v1.HealthCheckResponse_SERVING,
+		},
+		{
+			name:      "dependency is unhealthy",
+			deps:      map[string]Pinger{"mongodb": unhealthy},
+			service:   "tinee.TineeURL",
+			expStatus: grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+		},
+		{
+			name:      "tinee is draining",
+			deps:      map[string]Pinger{"mongodb": healthy},
+			draining:  true,
+			expStatus: grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+		},
+		{
+			name:    "unknown service",
+			deps:    map[string]Pinger{"mongodb": healthy},
+			service: "tinee.Unknown",
+			expCode: codes.NotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			h := New(cfg, tc.deps, "tinee.TineeURL")
+			if tc.draining {
+				h.Drain()
+			}
+
+			resp, err := h.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: tc.service})
+
+			is.Equal(tc.expCode, status.Code(err))
+			is.Equal(tc.expStatus, resp.GetStatus())
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// StatusOutput is response DTO for health endpoints.
+type StatusOutput struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Healthz is liveness endpoint, it responds while tinee serves requests.
+func (h *Health) Healthz(w http.ResponseWriter, r *http.Request) {
+	respond(w, http.StatusOK, StatusOutput{Status: StatusOK})
+}
+
+// Readyz is readiness endpoint, it responds with 503 if any dependency
+// does not respond to ping or tinee shuts down.
+func (h *Health) Readyz(w http.ResponseWriter, r *http.Request) {
+	st := h.Status(r.Context())
+
+	o := StatusOutput{Status: StatusOK, Checks: st.Checks}
+	code := http.StatusOK
+	if h.Draining() {
+		o.Status, code = StatusDraining, http.StatusServiceUnavailable
+	} else if !st.Ready {
+		o.Status, code = StatusUnavailable, http.StatusServiceUnavailable
+	}
+
+	respond(w, code, o)
+}
+
+// respond responds to request with JSON of data.
+func respond(w http.ResponseWriter, code int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+
+	_ = json.NewEncoder(w).Encode(data)
+}
@@ -3,34 +3,80 @@ package http
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi"
-	"go.uber.org/zap"
+	"golang.org/x/text/language"
 
+	"tinee/internal/config"
+	"tinee/internal/logging"
+	"tinee/internal/qrcode"
 	"tinee/internal/service"
+	"tinee/internal/transfer"
 )
 
 // Service is tinee service interface.
 type Service interface {
 	Shorten(ctx context.Context, URL, alias string) (tineeURL string, err error)
+	ShortenBatch(ctx context.Context, items []service.ShortenItem) ([]service.ShortenResult, error)
 	LinkByAlias(ctx context.Context, alias string) (l service.Link, err error)
+	LinksByAliases(ctx context.Context, aliases []string) (map[string]service.Link, error)
+	SetForwarding(ctx context.Context, alias string, f service.Forwarding) error
+	SetParams(ctx context.Context, alias string, params map[string]string) error
+	SetTargets(ctx context.Context, alias string, targets []service.Target) error
+	SetVariants(ctx context.Context, alias string, variants []service.Variant) error
+	Variants(ctx context.Context, alias string) ([]service.VariantClicks, error)
+	CountVariant(ctx context.Context, linkID, variant string) error
+	QRCode(ctx context.Context, alias string, o qrcode.Options) ([]byte, error)
+	Export(ctx context.Context, fn func(service.Link) error) error
+	Import(ctx context.Context, next func() (service.Link, error), o service.ImportOptions) (service.ImportReport, error)
+	ListLinks(ctx context.Context, q service.ListQuery) (service.LinkPage, error)
+}
+
+// GeoIP is GeoIP database interface.
+type GeoIP interface {
+	Country(ip net.IP) (string, error)
 }
 
 // Handler is HTTP handler for tinee.
 type Handler struct {
-	r *chi.Mux
-	s Service
+	cfg     config.HTTPServer
+	r       *chi.Mux
+	s       Service
+	geo     GeoIP
+	proxies []*net.IPNet
 }
 
 // NewHandler creates and returns a new Handler instance.
-func NewHandler(s Service) *Handler {
-	h := &Handler{r: chi.NewRouter(), s: s}
+// Country targeting is disabled if geo is nil.
+func NewHandler(cfg config.HTTPServer, s Service, geo GeoIP) *Handler {
+	// trusted proxies were validated with configuration
+	proxies, _ := cfg.Proxies()
+	h := &Handler{cfg: cfg, r: chi.NewRouter(), s: s, geo: geo, proxies: proxies}
 
-	h.r.Post("/api/v1/shorten", LogResponseTime(h.Shorten))
-	h.r.Get("/{alias}", LogResponseTime(h.Redirect))
+	h.r.Post("/api/v1/shorten", h.Shorten)
+	h.r.Post("/api/v1/shorten/batch", h.ShortenBatch)
+	h.r.Post("/api/v1/resolve", h.Resolve)
+	h.r.Get("/api/v1/links/{alias}/variants", h.Variants)
+	if cfg.AdminToken != "" {
+		h.r.Put("/api/v1/links/{alias}/forwarding", h.RequireAdmin(h.SetForwarding))
+		h.r.Put("/api/v1/links/{alias}/params", h.RequireAdmin(h.SetParams))
+		h.r.Put("/api/v1/links/{alias}/targets", h.RequireAdmin(h.SetTargets))
+		h.r.Put("/api/v1/links/{alias}/variants", h.RequireAdmin(h.SetVariants))
+		h.r.Get("/api/v1/links", h.RequireAdmin(h.ListLinks))
+		h.r.Get("/admin/links/export", h.RequireAdmin(h.Export))
+		h.r.Post("/admin/links/import", h.RequireAdmin(h.Import))
+	}
+	h.r.Get("/{alias}/qr", h.QRCode)
+	h.r.Get("/{alias}", h.Redirect)
+	h.r.Get("/{alias}/*", h.Redirect)
 
 	return h
 }
@@ -77,35 +123,619 @@ func (h *Handler) Shorten(w http.ResponseWriter, r *http.Request) {
 			"error": err.Error(),
 		})
 	} else if err != nil {
-		zap.L().Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		h.respond(w, http.StatusInternalServerError, nil)
 	} else {
 		h.respond(w, http.StatusOK, ShortenOutput{TineeURL: tineeURL})
 	}
 }
 
+// ShortenBatchOutput is response DTO for a URL of batch shortening endpoint.
+type ShortenBatchOutput struct {
+	TineeURL string `json:"tineeUrl,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ShortenBatch is endpoint for shortening many URLs at once.
+func (h *Handler) ShortenBatch(w http.ResponseWriter, r *http.Request) {
+	var i []ShortenInput
+	if err := json.NewDecoder(r.Body).Decode(&i); err != nil {
+		h.respond(w, http.StatusBadRequest, map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	items := make([]service.ShortenItem, 0, len(i))
+	for _, item := range i {
+		items = append(items, service.ShortenItem{URL: item.URL, Alias: item.Alias})
+	}
+
+	results, err := h.s.ShortenBatch(r.Context(), items)
+	if err == service.ErrBatchTooLarge {
+		h.respond(w, http.StatusBadRequest, map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	} else if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		h.respond(w, http.StatusInternalServerError, nil)
+		return
+	}
+
+	o := make([]ShortenBatchOutput, 0, len(results))
+	for _, res := range results {
+		if res.Err == service.ErrInvalidURL || res.Err == service.ErrInvalidAlias {
+			o = append(o, ShortenBatchOutput{Error: res.Err.Error()})
+		} else if res.Err != nil {
+			logging.FromContext(r.Context()).Error(res.Err.Error())
+			o = append(o, ShortenBatchOutput{Error: http.StatusText(http.StatusInternalServerError)})
+		} else {
+			o = append(o, ShortenBatchOutput{TineeURL: res.TineeURL})
+		}
+	}
+	h.respond(w, http.StatusOK, o)
+}
+
+// ResolveInput is request DTO for resolving endpoint.
+type ResolveInput struct {
+	Aliases []string `json:"aliases"`
+}
+
+// ResolveOutput is response DTO for resolving endpoint.
+type ResolveOutput struct {
+	URLs map[string]string `json:"urls"`
+}
+
+// Resolve is endpoint for resolving many aliases at once.
+func (h *Handler) Resolve(w http.ResponseWriter, r *http.Request) {
+	var i ResolveInput
+	if err := json.NewDecoder(r.Body).Decode(&i); err != nil {
+		h.respond(w, http.StatusBadRequest, map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	links, err := h.s.LinksByAliases(r.Context(), i.Aliases)
+	if err == service.ErrBatchTooLarge {
+		h.respond(w, http.StatusBadRequest, map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	} else if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		h.respond(w, http.StatusInternalServerError, nil)
+		return
+	}
+
+	o := ResolveOutput{URLs: make(map[string]string, len(links))}
+	for alias, l := range links {
+		o.URLs[alias] = l.URL
+	}
+	h.respond(w, http.StatusOK, o)
+}
+
+// LinkOutput is response DTO for a link of listing endpoint.
+type LinkOutput struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Aliases   []string  `json:"aliases"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ListLinksOutput is response DTO for listing endpoint.
+type ListLinksOutput struct {
+	Links      []LinkOutput `json:"links"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+}
+
+// ListLinks is endpoint for listing links page by page. Links are
+// filtered by domain, aliasPrefix and q (URL substring) query parameters
+// and sorted by creation time in order (asc or desc) query parameter.
+// Next page is requested with nextCursor of the previous one as cursor.
+// It is admin endpoint like export.
+func (h *Handler) ListLinks(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	lq := service.ListQuery{
+		Domain:      q.Get("domain"),
+		AliasPrefix: q.Get("aliasPrefix"),
+		Search:      q.Get("q"),
+		Order:       service.SortOrder(q.Get("order")),
+		Cursor:      q.Get("cursor"),
+	}
+	var err error
+	if limit := q.Get("limit"); limit != "" {
+		if lq.Limit, err = strconv.Atoi(limit); err != nil || lq.Limit <= 0 {
+			h.respond(w, http.StatusBadRequest, map[string]interface{}{
+				"error": service.ErrInvalidListQuery.Error(),
+			})
+			return
+		}
+	}
+
+	page, err := h.s.ListLinks(r.Context(), lq)
+	if err == service.ErrInvalidListQuery || err == service.ErrInvalidCursor {
+		h.respond(w, http.StatusBadRequest, map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	} else if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		h.respond(w, http.StatusInternalServerError, nil)
+		return
+	}
+
+	o := ListLinksOutput{Links: make([]LinkOutput, 0, len(page.Links)), NextCursor: page.NextCursor}
+	for _, l := range page.Links {
+		o.Links = append(o.Links, LinkOutput{ID: l.ID, URL: l.URL, Aliases: l.Aliases, CreatedAt: l.CreatedAt})
+	}
+	h.respond(w, http.StatusOK, o)
+}
+
 // Redirect is endpoint for redirecting shortened URLs.
+// Destination is chosen by link targeting rules or sticky weighted
+// variant, path that follows the alias and query are forwarded to it
+// if the link opts in, and link parameter templates are expanded onto it.
 func (h *Handler) Redirect(w http.ResponseWriter, r *http.Request) {
 	alias := chi.URLParam(r, "alias")
 
 	l, err := h.s.LinkByAlias(r.Context(), alias)
 	if err == service.ErrLinkNotFound {
 		h.respond(w, http.StatusNotFound, nil)
+		return
+	} else if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		h.respond(w, http.StatusInternalServerError, nil)
+		return
+	}
+
+	v := h.visit(r, alias)
+	var assigned bool
+	if len(l.Variants) > 0 && !l.Targeted(v) {
+		v.Variant, assigned = h.variant(r, l)
+	}
+
+	dst, err := l.Destination(v)
+	if err == service.ErrLinkNotFound {
+		h.respond(w, http.StatusNotFound, nil)
+		return
+	} else if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		h.respond(w, http.StatusInternalServerError, nil)
+		return
+	}
+
+	// variant is counted and kept only if visitor is redirected to it
+	if v.Variant != "" {
+		if assigned {
+			setVariantCookie(w, l, v.Variant)
+		}
+		if err = h.s.CountVariant(r.Context(), l.ID, v.Variant); err != nil {
+			logging.FromContext(r.Context()).Warn(err.Error())
+		}
+	}
+	http.Redirect(w, r, dst, http.StatusSeeOther)
+}
+
+// visit forms service.Visit of the request.
+func (h *Handler) visit(r *http.Request, alias string) service.Visit {
+	v := service.Visit{
+		Alias:  alias,
+		Time:   time.Now(),
+		Path:   chi.URLParam(r, "*"),
+		Query:  r.URL.Query(),
+		Device: service.Device(r.UserAgent()),
+	}
+
+	if tags, _, err := language.ParseAcceptLanguage(r.Header.Get("Accept-Language")); err == nil && len(tags) > 0 {
+		v.Language = tags[0].String()
+	}
+
+	if h.geo != nil {
+		if ip := h.clientIP(r); ip != nil {
+			var err error
+			if v.Country, err = h.geo.Country(ip); err != nil {
+				logging.FromContext(r.Context()).Warn(err.Error())
+			}
+		}
+	}
+
+	return v
+}
+
+// clientIP returns IP address of the client of the request. If the peer
+// is trusted proxy, it is the last address of X-Forwarded-For header that
+// is not trusted proxy, or address of X-Real-IP header.
+func (h *Handler) clientIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if !h.trusted(ip) {
+		return ip
+	}
+
+	if fwd := r.Header.Values("X-Forwarded-For"); len(fwd) > 0 {
+		addrs := strings.Split(strings.Join(fwd, ","), ",")
+		for i := len(addrs) - 1; i >= 0; i-- {
+			if ip = net.ParseIP(strings.TrimSpace(addrs[i])); ip == nil || !h.trusted(ip) {
+				return ip
+			}
+		}
+		return ip
+	} else if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+		return net.ParseIP(strings.TrimSpace(realIP))
+	}
+
+	return ip
+}
+
+// trusted reports whether ip is address of trusted proxy.
+func (h *Handler) trusted(ip net.IP) bool {
+	for _, n := range h.proxies {
+		if ip != nil && n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// variantCookiePrefix is the prefix of cookies with assigned variants.
+const variantCookiePrefix = "tinee_variant_"
+
+// variantCookieMaxAge is max age of cookies with assigned variants.
+const variantCookieMaxAge = 30 * 24 * time.Hour
+
+// variant returns the name of link variant assigned to the visitor by
+// cookie, or picks a new one and reports it was assigned if there is none.
+func (h *Handler) variant(r *http.Request, l service.Link) (string, bool) {
+	if c, err := r.Cookie(variantCookiePrefix + l.ID); err == nil {
+		if v, ok := l.Variant(c.Value); ok {
+			return v.Name, false
+		}
+	}
+
+	return l.PickVariant().Name, true
+}
+
+// setVariantCookie sets cookie keeping variant assigned to the visitor.
+func setVariantCookie(w http.ResponseWriter, l service.Link, variant string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     variantCookiePrefix + l.ID,
+		Value:    variant,
+		Path:     "/",
+		MaxAge:   int(variantCookieMaxAge.Seconds()),
+		HttpOnly: true,
+	})
+}
+
+// ForwardingInput is request DTO for forwarding endpoint.
+type ForwardingInput struct {
+	Path  bool   `json:"path"`
+	Query string `json:"query"`
+}
+
+// SetForwarding is admin endpoint for setting path and query forwarding
+// of link.
+func (h *Handler) SetForwarding(w http.ResponseWriter, r *http.Request) {
+	var i ForwardingInput
+	if err := json.NewDecoder(r.Body).Decode(&i); err != nil {
+		h.respond(w, http.StatusBadRequest, map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.s.SetForwarding(r.Context(), chi.URLParam(r, "alias"), service.Forwarding{
+		Path:  i.Path,
+		Query: service.QueryPolicy(i.Query),
+	})
+	if err == service.ErrInvalidQueryPolicy {
+		h.respond(w, http.StatusBadRequest, map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else if err == service.ErrLinkNotFound {
+		h.respond(w, http.StatusNotFound, nil)
+	} else if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		h.respond(w, http.StatusInternalServerError, nil)
+	} else {
+		h.respond(w, http.StatusNoContent, nil)
+	}
+}
+
+// ParamsInput is request DTO for parameter templates endpoint.
+type ParamsInput map[string]string
+
+// SetParams is admin endpoint for setting query parameter templates of
+// link.
+func (h *Handler) SetParams(w http.ResponseWriter, r *http.Request) {
+	var i ParamsInput
+	if err := json.NewDecoder(r.Body).Decode(&i); err != nil {
+		h.respond(w, http.StatusBadRequest, map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.s.SetParams(r.Context(), chi.URLParam(r, "alias"), i)
+	if err == service.ErrInvalidParams {
+		h.respond(w, http.StatusBadRequest, map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else if err == service.ErrLinkNotFound {
+		h.respond(w, http.StatusNotFound, nil)
+	} else if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		h.respond(w, http.StatusInternalServerError, nil)
+	} else {
+		h.respond(w, http.StatusNoContent, nil)
+	}
+}
+
+// TargetInput is request DTO for targeting rule.
+type TargetInput struct {
+	URL       string   `json:"url"`
+	Devices   []string `json:"devices"`
+	Languages []string `json:"languages"`
+	Countries []string `json:"countries"`
+}
+
+// SetTargets is admin endpoint for setting targeting rules of link.
+func (h *Handler) SetTargets(w http.ResponseWriter, r *http.Request) {
+	var i []TargetInput
+	if err := json.NewDecoder(r.Body).Decode(&i); err != nil {
+		h.respond(w, http.StatusBadRequest, map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	targets := make([]service.Target, 0, len(i))
+	for _, t := range i {
+		targets = append(targets, service.Target{
+			URL:       t.URL,
+			Devices:   t.Devices,
+			Languages: t.Languages,
+			Countries: t.Countries,
+		})
+	}
+
+	err := h.s.SetTargets(r.Context(), chi.URLParam(r, "alias"), targets)
+	if err == service.ErrInvalidTarget {
+		h.respond(w, http.StatusBadRequest, map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else if err == service.ErrLinkNotFound {
+		h.respond(w, http.StatusNotFound, nil)
+	} else if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		h.respond(w, http.StatusInternalServerError, nil)
+	} else {
+		h.respond(w, http.StatusNoContent, nil)
+	}
+}
+
+// VariantInput is request DTO for variant.
+type VariantInput struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
+// VariantOutput is response DTO for variant.
+type VariantOutput struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+	Clicks int64  `json:"clicks"`
+}
+
+// SetVariants is admin endpoint for setting weighted destinations of link.
+func (h *Handler) SetVariants(w http.ResponseWriter, r *http.Request) {
+	var i []VariantInput
+	if err := json.NewDecoder(r.Body).Decode(&i); err != nil {
+		h.respond(w, http.StatusBadRequest, map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	variants := make([]service.Variant, 0, len(i))
+	for _, v := range i {
+		variants = append(variants, service.Variant{Name: v.Name, URL: v.URL, Weight: v.Weight})
+	}
+
+	err := h.s.SetVariants(r.Context(), chi.URLParam(r, "alias"), variants)
+	if err == service.ErrInvalidVariant {
+		h.respond(w, http.StatusBadRequest, map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else if err == service.ErrLinkNotFound {
+		h.respond(w, http.StatusNotFound, nil)
+	} else if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		h.respond(w, http.StatusInternalServerError, nil)
+	} else {
+		h.respond(w, http.StatusNoContent, nil)
+	}
+}
+
+// Variants is endpoint for reporting weighted destinations of link
+// and their clicks.
+func (h *Handler) Variants(w http.ResponseWriter, r *http.Request) {
+	variants, err := h.s.Variants(r.Context(), chi.URLParam(r, "alias"))
+	if err == service.ErrLinkNotFound {
+		h.respond(w, http.StatusNotFound, nil)
+		return
 	} else if err != nil {
-		zap.L().Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
+		h.respond(w, http.StatusInternalServerError, nil)
+		return
+	}
+
+	o := make([]VariantOutput, 0, len(variants))
+	for _, v := range variants {
+		o = append(o, VariantOutput{Name: v.Name, URL: v.URL, Weight: v.Weight, Clicks: v.Clicks})
+	}
+	h.respond(w, http.StatusOK, o)
+}
+
+// QRCode is endpoint for QR codes of shortened URLs. It is served at qr
+// path after the alias, so the path is not forwarded to destinations.
+func (h *Handler) QRCode(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	o := qrcode.Options{
+		Format:     q.Get("format"),
+		Level:      q.Get("level"),
+		Foreground: q.Get("fg"),
+		Background: q.Get("bg"),
+	}
+	var err error
+	if size := q.Get("size"); size != "" {
+		if o.Size, err = strconv.Atoi(size); err != nil || o.Size <= 0 {
+			h.respond(w, http.StatusBadRequest, map[string]interface{}{
+				"error": qrcode.ErrInvalidOptions.Error(),
+			})
+			return
+		}
+	}
+	if quietZone := q.Get("quietZone"); quietZone != "" {
+		if o.QuietZone, err = strconv.ParseBool(quietZone); err != nil {
+			h.respond(w, http.StatusBadRequest, map[string]interface{}{
+				"error": qrcode.ErrInvalidOptions.Error(),
+			})
+			return
+		}
+	}
+
+	b, err := h.s.QRCode(r.Context(), chi.URLParam(r, "alias"), o)
+	if err == qrcode.ErrInvalidOptions {
+		h.respond(w, http.StatusBadRequest, map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else if err == service.ErrLinkNotFound {
+		h.respond(w, http.StatusNotFound, nil)
+	} else if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
 		h.respond(w, http.StatusInternalServerError, nil)
 	} else {
-		http.Redirect(w, r, l.URL, http.StatusSeeOther)
+		w.Header().Set("Content-Type", o.ContentType())
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(b)
+	}
+}
+
+// Export is admin endpoint for streaming export of all links.
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	enc, err := transfer.NewEncoder(w, format)
+	if err != nil {
+		h.respond(w, http.StatusBadRequest, map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", transfer.ContentType(format))
+	w.Header().Set("Content-Disposition", "attachment; filename=links."+format)
+	if err = h.s.Export(r.Context(), enc.Encode); err == nil {
+		err = enc.Flush()
+	}
+	if err != nil {
+		// response is already partially written, so the error is only logged
+		logging.FromContext(r.Context()).Error(err.Error())
 	}
 }
 
-// LogResponseTime is middleware for logging request execution time.
-func LogResponseTime(next http.HandlerFunc) http.HandlerFunc {
+// ImportOutput is response DTO for import endpoint.
+type ImportOutput struct {
+	Imported int           `json:"imported"`
+	Skipped  int           `json:"skipped"`
+	Failed   int           `json:"failed"`
+	Errors   []ImportError `json:"errors,omitempty"`
+	// Conflicts are skipped records conflicting with existing links.
+	Conflicts []ImportError `json:"conflicts,omitempty"`
+	DryRun    bool          `json:"dryRun,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// ImportError is response DTO for error of an imported record.
+type ImportError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// Import is admin endpoint for importing links, including exports
+// of other URL shorteners. Conflicts with existing links are handled
+// according to conflict query parameter, one of skip (default), overwrite
+// or fail, links of other URL shorteners are always merged into existing
+// links with the same URL. Links are only validated if dryRun query
+// parameter is true.
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	dec, err := transfer.NewDecoder(r.Body, q.Get("format"))
+	if err != nil {
+		h.respond(w, http.StatusBadRequest, map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	opts := service.ImportOptions{
+		Conflict: service.ConflictPolicy(q.Get("conflict")),
+		Foreign:  transfer.Foreign(q.Get("format")),
+	}
+	if opts.Conflict == "" {
+		opts.Conflict = service.ConflictSkip
+	}
+	if dryRun := q.Get("dryRun"); dryRun != "" {
+		if opts.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			h.respond(w, http.StatusBadRequest, map[string]interface{}{
+				"error": "invalid dryRun",
+			})
+			return
+		}
+	}
+
+	report, err := h.s.Import(r.Context(), dec.Decode, opts)
+	o := ImportOutput{
+		Imported: report.Imported,
+		Skipped:  report.Skipped,
+		Failed:   report.Failed,
+		DryRun:   opts.DryRun,
+	}
+	for _, e := range report.Errors {
+		o.Errors = append(o.Errors, ImportError{Row: e.Row, Error: e.Err.Error()})
+	}
+	for _, e := range report.Conflicts {
+		o.Conflicts = append(o.Conflicts, ImportError{Row: e.Row, Error: e.Err.Error()})
+	}
+
+	code := http.StatusOK
+	if err == service.ErrInvalidConflictPolicy || errors.Is(err, transfer.ErrMalformedInput) {
+		code, o.Error = http.StatusBadRequest, err.Error()
+	} else if errors.Is(err, service.ErrConflict) {
+		code, o.Error = http.StatusConflict, err.Error()
+	} else if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		code, o.Error = http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)
+	}
+	h.respond(w, code, o)
+}
+
+// RequireAdmin is middleware that allows only requests with admin token.
+func (h *Handler) RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
+		token := []byte("Bearer " + h.cfg.AdminToken)
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), token) != 1 {
+			h.respond(w, http.StatusUnauthorized, nil)
+			return
+		}
 
 		next.ServeHTTP(w, r)
-
-		zap.S().Infof("request: %s, took: %v", r.URL, time.Since(start))
 	}
 }
@@ -5,29 +5,101 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/matryer/is"
 
+	"tinee/internal/config"
+	"tinee/internal/qrcode"
 	"tinee/internal/service"
 )
 
 type mockService struct {
-	shorten     func(ctx context.Context, URL, alias string) (tineeURL string, err error)
-	linkByAlias func(ctx context.Context, alias string) (l service.Link, err error)
+	shorten        func(ctx context.Context, URL, alias string) (tineeURL string, err error)
+	shortenBatch   func(ctx context.Context, items []service.ShortenItem) ([]service.ShortenResult, error)
+	linkByAlias    func(ctx context.Context, alias string) (l service.Link, err error)
+	linksByAliases func(ctx context.Context, aliases []string) (map[string]service.Link, error)
+	setForwarding  func(ctx context.Context, alias string, f service.Forwarding) error
+	setParams      func(ctx context.Context, alias string, params map[string]string) error
+	setTargets     func(ctx context.Context, alias string, targets []service.Target) error
+	setVariants    func(ctx context.Context, alias string, variants []service.Variant) error
+	variants       func(ctx context.Context, alias string) ([]service.VariantClicks, error)
+	countVariant   func(ctx context.Context, linkID, variant string) error
+	qrCode         func(ctx context.Context, alias string, o qrcode.Options) ([]byte, error)
+	export         func(ctx context.Context, fn func(service.Link) error) error
+	importLinks    func(ctx context.Context, next func() (service.Link, error), o service.ImportOptions) (service.ImportReport, error)
+	listLinks      func(ctx context.Context, q service.ListQuery) (service.LinkPage, error)
 }
 
 func (s *mockService) Shorten(ctx context.Context, URL, alias string) (string, error) {
 	return s.shorten(ctx, URL, alias)
 }
 
+func (s *mockService) ShortenBatch(ctx context.Context, items []service.ShortenItem) ([]service.ShortenResult, error) {
+	return s.shortenBatch(ctx, items)
+}
+
 func (s *mockService) LinkByAlias(ctx context.Context, alias string) (l service.Link, err error) {
 	return s.linkByAlias(ctx, alias)
 }
 
+func (s *mockService) LinksByAliases(ctx context.Context, aliases []string) (map[string]service.Link, error) {
+	return s.linksByAliases(ctx, aliases)
+}
+
+func (s *mockService) SetForwarding(ctx context.Context, alias string, f service.Forwarding) error {
+	return s.setForwarding(ctx, alias, f)
+}
+
+func (s *mockService) SetParams(ctx context.Context, alias string, params map[string]string) error {
+	return s.setParams(ctx, alias, params)
+}
+
+func (s *mockService) SetTargets(ctx context.Context, alias string, targets []service.Target) error {
+	return s.setTargets(ctx, alias, targets)
+}
+
+func (s *mockService) SetVariants(ctx context.Context, alias string, variants []service.Variant) error {
+	return s.setVariants(ctx, alias, variants)
+}
+
+func (s *mockService) Variants(ctx context.Context, alias string) ([]service.VariantClicks, error) {
+	return s.variants(ctx, alias)
+}
+
+func (s *mockService) CountVariant(ctx context.Context, linkID, variant string) error {
+	return s.countVariant(ctx, linkID, variant)
+}
+
+func (s *mockService) QRCode(ctx context.Context, alias string, o qrcode.Options) ([]byte, error) {
+	return s.qrCode(ctx, alias, o)
+}
+
+func (s *mockService) Export(ctx context.Context, fn func(service.Link) error) error {
+	return s.export(ctx, fn)
+}
+
+func (s *mockService) ListLinks(ctx context.Context, q service.ListQuery) (service.LinkPage, error) {
+	return s.listLinks(ctx, q)
+}
+
+func (s *mockService) Import(ctx context.Context, next func() (service.Link, error), o service.ImportOptions) (service.ImportReport, error) {
+	return s.importLinks(ctx, next, o)
+}
+
+type mockGeoIP struct {
+	country func(ip net.IP) (string, error)
+}
+
+func (g *mockGeoIP) Country(ip net.IP) (string, error) {
+	return g.country(ip)
+}
+
 func TestHandler_Shorten(t *testing.T) {
 	testcases := []struct {
 		name    string
@@ -100,7 +172,7 @@ func TestHandler_Shorten(t *testing.T) {
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			is := is.New(t)
-			h := NewHandler(tc.s)
+			h := NewHandler(config.HTTPServer{}, tc.s, nil)
 
 			r := httptest.NewRequest(http.MethodPost, "/api/v1/shorten", bytes.NewBufferString(tc.body))
 			rr := httptest.NewRecorder()
@@ -112,12 +184,153 @@ func TestHandler_Shorten(t *testing.T) {
 	}
 }
 
-func TestHandler_Redirect(t *testing.T) {
+func TestHandler_ShortenBatch(t *testing.T) {
+	testcases := []struct {
+		name    string
+		s       Service
+		body    string
+		expCode int
+		expBody string
+	}{
+		{
+			name: "URLs are shortened with per item results",
+			s: &mockService{
+				shortenBatch: func(ctx context.Context, items []service.ShortenItem) ([]service.ShortenResult, error) {
+					if len(items) != 3 || items[1].Alias != "xxxx" {
+						return nil, errors.New("unexpected arguments")
+					}
+
+					return []service.ShortenResult{
+						{TineeURL: "tinee.io/xxxxxxxx"},
+						{Err: service.ErrInvalidAlias},
+						{Err: errors.New("unexpected error")},
+					}, nil
+				},
+			},
+			body:    `[{"url":"https://x.xx"},{"url":"https://y.yy","alias":"xxxx"},{"url":"https://z.zz"}]`,
+			expCode: http.StatusOK,
+			expBody: `[{"tineeUrl":"tinee.io/xxxxxxxx"},{"error":"invalid alias"},{"error":"Internal Server Error"}]`,
+		},
+		{
+			name:    "empty request body",
+			expCode: http.StatusBadRequest,
+			expBody: `{"error":"EOF"}`,
+		},
+		{
+			name: "batch is too large",
+			s: &mockService{
+				shortenBatch: func(ctx context.Context, items []service.ShortenItem) ([]service.ShortenResult, error) {
+					return nil, service.ErrBatchTooLarge
+				},
+			},
+			body:    `[{"url":"https://x.xx"}]`,
+			expCode: http.StatusBadRequest,
+			expBody: `{"error":"batch is too large"}`,
+		},
+		{
+			name: "unexpected error",
+			s: &mockService{
+				shortenBatch: func(ctx context.Context, items []service.ShortenItem) ([]service.ShortenResult, error) {
+					return nil, errors.New("unexpected error")
+				},
+			},
+			body:    `[{"url":"https://x.xx"}]`,
+			expCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			h := NewHandler(config.HTTPServer{}, tc.s, nil)
+
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/shorten/batch", bytes.NewBufferString(tc.body))
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, r)
+
+			is.Equal(tc.expCode, rr.Code)
+			is.Equal(tc.expBody, strings.TrimSpace(rr.Body.String()))
+		})
+	}
+}
+
+func TestHandler_Resolve(t *testing.T) {
 	testcases := []struct {
 		name    string
 		s       Service
+		body    string
 		expCode int
-		expURL  string
+		expBody string
+	}{
+		{
+			name: "aliases are resolved",
+			s: &mockService{
+				linksByAliases: func(ctx context.Context, aliases []string) (map[string]service.Link, error) {
+					if len(aliases) != 2 {
+						return nil, errors.New("unexpected arguments")
+					}
+
+					return map[string]service.Link{"xxxx": {URL: "https://x.xx"}}, nil
+				},
+			},
+			body:    `{"aliases":["xxxx","yyyy"]}`,
+			expCode: http.StatusOK,
+			expBody: `{"urls":{"xxxx":"https://x.xx"}}`,
+		},
+		{
+			name:    "empty request body",
+			expCode: http.StatusBadRequest,
+			expBody: `{"error":"EOF"}`,
+		},
+		{
+			name: "batch is too large",
+			s: &mockService{
+				linksByAliases: func(ctx context.Context, aliases []string) (map[string]service.Link, error) {
+					return nil, service.ErrBatchTooLarge
+				},
+			},
+			body:    `{"aliases":["xxxx","yyyy"]}`,
+			expCode: http.StatusBadRequest,
+			expBody: `{"error":"batch is too large"}`,
+		},
+		{
+			name: "unexpected error",
+			s: &mockService{
+				linksByAliases: func(ctx context.Context, aliases []string) (map[string]service.Link, error) {
+					return nil, errors.New("unexpected error")
+				},
+			},
+			body:    `{"aliases":["xxxx"]}`,
+			expCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			h := NewHandler(config.HTTPServer{}, tc.s, nil)
+
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/resolve", bytes.NewBufferString(tc.body))
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, r)
+
+			is.Equal(tc.expCode, rr.Code)
+			is.Equal(tc.expBody, strings.TrimSpace(rr.Body.String()))
+		})
+	}
+}
+
+func TestHandler_Redirect(t *testing.T) {
+	testcases := []struct {
+		name      string
+		s         Service
+		geo       GeoIP
+		target    string
+		header    http.Header
+		cookies   []*http.Cookie
+		expCode   int
+		expURL    string
+		expCookie string
 	}{
 		{
 			name: "tineeURL redirected to actual URL",
@@ -126,9 +339,205 @@ func TestHandler_Redirect(t *testing.T) {
 					return service.Link{URL: "https://x.xx"}, nil
 				},
 			},
+			target:  "/alias",
+			expCode: http.StatusSeeOther,
+			expURL:  "https://x.xx",
+		},
+		{
+			name: "query is dropped by default",
+			s: &mockService{
+				linkByAlias: func(ctx context.Context, alias string) (l service.Link, err error) {
+					return service.Link{URL: "https://x.xx?a=1"}, nil
+				},
+			},
+			target:  "/alias?ref=x",
+			expCode: http.StatusSeeOther,
+			expURL:  "https://x.xx?a=1",
+		},
+		{
+			name: "path and query are forwarded",
+			s: &mockService{
+				linkByAlias: func(ctx context.Context, alias string) (l service.Link, err error) {
+					return service.Link{
+						URL:        "https://x.xx/docs/?ref=y",
+						Forwarding: service.Forwarding{Path: true, Query: service.QueryKeep},
+					}, nil
+				},
+			},
+			target:  "/alias/getting-started?ref=x&utm=z",
+			expCode: http.StatusSeeOther,
+			expURL:  "https://x.xx/docs/getting-started?ref=y&utm=z",
+		},
+		{
+			name: "paths under qr path are forwarded",
+			s: &mockService{
+				linkByAlias: func(ctx context.Context, alias string) (l service.Link, err error) {
+					return service.Link{URL: "https://x.xx/docs", Forwarding: service.Forwarding{Path: true}}, nil
+				},
+			},
+			target:  "/alias/qr/scan",
+			expCode: http.StatusSeeOther,
+			expURL:  "https://x.xx/docs/qr/scan",
+		},
+		{
+			name: "query is forwarded with override policy",
+			s: &mockService{
+				linkByAlias: func(ctx context.Context, alias string) (l service.Link, err error) {
+					return service.Link{
+						URL:        "https://x.xx?ref=y",
+						Forwarding: service.Forwarding{Query: service.QueryOverride},
+					}, nil
+				},
+			},
+			target:  "/alias?ref=x",
+			expCode: http.StatusSeeOther,
+			expURL:  "https://x.xx?ref=x",
+		},
+		{
+			name: "params are expanded",
+			s: &mockService{
+				linkByAlias: func(ctx context.Context, alias string) (l service.Link, err error) {
+					return service.Link{
+						URL:    "https://x.xx",
+						Params: map[string]string{"utm_source": "tinee", "utm_campaign": "{alias}"},
+					}, nil
+				},
+			},
+			target:  "/alias",
+			expCode: http.StatusSeeOther,
+			expURL:  "https://x.xx?utm_campaign=alias&utm_source=tinee",
+		},
+		{
+			name: "device target is chosen",
+			s: &mockService{
+				linkByAlias: func(ctx context.Context, alias string) (l service.Link, err error) {
+					return service.Link{
+						URL: "https://x.xx",
+						Targets: []service.Target{
+							{URL: "https://apps.apple.com/x", Devices: []string{service.DeviceIOS}},
+							{URL: "https://play.google.com/x", Devices: []string{service.DeviceAndroid}},
+						},
+					}, nil
+				},
+			},
+			target:  "/alias",
+			header:  http.Header{"User-Agent": {"Mozilla/5.0 (Linux; Android 12; Pixel 6)"}},
+			expCode: http.StatusSeeOther,
+			expURL:  "https://play.google.com/x",
+		},
+		{
+			name: "language and country target is chosen",
+			s: &mockService{
+				linkByAlias: func(ctx context.Context, alias string) (l service.Link, err error) {
+					return service.Link{
+						URL: "https://x.xx",
+						Targets: []service.Target{
+							{URL: "https://x.xx/fr", Languages: []string{"fr"}},
+							{URL: "https://x.xx/de-ch", Languages: []string{"de"}, Countries: []string{"CH"}},
+						},
+					}, nil
+				},
+			},
+			geo: &mockGeoIP{
+				country: func(ip net.IP) (string, error) {
+					return "CH", nil
+				},
+			},
+			target:  "/alias",
+			header:  http.Header{"Accept-Language": {"en;q=0.5, de-CH"}},
+			expCode: http.StatusSeeOther,
+			expURL:  "https://x.xx/de-ch",
+		},
+		{
+			name: "default URL is chosen without matching target",
+			s: &mockService{
+				linkByAlias: func(ctx context.Context, alias string) (l service.Link, err error) {
+					return service.Link{
+						URL:     "https://x.xx",
+						Targets: []service.Target{{URL: "https://x.xx/de", Countries: []string{"DE"}}},
+					}, nil
+				},
+			},
+			geo: &mockGeoIP{
+				country: func(ip net.IP) (string, error) {
+					return "", errors.New("unexpected error")
+				},
+			},
+			target:  "/alias",
 			expCode: http.StatusSeeOther,
 			expURL:  "https://x.xx",
 		},
+		{
+			name: "sticky variant is chosen by cookie",
+			s: &mockService{
+				linkByAlias: func(ctx context.Context, alias string) (l service.Link, err error) {
+					return service.Link{
+						ID:  "x-x-x-x",
+						URL: "https://x.xx",
+						Variants: []service.Variant{
+							{Name: "aaaa", URL: "https://x.xx/a", Weight: 1000},
+							{Name: "bbbb", URL: "https://x.xx/b", Weight: 1},
+						},
+					}, nil
+				},
+				countVariant: func(ctx context.Context, linkID, variant string) error {
+					if variant != "bbbb" {
+						return errors.New("unexpected variant")
+					}
+
+					return nil
+				},
+			},
+			target:  "/alias",
+			cookies: []*http.Cookie{{Name: "tinee_variant_x-x-x-x", Value: "bbbb"}},
+			expCode: http.StatusSeeOther,
+			expURL:  "https://x.xx/b",
+		},
+		{
+			name: "variant is assigned",
+			s: &mockService{
+				linkByAlias: func(ctx context.Context, alias string) (l service.Link, err error) {
+					return service.Link{
+						ID:       "x-x-x-x",
+						URL:      "https://x.xx",
+						Variants: []service.Variant{{Name: "aaaa", URL: "https://x.xx/a", Weight: 1}},
+					}, nil
+				},
+				countVariant: func(ctx context.Context, linkID, variant string) error {
+					return errors.New("unexpected error")
+				},
+			},
+			target:    "/alias",
+			cookies:   []*http.Cookie{{Name: "tinee_variant_x-x-x-x", Value: "removed"}},
+			expCode:   http.StatusSeeOther,
+			expURL:    "https://x.xx/a",
+			expCookie: "tinee_variant_x-x-x-x=aaaa",
+		},
+		{
+			name: "path is not forwarded without opt in",
+			s: &mockService{
+				linkByAlias: func(ctx context.Context, alias string) (l service.Link, err error) {
+					return service.Link{URL: "https://x.xx"}, nil
+				},
+			},
+			target:  "/alias/getting-started",
+			expCode: http.StatusNotFound,
+		},
+		{
+			name: "variant is not counted nor assigned without redirect",
+			s: &mockService{
+				linkByAlias: func(ctx context.Context, alias string) (l service.Link, err error) {
+					return service.Link{
+						ID:       "x-x-x-x",
+						URL:      "https://x.xx",
+						Variants: []service.Variant{{Name: "aaaa", URL: "https://x.xx/a", Weight: 1}},
+					}, nil
+				},
+				// countVariant is not set, the call would panic
+			},
+			target:  "/alias/getting-started",
+			expCode: http.StatusNotFound,
+		},
 		{
 			name: "link not found",
 			s: &mockService{
@@ -136,6 +545,7 @@ func TestHandler_Redirect(t *testing.T) {
 					return service.Link{}, service.ErrLinkNotFound
 				},
 			},
+			target:  "/alias",
 			expCode: http.StatusNotFound,
 		},
 		{
@@ -145,6 +555,7 @@ func TestHandler_Redirect(t *testing.T) {
 					return service.Link{}, errors.New("unexpected error")
 				},
 			},
+			target:  "/alias",
 			expCode: http.StatusInternalServerError,
 		},
 	}
@@ -152,9 +563,15 @@ func TestHandler_Redirect(t *testing.T) {
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			is := is.New(t)
-			h := NewHandler(tc.s)
+			h := NewHandler(config.HTTPServer{}, tc.s, tc.geo)
 
-			r := httptest.NewRequest(http.MethodGet, "/alias", nil)
+			r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			for k, v := range tc.header {
+				r.Header[k] = v
+			}
+			for _, c := range tc.cookies {
+				r.AddCookie(c)
+			}
 			rr := httptest.NewRecorder()
 			h.ServeHTTP(rr, r)
 
@@ -162,6 +579,920 @@ func TestHandler_Redirect(t *testing.T) {
 			if tc.expCode == http.StatusSeeOther {
 				is.Equal(tc.expURL, rr.Header().Get("Location"))
 			}
+			if tc.expCookie != "" {
+				is.True(strings.HasPrefix(rr.Header().Get("Set-Cookie"), tc.expCookie))
+			} else {
+				is.Equal("", rr.Header().Get("Set-Cookie")) // cookie is not set
+			}
+		})
+	}
+}
+
+func TestHandler_ClientIP(t *testing.T) {
+	testcases := []struct {
+		name    string
+		proxies string
+		header  http.Header
+		expIP   string
+	}{
+		{
+			name:  "peer address is used without proxies",
+			expIP: "192.0.2.1",
+		},
+		{
+			name:   "headers of untrusted peer are ignored",
+			header: http.Header{"X-Forwarded-For": {"198.51.100.1"}, "X-Real-Ip": {"198.51.100.2"}},
+			expIP:  "192.0.2.1",
+		},
+		{
+			name:    "forwarded address is used from trusted proxy",
+			proxies: "192.0.2.0/24",
+			header:  http.Header{"X-Forwarded-For": {"198.51.100.1"}},
+			expIP:   "198.51.100.1",
+		},
+		{
+			name:    "trusted proxies are skipped",
+			proxies: "192.0.2.1, 10.0.0.0/8",
+			header:  http.Header{"X-Forwarded-For": {"203.0.113.1, 198.51.100.1", "10.0.0.1"}},
+			expIP:   "198.51.100.1",
+		},
+		{
+			name:    "real IP is used from trusted proxy",
+			proxies: "192.0.2.1",
+			header:  http.Header{"X-Real-Ip": {"198.51.100.2"}},
+			expIP:   "198.51.100.2",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			h := NewHandler(config.HTTPServer{TrustedProxies: tc.proxies}, nil, nil)
+
+			r := httptest.NewRequest(http.MethodGet, "/alias", nil)
+			r.RemoteAddr = "192.0.2.1:1234"
+			for k, v := range tc.header {
+				r.Header[k] = v
+			}
+
+			is.Equal(tc.expIP, h.clientIP(r).String())
+		})
+	}
+}
+
+func TestHandler_SetForwarding(t *testing.T) {
+	testcases := []struct {
+		name    string
+		s       Service
+		body    string
+		token   string
+		expCode int
+		expBody string
+	}{
+		{
+			name: "forwarding is set",
+			s: &mockService{
+				setForwarding: func(ctx context.Context, alias string, f service.Forwarding) error {
+					if alias != "alias" || !f.Path || f.Query != service.QueryAppend {
+						return errors.New("unexpected arguments")
+					}
+
+					return nil
+				},
+			},
+			body:    `{"path":true,"query":"append"}`,
+			expCode: http.StatusNoContent,
+		},
+		{
+			name:    "empty request body",
+			expCode: http.StatusBadRequest,
+			expBody: `{"error":"EOF"}`,
+		},
+		{
+			name: "invalid query policy",
+			s: &mockService{
+				setForwarding: func(ctx context.Context, alias string, f service.Forwarding) error {
+					return service.ErrInvalidQueryPolicy
+				},
+			},
+			body:    `{"query":"x"}`,
+			expCode: http.StatusBadRequest,
+			expBody: `{"error":"invalid query policy"}`,
+		},
+		{
+			name: "link not found",
+			s: &mockService{
+				setForwarding: func(ctx context.Context, alias string, f service.Forwarding) error {
+					return service.ErrLinkNotFound
+				},
+			},
+			body:    `{"path":true}`,
+			expCode: http.StatusNotFound,
+		},
+		{
+			name:    "invalid admin token",
+			body:    "{}",
+			token:   "Bearer other",
+			expCode: http.StatusUnauthorized,
+		},
+		{
+			name: "unexpected error",
+			s: &mockService{
+				setForwarding: func(ctx context.Context, alias string, f service.Forwarding) error {
+					return errors.New("unexpected error")
+				},
+			},
+			body:    `{"path":true}`,
+			expCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			h := NewHandler(config.HTTPServer{AdminToken: "token"}, tc.s, nil)
+
+			r := httptest.NewRequest(http.MethodPut, "/api/v1/links/alias/forwarding", bytes.NewBufferString(tc.body))
+			r.Header.Set("Authorization", "Bearer token")
+			if tc.token != "" {
+				r.Header.Set("Authorization", tc.token)
+			}
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, r)
+
+			is.Equal(tc.expCode, rr.Code)
+			is.Equal(tc.expBody, strings.TrimSpace(rr.Body.String()))
+		})
+	}
+}
+
+func TestHandler_SetParams(t *testing.T) {
+	testcases := []struct {
+		name    string
+		s       Service
+		body    string
+		token   string
+		expCode int
+		expBody string
+	}{
+		{
+			name: "params are set",
+			s: &mockService{
+				setParams: func(ctx context.Context, alias string, params map[string]string) error {
+					if alias != "alias" || params["utm_source"] != "tinee" {
+						return errors.New("unexpected arguments")
+					}
+
+					return nil
+				},
+			},
+			body:    `{"utm_source":"tinee"}`,
+			expCode: http.StatusNoContent,
+		},
+		{
+			name:    "empty request body",
+			expCode: http.StatusBadRequest,
+			expBody: `{"error":"EOF"}`,
+		},
+		{
+			name: "invalid params",
+			s: &mockService{
+				setParams: func(ctx context.Context, alias string, params map[string]string) error {
+					return service.ErrInvalidParams
+				},
+			},
+			body:    `{"utm_source":"{x}"}`,
+			expCode: http.StatusBadRequest,
+			expBody: `{"error":"invalid params"}`,
+		},
+		{
+			name: "link not found",
+			s: &mockService{
+				setParams: func(ctx context.Context, alias string, params map[string]string) error {
+					return service.ErrLinkNotFound
+				},
+			},
+			body:    `{}`,
+			expCode: http.StatusNotFound,
+		},
+		{
+			name:    "invalid admin token",
+			body:    "{}",
+			token:   "Bearer other",
+			expCode: http.StatusUnauthorized,
+		},
+		{
+			name: "unexpected error",
+			s: &mockService{
+				setParams: func(ctx context.Context, alias string, params map[string]string) error {
+					return errors.New("unexpected error")
+				},
+			},
+			body:    `{}`,
+			expCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			h := NewHandler(config.HTTPServer{AdminToken: "token"}, tc.s, nil)
+
+			r := httptest.NewRequest(http.MethodPut, "/api/v1/links/alias/params", bytes.NewBufferString(tc.body))
+			r.Header.Set("Authorization", "Bearer token")
+			if tc.token != "" {
+				r.Header.Set("Authorization", tc.token)
+			}
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, r)
+
+			is.Equal(tc.expCode, rr.Code)
+			is.Equal(tc.expBody, strings.TrimSpace(rr.Body.String()))
+		})
+	}
+}
+
+func TestHandler_SetTargets(t *testing.T) {
+	testcases := []struct {
+		name    string
+		s       Service
+		body    string
+		token   string
+		expCode int
+		expBody string
+	}{
+		{
+			name: "targets are set",
+			s: &mockService{
+				setTargets: func(ctx context.Context, alias string, targets []service.Target) error {
+					if alias != "alias" || len(targets) != 1 || targets[0].Devices[0] != service.DeviceIOS {
+						return errors.New("unexpected arguments")
+					}
+
+					return nil
+				},
+			},
+			body:    `[{"url":"https://x.xx","devices":["ios"]}]`,
+			expCode: http.StatusNoContent,
+		},
+		{
+			name:    "empty request body",
+			expCode: http.StatusBadRequest,
+			expBody: `{"error":"EOF"}`,
+		},
+		{
+			name: "invalid target",
+			s: &mockService{
+				setTargets: func(ctx context.Context, alias string, targets []service.Target) error {
+					return service.ErrInvalidTarget
+				},
+			},
+			body:    `[{"url":"x.xx"}]`,
+			expCode: http.StatusBadRequest,
+			expBody: `{"error":"invalid target"}`,
+		},
+		{
+			name: "link not found",
+			s: &mockService{
+				setTargets: func(ctx context.Context, alias string, targets []service.Target) error {
+					return service.ErrLinkNotFound
+				},
+			},
+			body:    `[]`,
+			expCode: http.StatusNotFound,
+		},
+		{
+			name:    "invalid admin token",
+			body:    "{}",
+			token:   "Bearer other",
+			expCode: http.StatusUnauthorized,
+		},
+		{
+			name: "unexpected error",
+			s: &mockService{
+				setTargets: func(ctx context.Context, alias string, targets []service.Target) error {
+					return errors.New("unexpected error")
+				},
+			},
+			body:    `[]`,
+			expCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			h := NewHandler(config.HTTPServer{AdminToken: "token"}, tc.s, nil)
+
+			r := httptest.NewRequest(http.MethodPut, "/api/v1/links/alias/targets", bytes.NewBufferString(tc.body))
+			r.Header.Set("Authorization", "Bearer token")
+			if tc.token != "" {
+				r.Header.Set("Authorization", tc.token)
+			}
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, r)
+
+			is.Equal(tc.expCode, rr.Code)
+			is.Equal(tc.expBody, strings.TrimSpace(rr.Body.String()))
+		})
+	}
+}
+
+func TestHandler_SetVariants(t *testing.T) {
+	testcases := []struct {
+		name    string
+		s       Service
+		body    string
+		token   string
+		expCode int
+		expBody string
+	}{
+		{
+			name: "variants are set",
+			s: &mockService{
+				setVariants: func(ctx context.Context, alias string, variants []service.Variant) error {
+					if alias != "alias" || len(variants) != 1 || variants[0].Weight != 2 {
+						return errors.New("unexpected arguments")
+					}
+
+					return nil
+				},
+			},
+			body:    `[{"name":"aaaa","url":"https://x.xx","weight":2}]`,
+			expCode: http.StatusNoContent,
+		},
+		{
+			name:    "empty request body",
+			expCode: http.StatusBadRequest,
+			expBody: `{"error":"EOF"}`,
+		},
+		{
+			name: "invalid variant",
+			s: &mockService{
+				setVariants: func(ctx context.Context, alias string, variants []service.Variant) error {
+					return service.ErrInvalidVariant
+				},
+			},
+			body:    `[{"name":"aaaa"}]`,
+			expCode: http.StatusBadRequest,
+			expBody: `{"error":"invalid variant"}`,
+		},
+		{
+			name: "link not found",
+			s: &mockService{
+				setVariants: func(ctx context.Context, alias string, variants []service.Variant) error {
+					return service.ErrLinkNotFound
+				},
+			},
+			body:    `[]`,
+			expCode: http.StatusNotFound,
+		},
+		{
+			name:    "invalid admin token",
+			body:    "{}",
+			token:   "Bearer other",
+			expCode: http.StatusUnauthorized,
+		},
+		{
+			name: "unexpected error",
+			s: &mockService{
+				setVariants: func(ctx context.Context, alias string, variants []service.Variant) error {
+					return errors.New("unexpected error")
+				},
+			},
+			body:    `[]`,
+			expCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			h := NewHandler(config.HTTPServer{AdminToken: "token"}, tc.s, nil)
+
+			r := httptest.NewRequest(http.MethodPut, "/api/v1/links/alias/variants", bytes.NewBufferString(tc.body))
+			r.Header.Set("Authorization", "Bearer token")
+			if tc.token != "" {
+				r.Header.Set("Authorization", tc.token)
+			}
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, r)
+
+			is.Equal(tc.expCode, rr.Code)
+			is.Equal(tc.expBody, strings.TrimSpace(rr.Body.String()))
+		})
+	}
+}
+
+func TestHandler_Variants(t *testing.T) {
+	testcases := []struct {
+		name    string
+		s       Service
+		expCode int
+		expBody string
+	}{
+		{
+			name: "variants are reported",
+			s: &mockService{
+				variants: func(ctx context.Context, alias string) ([]service.VariantClicks, error) {
+					return []service.VariantClicks{{
+						Variant: service.Variant{Name: "aaaa", URL: "https://x.xx", Weight: 1},
+						Clicks:  5,
+					}}, nil
+				},
+			},
+			expCode: http.StatusOK,
+			expBody: `[{"name":"aaaa","url":"https://x.xx","weight":1,"clicks":5}]`,
+		},
+		{
+			name: "link not found",
+			s: &mockService{
+				variants: func(ctx context.Context, alias string) ([]service.VariantClicks, error) {
+					return nil, service.ErrLinkNotFound
+				},
+			},
+			expCode: http.StatusNotFound,
+		},
+		{
+			name: "unexpected error",
+			s: &mockService{
+				variants: func(ctx context.Context, alias string) ([]service.VariantClicks, error) {
+					return nil, errors.New("unexpected error")
+				},
+			},
+			expCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			h := NewHandler(config.HTTPServer{}, tc.s, nil)
+
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/links/alias/variants", nil)
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, r)
+
+			is.Equal(tc.expCode, rr.Code)
+			is.Equal(tc.expBody, strings.TrimSpace(rr.Body.String()))
+		})
+	}
+}
+
+func TestHandler_QRCode(t *testing.T) {
+	testcases := []struct {
+		name           string
+		s              Service
+		target         string
+		expCode        int
+		expContentType string
+		expBody        string
+	}{
+		{
+			name: "PNG QR code is rendered",
+			s: &mockService{
+				qrCode: func(ctx context.Context, alias string, o qrcode.Options) ([]byte, error) {
+					if alias != "alias" || o != (qrcode.Options{}) {
+						return nil, errors.New("unexpected arguments")
+					}
+
+					return []byte("png"), nil
+				},
+			},
+			target:         "/alias/qr",
+			expCode:        http.StatusOK,
+			expContentType: "image/png",
+			expBody:        "png",
+		},
+		{
+			name: "SVG QR code is rendered with options",
+			s: &mockService{
+				qrCode: func(ctx context.Context, alias string, o qrcode.Options) ([]byte, error) {
+					exp := qrcode.Options{
+						Format:     qrcode.FormatSVG,
+						Size:       512,
+						Level:      "H",
+						Foreground: "112233",
+						Background: "ffffff",
+						QuietZone:  true,
+					}
+					if o != exp {
+						return nil, errors.New("unexpected options")
+					}
+
+					return []byte("<svg/>"), nil
+				},
+			},
+			target:         "/alias/qr?format=svg&size=512&level=H&fg=112233&bg=ffffff&quietZone=true",
+			expCode:        http.StatusOK,
+			expContentType: "image/svg+xml",
+			expBody:        "<svg/>",
+		},
+		{
+			name:           "invalid size",
+			target:         "/alias/qr?size=x",
+			expCode:        http.StatusBadRequest,
+			expContentType: "application/json",
+			expBody:        `{"error":"invalid QR code options"}`,
+		},
+		{
+			name:           "invalid quiet zone",
+			target:         "/alias/qr?quietZone=x",
+			expCode:        http.StatusBadRequest,
+			expContentType: "application/json",
+			expBody:        `{"error":"invalid QR code options"}`,
+		},
+		{
+			name: "invalid options",
+			s: &mockService{
+				qrCode: func(ctx context.Context, alias string, o qrcode.Options) ([]byte, error) {
+					return nil, qrcode.ErrInvalidOptions
+				},
+			},
+			target:         "/alias/qr?level=X",
+			expCode:        http.StatusBadRequest,
+			expContentType: "application/json",
+			expBody:        `{"error":"invalid QR code options"}`,
+		},
+		{
+			name: "link not found",
+			s: &mockService{
+				qrCode: func(ctx context.Context, alias string, o qrcode.Options) ([]byte, error) {
+					return nil, service.ErrLinkNotFound
+				},
+			},
+			target:         "/alias/qr",
+			expCode:        http.StatusNotFound,
+			expContentType: "application/json",
+		},
+		{
+			name: "unexpected error",
+			s: &mockService{
+				qrCode: func(ctx context.Context, alias string, o qrcode.Options) ([]byte, error) {
+					return nil, errors.New("unexpected error")
+				},
+			},
+			target:         "/alias/qr",
+			expCode:        http.StatusInternalServerError,
+			expContentType: "application/json",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			h := NewHandler(config.HTTPServer{}, tc.s, nil)
+
+			r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, r)
+
+			is.Equal(tc.expCode, rr.Code)
+			is.Equal(tc.expContentType, rr.Header().Get("Content-Type"))
+			is.Equal(tc.expBody, strings.TrimSpace(rr.Body.String()))
+		})
+	}
+}
+
+func TestHandler_Export(t *testing.T) {
+	testcases := []struct {
+		name           string
+		s              Service
+		target         string
+		token          string
+		expCode        int
+		expContentType string
+		expBody        string
+	}{
+		{
+			name: "links are exported as JSONL",
+			s: &mockService{
+				export: func(ctx context.Context, fn func(service.Link) error) error {
+					return fn(service.Link{ID: "x", URL: "https://x.xx", Aliases: []string{"xxxx"}})
+				},
+			},
+			target:         "/admin/links/export?format=jsonl",
+			token:          "Bearer token",
+			expCode:        http.StatusOK,
+			expContentType: "application/x-ndjson",
+			expBody:        `{"id":"x","url":"https://x.xx","aliases":["xxxx"]}`,
+		},
+		{
+			name: "links are exported as CSV",
+			s: &mockService{
+				export: func(ctx context.Context, fn func(service.Link) error) error {
+					return fn(service.Link{ID: "x", URL: "https://x.xx", Aliases: []string{"xxxx"}})
+				},
+			},
+			target:         "/admin/links/export?format=csv",
+			token:          "Bearer token",
+			expCode:        http.StatusOK,
+			expContentType: "text/csv",
+			expBody:        "id,url,aliases,metadata\nx,https://x.xx,xxxx,",
+		},
+		{
+			name:           "unknown format",
+			target:         "/admin/links/export?format=xml",
+			token:          "Bearer token",
+			expCode:        http.StatusBadRequest,
+			expContentType: "application/json",
+			expBody:        `{"error":"unknown format"}`,
+		},
+		{
+			name:    "invalid token",
+			target:  "/admin/links/export?format=jsonl",
+			token:   "Bearer x",
+			expCode: http.StatusUnauthorized,
+		},
+		{
+			name:    "missing token",
+			target:  "/admin/links/export?format=jsonl",
+			expCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			h := NewHandler(config.HTTPServer{AdminToken: "token"}, tc.s, nil)
+
+			r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.token != "" {
+				r.Header.Set("Authorization", tc.token)
+			}
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, r)
+
+			is.Equal(tc.expCode, rr.Code)
+			if tc.expContentType != "" {
+				is.Equal(tc.expContentType, rr.Header().Get("Content-Type"))
+			}
+			is.Equal(tc.expBody, strings.TrimSpace(rr.Body.String()))
+		})
+	}
+}
+
+func TestHandler_Import(t *testing.T) {
+	testcases := []struct {
+		name    string
+		s       Service
+		target  string
+		body    string
+		expCode int
+		expBody string
+	}{
+		{
+			name: "links are imported",
+			s: &mockService{
+				importLinks: func(ctx context.Context, next func() (service.Link, error), o service.ImportOptions) (service.ImportReport, error) {
+					if o != (service.ImportOptions{Conflict: service.ConflictSkip}) {
+						return service.ImportReport{}, errors.New("unexpected policy")
+					}
+					l, err := next()
+					if err != nil || l.URL != "https://x.xx" {
+						return service.ImportReport{}, errors.New("unexpected link")
+					}
+
+					return service.ImportReport{
+						Imported: 1,
+						Failed:   1,
+						Errors:   []service.ImportError{{Row: 2, Err: service.ErrInvalidRecord}},
+					}, nil
+				},
+			},
+			target:  "/admin/links/import?format=jsonl",
+			body:    `{"id":"x","url":"https://x.xx","aliases":["xxxx"]}`,
+			expCode: http.StatusOK,
+			expBody: `{"imported":1,"skipped":0,"failed":1,"errors":[{"row":2,"error":"invalid record"}]}`,
+		},
+		{
+			name: "dry run of Bitly import",
+			s: &mockService{
+				importLinks: func(ctx context.Context, next func() (service.Link, error), o service.ImportOptions) (service.ImportReport, error) {
+					if o != (service.ImportOptions{Conflict: service.ConflictSkip, DryRun: true, Foreign: true}) {
+						return service.ImportReport{}, errors.New("unexpected options")
+					}
+					l, err := next()
+					if err != nil || l.URL != "https://x.xx" || l.Aliases[0] != "abcd" {
+						return service.ImportReport{}, errors.New("unexpected link")
+					}
+
+					return service.ImportReport{
+						Skipped:   1,
+						Conflicts: []service.ImportError{{Row: 1, Err: service.ErrConflict}},
+					}, nil
+				},
+			},
+			target:  "/admin/links/import?format=bitly&dryRun=true",
+			body:    "long_url,link\nhttps://x.xx,https://bit.ly/abcd\n",
+			expCode: http.StatusOK,
+			expBody: `{"imported":0,"skipped":1,"failed":0,"conflicts":[{"row":1,"error":"link conflicts with existing one"}],"dryRun":true}`,
+		},
+		{
+			name:    "invalid dry run",
+			target:  "/admin/links/import?format=jsonl&dryRun=x",
+			expCode: http.StatusBadRequest,
+			expBody: `{"error":"invalid dryRun"}`,
+		},
+		{
+			name:    "unknown format",
+			target:  "/admin/links/import?format=xml",
+			expCode: http.StatusBadRequest,
+			expBody: `{"error":"unknown format"}`,
+		},
+		{
+			name: "malformed input",
+			s: &mockService{
+				importLinks: func(ctx context.Context, next func() (service.Link, error), o service.ImportOptions) (service.ImportReport, error) {
+					_, err := next()
+					return service.ImportReport{}, service.ImportError{Row: 1, Err: err}
+				},
+			},
+			target:  "/admin/links/import?format=csv",
+			body:    "url,alias\n",
+			expCode: http.StatusBadRequest,
+			expBody: `{"imported":0,"skipped":0,"failed":0,"error":"row 1: malformed input: invalid CSV header: [url alias]"}`,
+		},
+		{
+			name: "invalid conflict policy",
+			s: &mockService{
+				importLinks: func(ctx context.Context, next func() (service.Link, error), o service.ImportOptions) (service.ImportReport, error) {
+					return service.ImportReport{}, service.ErrInvalidConflictPolicy
+				},
+			},
+			target:  "/admin/links/import?format=jsonl&conflict=x",
+			expCode: http.StatusBadRequest,
+			expBody: `{"imported":0,"skipped":0,"failed":0,"error":"invalid conflict policy"}`,
+		},
+		{
+			name: "conflict",
+			s: &mockService{
+				importLinks: func(ctx context.Context, next func() (service.Link, error), o service.ImportOptions) (service.ImportReport, error) {
+					if o.Conflict != service.ConflictFail {
+						return service.ImportReport{}, errors.New("unexpected policy")
+					}
+
+					return service.ImportReport{Imported: 1}, service.ImportError{Row: 2, Err: service.ErrConflict}
+				},
+			},
+			target:  "/admin/links/import?format=csv&conflict=fail",
+			expCode: http.StatusConflict,
+			expBody: `{"imported":1,"skipped":0,"failed":0,"error":"row 2: link conflicts with existing one"}`,
+		},
+		{
+			name: "unexpected error",
+			s: &mockService{
+				importLinks: func(ctx context.Context, next func() (service.Link, error), o service.ImportOptions) (service.ImportReport, error) {
+					return service.ImportReport{}, errors.New("unexpected error")
+				},
+			},
+			target:  "/admin/links/import?format=jsonl",
+			expCode: http.StatusInternalServerError,
+			expBody: `{"imported":0,"skipped":0,"failed":0,"error":"Internal Server Error"}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			h := NewHandler(config.HTTPServer{AdminToken: "token"}, tc.s, nil)
+
+			r := httptest.NewRequest(http.MethodPost, tc.target, strings.NewReader(tc.body))
+			r.Header.Set("Authorization", "Bearer token")
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, r)
+
+			is.Equal(tc.expCode, rr.Code)
+			is.Equal(tc.expBody, strings.TrimSpace(rr.Body.String()))
+		})
+	}
+}
+
+func TestHandler_AdminDisabled(t *testing.T) {
+	is := is.New(t)
+	s := &mockService{
+		linkByAlias: func(ctx context.Context, alias string) (service.Link, error) {
+			return service.Link{}, service.ErrLinkNotFound
+		},
+	}
+	h := NewHandler(config.HTTPServer{}, s, nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/admin/links/export?format=jsonl", nil)
+	r.Header.Set("Authorization", "Bearer ")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, r)
+
+	is.Equal(http.StatusNotFound, rr.Code)
+}
+
+func TestHandler_ListLinks(t *testing.T) {
+	testcases := []struct {
+		name    string
+		s       Service
+		target  string
+		token   string
+		expCode int
+		expBody string
+	}{
+		{
+			name: "links are listed",
+			s: &mockService{
+				listLinks: func(ctx context.Context, q service.ListQuery) (service.LinkPage, error) {
+					exp := service.ListQuery{
+						Domain:      "x.xx",
+						AliasPrefix: "x",
+						Search:      "docs",
+						Order:       service.SortDesc,
+						Limit:       1,
+						Cursor:      "c1",
+					}
+					if q != exp {
+						return service.LinkPage{}, errors.New("unexpected query")
+					}
+
+					return service.LinkPage{
+						Links: []service.Link{{
+							ID:        "x",
+							URL:       "https://x.xx/docs",
+							Aliases:   []string{"xxxx"},
+							CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+						}},
+						NextCursor: "c2",
+					}, nil
+				},
+			},
+			target:  "/api/v1/links?domain=x.xx&aliasPrefix=x&q=docs&order=desc&limit=1&cursor=c1",
+			expCode: http.StatusOK,
+			expBody: `{"links":[{"id":"x","url":"https://x.xx/docs","aliases":["xxxx"],"createdAt":"2021-01-01T00:00:00Z"}],"nextCursor":"c2"}`,
+		},
+		{
+			name: "no links",
+			s: &mockService{
+				listLinks: func(ctx context.Context, q service.ListQuery) (service.LinkPage, error) {
+					return service.LinkPage{}, nil
+				},
+			},
+			target:  "/api/v1/links",
+			expCode: http.StatusOK,
+			expBody: `{"links":[]}`,
+		},
+		{
+			name:    "invalid limit",
+			target:  "/api/v1/links?limit=x",
+			expCode: http.StatusBadRequest,
+			expBody: `{"error":"invalid list query"}`,
+		},
+		{
+			name: "invalid cursor",
+			s: &mockService{
+				listLinks: func(ctx context.Context, q service.ListQuery) (service.LinkPage, error) {
+					return service.LinkPage{}, service.ErrInvalidCursor
+				},
+			},
+			target:  "/api/v1/links?cursor=x",
+			expCode: http.StatusBadRequest,
+			expBody: `{"error":"invalid cursor"}`,
+		},
+		{
+			name:    "missing admin token",
+			target:  "/api/v1/links",
+			token:   "-",
+			expCode: http.StatusUnauthorized,
+		},
+		{
+			name:    "invalid admin token",
+			target:  "/api/v1/links",
+			token:   "Bearer other",
+			expCode: http.StatusUnauthorized,
+		},
+		{
+			name: "unexpected error",
+			s: &mockService{
+				listLinks: func(ctx context.Context, q service.ListQuery) (service.LinkPage, error) {
+					return service.LinkPage{}, errors.New("unexpected error")
+				},
+			},
+			target:  "/api/v1/links",
+			expCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			h := NewHandler(config.HTTPServer{AdminToken: "token"}, tc.s, nil)
+
+			r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.token == "" {
+				r.Header.Set("Authorization", "Bearer token")
+			} else if tc.token != "-" {
+				r.Header.Set("Authorization", tc.token)
+			}
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, r)
+
+			is.Equal(tc.expCode, rr.Code)
+			is.Equal(tc.expBody, strings.TrimSpace(rr.Body.String()))
 		})
 	}
 }
@@ -0,0 +1,172 @@
+// Package lifecycle starts components of tinee in order, and stops them
+// in reverse order on termination signal or failure of a component.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"tinee/internal/config"
+)
+
+// Signals are signals terminating tinee.
+var Signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// Component is a part of tinee, e.g. database connection or server. All
+// its functions are optional.
+type Component struct {
+	Name string
+	// Start starts the component, components started later may use it.
+	Start func(ctx context.Context) error
+	// Serve serves until the component is stopped, it runs in background
+	// after all components started. Its error shuts tinee down.
+	Serve func() error
+	// Stop stops the component, e.g. drains its requests.
+	Stop func(ctx context.Context) error
+	// StopTimeout is timeout of Stop, StopTimeout of Lifecycle is used if
+	// it is zero.
+	StopTimeout time.Duration
+}
+
+// Lifecycle starts and stops components.
+type Lifecycle struct {
+	cfg        config.Lifecycle
+	components []Component
+	started    int
+	errs       chan error
+}
+
+// New creates and returns a new Lifecycle instance of components.
+func New(cfg config.Lifecycle, components ...Component) *Lifecycle {
+	return &Lifecycle{cfg: cfg, components: components}
+}
+
+// Append appends components started after the existing ones.
+func (l *Lifecycle) Append(components ...Component) {
+	l.components = append(l.components, components...)
+}
+
+// Run starts components, waits for termination signal or failure of
+// a component and stops components. It returns error of start, failure or
+// stop of a component.
+func (l *Lifecycle) Run(ctx context.Context) error {
+	ctx, cancel := signal.NotifyContext(ctx, Signals...)
+	defer cancel()
+
+	if err := l.Start(ctx); err != nil {
+		return err
+	}
+
+	err := l.Wait(ctx)
+	if err == nil {
+		zap.L().Info("received termination signal, shutting down")
+	} else {
+		zap.L().Error(err.Error() + ", shutting down")
+	}
+
+	if stopErr := l.Stop(); err == nil {
+		err = stopErr
+	}
+
+	return err
+}
+
+// Start starts components in order and then serves them. If a component
+// fails to start, components started before it are stopped.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, l.cfg.StartTimeout)
+	defer cancel()
+
+	l.errs = make(chan error, len(l.components))
+	for _, c := range l.components {
+		if c.Start != nil {
+			if err := c.Start(ctx); err != nil {
+				err = fmt.Errorf("starting %s: %w", c.Name, err)
+				zap.L().Error(err.Error())
+				_ = l.Stop()
+				return err
+			}
+		}
+		l.started++
+		zap.S().Infof("started %s", c.Name)
+	}
+
+	for _, c := range l.components {
+		if c.Serve != nil {
+			go l.serve(c)
+		}
+	}
+
+	return nil
+}
+
+// Wait waits until context is done or a component fails to serve, it
+// returns error of the component.
+func (l *Lifecycle) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-l.errs:
+		return err
+	}
+}
+
+// Stop stops started components in reverse order, every one of them is
+// stopped even if stop of another one failed. It returns the first error.
+func (l *Lifecycle) Stop() error {
+	var first error
+	for ; l.started > 0; l.started-- {
+		if err := l.stop(l.components[l.started-1]); err != nil {
+			zap.L().Error(err.Error())
+			if first == nil {
+				first = err
+			}
+		}
+	}
+
+	return first
+}
+
+// serve serves component and reports its error.
+func (l *Lifecycle) serve(c Component) {
+	if err := c.Serve(); err != nil {
+		l.errs <- fmt.Errorf("serving %s: %w", c.Name, err)
+	}
+}
+
+// stop stops component with its timeout, it does not wait for the
+// component after the timeout.
+func (l *Lifecycle) stop(c Component) error {
+	if c.Stop != nil {
+		timeout := c.StopTimeout
+		if timeout == 0 {
+			timeout = l.cfg.StopTimeout
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		done := make(chan error, 1)
+		go func() {
+			done <- c.Stop(ctx)
+		}()
+
+		var err error
+		select {
+		case err = <-done:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		if err != nil {
+			return fmt.Errorf("stopping %s: %w", c.Name, err)
+		}
+	}
+
+	zap.S().Infof("stopped %s", c.Name)
+	return nil
+}
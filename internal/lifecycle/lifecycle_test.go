@@ -0,0 +1,194 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"tinee/internal/config"
+)
+
+var cfg = config.Lifecycle{StartTimeout: time.Second, StopTimeout: 10 * time.Millisecond}
+
+// recorder records starts and stops of components.
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) record(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+// component returns component recording its start and stop, and failing
+// with errors.
+func (r *recorder) component(name string, startErr, stopErr error) Component {
+	return Component{
+		Name: name,
+		Start: func(ctx context.Context) error {
+			r.record("start " + name)
+			return startErr
+		},
+		Stop: func(ctx context.Context) error {
+			r.record("stop " + name)
+			return stopErr
+		},
+	}
+}
+
+func TestLifecycle_Start(t *testing.T) {
+	errFailed := errors.New("failed")
+	testcases := []struct {
+		name      string
+		startErr  error
+		stopErr   error
+		expEvents []string
+		expErr    error
+	}{
+		{
+			name:      "components are started in order",
+			expEvents: []string{"start a", "start b", "start c"},
+		},
+		{
+			name:      "started components are stopped if a component fails to start",
+			startErr:  errFailed,
+			expEvents: []string{"start a", "start b", "stop a"},
+			expErr:    errFailed,
+		},
+		{
+			name:      "start error is returned if a component fails to stop",
+			startErr:  errFailed,
+			stopErr:   errors.New("stop failed"),
+			expEvents: []string{"start a", "start b", "stop a"},
+			expErr:    errFailed,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			r := &recorder{}
+			l := New(cfg, r.component("a", nil, tc.stopErr), r.component("b", tc.startErr, nil))
+			l.Append(r.component("c", nil, nil))
+
+			err := l.Start(context.Background())
+
+			is.True(errors.Is(err, tc.expErr))
+			is.Equal(tc.expEvents, r.events)
+		})
+	}
+}
+
+func TestLifecycle_Stop(t *testing.T) {
+	errFailed := errors.New("failed")
+	testcases := []struct {
+		name      string
+		stop      func(ctx context.Context) error
+		expEvents []string
+		expErr    error
+	}{
+		{
+			name:      "components are stopped in reverse order",
+			stop:      nil,
+			expEvents: []string{"stop c", "stop a"},
+			expErr:    nil,
+		},
+		{
+			name: "components are stopped if a component fails to stop",
+			stop: func(ctx context.Context) error {
+				return errFailed
+			},
+			expEvents: []string{"stop c", "stop a"},
+			expErr:    errFailed,
+		},
+		{
+			name: "component is not waited for after timeout",
+			stop: func(ctx context.Context) error {
+				time.Sleep(time.Second)
+				return nil
+			},
+			expEvents: []string{"stop c", "stop a"},
+			expErr:    context.DeadlineExceeded,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			r := &recorder{}
+			l := New(cfg, r.component("a", nil, nil), Component{Name: "b", Stop: tc.stop}, r.component("c", nil, nil))
+			is.NoErr(l.Start(context.Background()))
+			r.events = nil
+
+			err := l.Stop()
+
+			is.True(errors.Is(err, tc.expErr))
+			is.Equal(tc.expEvents, r.events)
+			is.NoErr(l.Stop()) // components are stopped once
+		})
+	}
+}
+
+func TestLifecycle_Run(t *testing.T) {
+	errFailed := errors.New("failed")
+	testcases := []struct {
+		name   string
+		serve  func(stop <-chan struct{}) error
+		cancel bool
+		expErr error
+	}{
+		{
+			name: "components are stopped when context is done",
+			serve: func(stop <-chan struct{}) error {
+				<-stop
+				return nil
+			},
+			cancel: true,
+			expErr: nil,
+		},
+		{
+			name: "components are stopped when a component fails",
+			serve: func(stop <-chan struct{}) error {
+				return errFailed
+			},
+			expErr: errFailed,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			r := &recorder{}
+			stop := make(chan struct{})
+			serve := tc.serve
+			server := Component{
+				Name: "server",
+				Serve: func() error {
+					return serve(stop)
+				},
+				Stop: func(ctx context.Context) error {
+					r.record("stop server")
+					close(stop)
+					return nil
+				},
+			}
+			l := New(cfg, r.component("db", nil, nil), server)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancel {
+				cancel()
+			}
+			err := l.Run(ctx)
+
+			is.True(errors.Is(err, tc.expErr))
+			is.Equal([]string{"start db", "stop server", "stop db"}, r.events)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDMetadata is gRPC metadata key of request ID.
+const RequestIDMetadata = "x-request-id"
+
+// UnaryInterceptor is gRPC interceptor logging unary calls. Request ID is
+// taken from x-request-id metadata or generated, and returned in the same
+// header metadata. Logger of the call is stored in its context.
+func UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	ctx, ID := withRequest(ctx, incomingRequestID(ctx))
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadata, ID))
+
+	resp, err := handler(ctx, req)
+	logCall(ctx, info.FullMethod, start, err)
+
+	return resp, err
+}
+
+// StreamInterceptor is gRPC interceptor logging streaming calls like
+// UnaryInterceptor does.
+func StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	ctx, ID := withRequest(ss.Context(), incomingRequestID(ss.Context()))
+	_ = ss.SetHeader(metadata.Pairs(RequestIDMetadata, ID))
+
+	err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	logCall(ctx, info.FullMethod, start, err)
+
+	return err
+}
+
+// serverStream is grpc.ServerStream with context of the call.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns context of the call.
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// incomingRequestID returns request ID of incoming metadata of ctx.
+func incomingRequestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if IDs := md.Get(RequestIDMetadata); len(IDs) > 0 {
+		return IDs[0]
+	}
+
+	return ""
+}
+
+// logCall logs the call of the method started at start.
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	level := zapcore.InfoLevel
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unimplemented:
+		level = zapcore.ErrorLevel
+	}
+
+	ce := FromContext(ctx).Check(level, "gRPC call")
+	if ce == nil {
+		return
+	}
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.String("code", code.String()),
+		zap.Duration("duration", time.Since(start)),
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		fields = append(fields, zap.String("peer", p.Addr.String()))
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	ce.Write(fields...)
+}
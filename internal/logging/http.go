@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RequestIDHeader is HTTP header of request ID.
+const RequestIDHeader = "X-Request-ID"
+
+// HTTP is middleware logging HTTP requests. Request ID is taken from
+// X-Request-ID header or generated, and returned in the same header of
+// the response. Logger of the request is stored in its context.
+func HTTP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ctx, ID := withRequest(r.Context(), r.Header.Get(RequestIDHeader))
+		w.Header().Set(RequestIDHeader, ID)
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		code := ww.Status()
+		if code == 0 {
+			code = http.StatusOK
+		}
+		level := zapcore.InfoLevel
+		if code >= http.StatusInternalServerError {
+			level = zapcore.ErrorLevel
+		}
+		if ce := FromContext(ctx).Check(level, "HTTP request"); ce != nil {
+			ce.Write(
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.Int("status", code),
+				zap.Int("size", ww.BytesWritten()),
+				zap.Duration("duration", time.Since(start)),
+				zap.String("clientIp", clientIP(r)),
+				zap.String("userAgent", r.UserAgent()),
+			)
+		}
+	})
+}
+
+// clientIP returns IP address of the client of the request.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
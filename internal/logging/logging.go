@@ -0,0 +1,111 @@
+// Package logging provides structured logging of tinee with request-scoped
+// loggers.
+package logging
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"tinee/internal/config"
+)
+
+const (
+	// FormatJSON logs entries as JSON objects.
+	FormatJSON = "json"
+	// FormatConsole logs entries as human-readable lines.
+	FormatConsole = "console"
+)
+
+var (
+	// ErrInvalidLevel is returned when unknown level was configured.
+	ErrInvalidLevel = errors.New("invalid log level")
+	// ErrInvalidFormat is returned when unknown format was configured.
+	ErrInvalidFormat = errors.New("invalid log format")
+)
+
+// New creates and returns a new logger configured by cfg, and its level
+// which can be changed while the logger is used.
+func New(cfg config.Logging) (*zap.Logger, zap.AtomicLevel, error) {
+	var level zapcore.Level
+	if err := level.Set(cfg.Level); err != nil {
+		return nil, zap.AtomicLevel{}, ErrInvalidLevel
+	}
+	if cfg.Format != FormatJSON && cfg.Format != FormatConsole {
+		return nil, zap.AtomicLevel{}, ErrInvalidFormat
+	}
+
+	zc := zap.NewProductionConfig()
+	zc.Level = zap.NewAtomicLevelAt(level)
+	zc.Encoding = cfg.Format
+	zc.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	zc.Sampling = nil
+	if cfg.SamplingInitial > 0 {
+		zc.Sampling = &zap.SamplingConfig{
+			Initial:    cfg.SamplingInitial,
+			Thereafter: cfg.SamplingThereafter,
+		}
+	}
+
+	l, err := zc.Build()
+	if err != nil {
+		return nil, zap.AtomicLevel{}, err
+	}
+
+	return l, zc.Level, nil
+}
+
+// contextKey is type of keys of context values of the package.
+type contextKey int
+
+const (
+	loggerKey contextKey = iota
+	requestIDKey
+)
+
+// WithLogger returns a copy of ctx with the logger.
+func WithLogger(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, l)
+}
+
+// FromContext returns logger of ctx, or the global logger if there is none.
+func FromContext(ctx context.Context) *zap.Logger {
+	if l, ok := ctx.Value(loggerKey).(*zap.Logger); ok {
+		return l
+	}
+
+	return zap.L()
+}
+
+// RequestID returns ID of the request of ctx, or empty string if there
+// is none.
+func RequestID(ctx context.Context) string {
+	ID, _ := ctx.Value(requestIDKey).(string)
+
+	return ID
+}
+
+// maxRequestIDLength is max length of request ID provided by client,
+// longer ones are replaced with generated ID.
+const maxRequestIDLength = 128
+
+// withRequest returns a copy of ctx with ID of the request, and logger of
+// the request that logs the ID and trace ID of ctx span. ID is generated
+// if it is empty or too long.
+func withRequest(ctx context.Context, ID string) (context.Context, string) {
+	if ID == "" || len(ID) > maxRequestIDLength {
+		ID = uuid.New().String()
+	}
+	ctx = context.WithValue(ctx, requestIDKey, ID)
+
+	fields := []zap.Field{zap.String("requestId", ID)}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields = append(fields, zap.String("traceId", sc.TraceID().String()))
+	}
+
+	return WithLogger(ctx, FromContext(ctx).With(fields...)), ID
+}
@@ -0,0 +1,146 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matryer/is"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"tinee/internal/config"
+)
+
+// observe replaces global logger with logger observing entries for the test.
+func observe(t *testing.T) *observer.ObservedLogs {
+	core, logs := observer.New(zapcore.DebugLevel)
+	t.Cleanup(zap.ReplaceGlobals(zap.New(core)))
+
+	return logs
+}
+
+func TestNew(t *testing.T) {
+	testcases := []struct {
+		name   string
+		cfg    config.Logging
+		expErr error
+	}{
+		{
+			name:   "JSON logger with sampling",
+			cfg:    config.Logging{Level: "info", Format: FormatJSON, SamplingInitial: 100, SamplingThereafter: 100},
+			expErr: nil,
+		},
+		{
+			name:   "console logger without sampling",
+			cfg:    config.Logging{Level: "debug", Format: FormatConsole},
+			expErr: nil,
+		},
+		{
+			name:   "invalid level",
+			cfg:    config.Logging{Level: "verbose", Format: FormatJSON},
+			expErr: ErrInvalidLevel,
+		},
+		{
+			name:   "invalid format",
+			cfg:    config.Logging{Level: "info", Format: "xml"},
+			expErr: ErrInvalidFormat,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			_, _, err := New(tc.cfg)
+
+			is.Equal(tc.expErr, err)
+		})
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	testcases := []struct {
+		name      string
+		requestID string
+		code      int
+		expLevel  zapcore.Level
+	}{
+		{
+			name:      "request ID is propagated",
+			requestID: "abc",
+			code:      http.StatusOK,
+			expLevel:  zapcore.InfoLevel,
+		},
+		{
+			name:      "request ID is generated",
+			requestID: "",
+			code:      http.StatusInternalServerError,
+			expLevel:  zapcore.ErrorLevel,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			logs := observe(t)
+			var handled string
+			h := HTTP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handled = RequestID(r.Context())
+				FromContext(r.Context()).Info("handled")
+				w.WriteHeader(tc.code)
+				_, _ = w.Write([]byte("{}"))
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/xxxx", nil)
+			r.Header.Set(RequestIDHeader, tc.requestID)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			ID := w.Header().Get(RequestIDHeader)
+			is.True(ID != "")
+			if tc.requestID != "" {
+				is.Equal(tc.requestID, ID)
+			}
+			is.Equal(ID, handled)
+
+			entries := logs.AllUntimed()
+			is.Equal(2, len(entries))
+			is.Equal(ID, entries[0].ContextMap()["requestId"]) // handler logs with request ID
+			access := entries[1]
+			is.Equal(tc.expLevel, access.Level)
+			is.Equal(ID, access.ContextMap()["requestId"])
+			is.Equal(int64(tc.code), access.ContextMap()["status"])
+			is.Equal(int64(2), access.ContextMap()["size"])
+			is.Equal("/xxxx", access.ContextMap()["path"])
+		})
+	}
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	is := is.New(t)
+	logs := observe(t)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDMetadata, "abc"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/tinee.TineeURL/UrlByAlias"}
+
+	var handled string
+	_, err := UnaryInterceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		handled = RequestID(ctx)
+		return nil, status.Error(codes.Internal, "unexpected error")
+	})
+
+	is.Equal(codes.Internal, status.Code(err))
+	is.Equal("abc", handled)
+	entries := logs.AllUntimed()
+	is.Equal(1, len(entries))
+	is.Equal(zapcore.ErrorLevel, entries[0].Level)
+	is.Equal("abc", entries[0].ContextMap()["requestId"])
+	is.Equal("Internal", entries[0].ContextMap()["code"])
+	is.Equal(info.FullMethod, entries[0].ContextMap()["method"])
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"tinee/internal/service"
+)
+
+// LinkCache is the in-memory link cache.
+type LinkCache struct {
+	mu    sync.RWMutex
+	links map[string]service.Link
+}
+
+// NewLinkCache creates and returns a new LinkCache instance.
+func NewLinkCache() *LinkCache {
+	return &LinkCache{links: make(map[string]service.Link)}
+}
+
+// Set caches a service.Link with alias key.
+func (c *LinkCache) Set(ctx context.Context, alias string, l service.Link) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.links[alias] = copyLink(l)
+
+	return nil
+}
+
+// Get gets cached service.Link by alias. It returns service.ErrLinkNotFound
+// if the alias is not cached.
+func (c *LinkCache) Get(ctx context.Context, alias string) (service.Link, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	l, ok := c.links[alias]
+	if !ok {
+		return service.Link{}, service.ErrLinkNotFound
+	}
+
+	return copyLink(l), nil
+}
+
+// SetMany caches service.Links with alias keys.
+func (c *LinkCache) SetMany(ctx context.Context, links map[string]service.Link) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for alias, l := range links {
+		c.links[alias] = copyLink(l)
+	}
+
+	return nil
+}
+
+// GetMany gets cached service.Links by aliases, aliases without cached
+// Link are not present in the returned map.
+func (c *LinkCache) GetMany(ctx context.Context, aliases []string) (map[string]service.Link, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	links := make(map[string]service.Link, len(aliases))
+	for _, a := range aliases {
+		if l, ok := c.links[a]; ok {
+			links[a] = copyLink(l)
+		}
+	}
+
+	return links, nil
+}
+
+// Delete removes cached service.Links with aliases.
+func (c *LinkCache) Delete(ctx context.Context, aliases []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, a := range aliases {
+		delete(c.links, a)
+	}
+
+	return nil
+}
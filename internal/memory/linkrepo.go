@@ -0,0 +1,240 @@
+// Package memory provides in-memory storage for tests and development.
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"tinee/internal/service"
+)
+
+// LinkRepo is the in-memory link repository.
+type LinkRepo struct {
+	mu    sync.RWMutex
+	links map[string]service.Link
+	// ids are IDs of links in the order they were saved first.
+	ids []string
+}
+
+// NewLinkRepo creates and returns a new LinkRepo instance.
+func NewLinkRepo() *LinkRepo {
+	return &LinkRepo{links: make(map[string]service.Link)}
+}
+
+// Save saves a Link if the stored one has the same version. It returns
+// service.ErrVersionConflict otherwise, and service.ErrInvalidAlias if
+// any alias of the Link is taken by another one.
+func (r *LinkRepo) Save(ctx context.Context, l service.Link) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.check(l); err != nil {
+		return err
+	}
+	r.save(l)
+
+	return nil
+}
+
+// SaveMany saves Links if stored ones have the same versions and their
+// aliases are not taken. Links that can't be saved are skipped and
+// service.LinkErrors is returned with their errors.
+func (r *LinkRepo) SaveMany(ctx context.Context, links []service.Link) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	errs := make(service.LinkErrors)
+	for i, l := range links {
+		if err := r.check(l); err != nil {
+			errs[i] = err
+			continue
+		}
+		r.save(l)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// check returns error of saving the Link like the unique indexes of
+// MongoDB do. Version of Link that is not stored is zero.
+func (r *LinkRepo) check(l service.Link) error {
+	if r.links[l.ID].Version != l.Version {
+		return service.ErrVersionConflict
+	}
+	for _, ID := range r.ids {
+		if ID == l.ID {
+			continue
+		}
+		for _, a := range l.Aliases {
+			if hasAlias(r.links[ID], a) {
+				return service.ErrInvalidAlias
+			}
+		}
+	}
+
+	return nil
+}
+
+// save saves a copy of the Link with incremented version.
+func (r *LinkRepo) save(l service.Link) {
+	if _, ok := r.links[l.ID]; !ok {
+		r.ids = append(r.ids, l.ID)
+	}
+	l.Version++
+	r.links[l.ID] = copyLink(l)
+}
+
+// FindByID finds a Link by ID.
+func (r *LinkRepo) FindByID(ctx context.Context, ID string) (service.Link, error) {
+	return r.find(func(l service.Link) bool {
+		return l.ID == ID
+	})
+}
+
+// FindByURL finds a Link by URL.
+func (r *LinkRepo) FindByURL(ctx context.Context, URL string) (service.Link, error) {
+	return r.find(func(l service.Link) bool {
+		return l.URL == URL
+	})
+}
+
+// FindByAlias finds a Link by alias.
+func (r *LinkRepo) FindByAlias(ctx context.Context, alias string) (service.Link, error) {
+	return r.find(func(l service.Link) bool {
+		return hasAlias(l, alias)
+	})
+}
+
+// FindByAliases finds Links that have any of aliases.
+func (r *LinkRepo) FindByAliases(ctx context.Context, aliases []string) ([]service.Link, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var links []service.Link
+	for _, ID := range r.ids {
+		l := r.links[ID]
+		for _, a := range aliases {
+			if hasAlias(l, a) {
+				links = append(links, copyLink(l))
+				break
+			}
+		}
+	}
+
+	return links, nil
+}
+
+// Each calls fn for every Link in the order they were saved first,
+// until fn returns error.
+func (r *LinkRepo) Each(ctx context.Context, fn func(service.Link) error) error {
+	for _, l := range r.All() {
+		if err := fn(l); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// List lists Links matching the filter ordered by creation time and ID.
+func (r *LinkRepo) List(ctx context.Context, f service.LinkFilter) ([]service.Link, error) {
+	var links []service.Link
+	for _, l := range r.All() {
+		if !f.Matches(l) {
+			continue
+		}
+		if f.After != nil {
+			p := service.PositionOf(l)
+			if (!f.Desc && !f.After.Less(p)) || (f.Desc && !p.Less(*f.After)) {
+				continue
+			}
+		}
+		links = append(links, l)
+	}
+
+	sort.Slice(links, func(i, j int) bool {
+		if f.Desc {
+			i, j = j, i
+		}
+		return service.PositionOf(links[i]).Less(service.PositionOf(links[j]))
+	})
+	if f.Limit > 0 && len(links) > f.Limit {
+		links = links[:f.Limit]
+	}
+
+	return links, nil
+}
+
+// All returns all Links in the order they were saved first.
+func (r *LinkRepo) All() []service.Link {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	links := make([]service.Link, 0, len(r.ids))
+	for _, ID := range r.ids {
+		links = append(links, copyLink(r.links[ID]))
+	}
+
+	return links
+}
+
+// Clear removes all Links.
+func (r *LinkRepo) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.links = make(map[string]service.Link)
+	r.ids = nil
+}
+
+// find returns copy of the first Link matching the predicate.
+func (r *LinkRepo) find(match func(service.Link) bool) (service.Link, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, ID := range r.ids {
+		if l := r.links[ID]; match(l) {
+			return copyLink(l), nil
+		}
+	}
+
+	return service.Link{}, service.ErrLinkNotFound
+}
+
+// hasAlias reports whether the Link has the alias.
+func hasAlias(l service.Link, alias string) bool {
+	for _, a := range l.Aliases {
+		if a == alias {
+			return true
+		}
+	}
+
+	return false
+}
+
+// copyLink returns copy of the Link that shares no memory with it.
+func copyLink(l service.Link) service.Link {
+	l.Aliases = append([]string(nil), l.Aliases...)
+	if l.Params != nil {
+		params := make(map[string]string, len(l.Params))
+		for k, v := range l.Params {
+			params[k] = v
+		}
+		l.Params = params
+	}
+	targets := l.Targets
+	l.Targets = nil
+	for _, t := range targets {
+		t.Devices = append([]string(nil), t.Devices...)
+		t.Languages = append([]string(nil), t.Languages...)
+		t.Countries = append([]string(nil), t.Countries...)
+		l.Targets = append(l.Targets, t)
+	}
+	l.Variants = append([]service.Variant(nil), l.Variants...)
+
+	return l
+}
@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"tinee/internal/service"
+)
+
+func TestLinkRepo_SaveMany(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	r := NewLinkRepo()
+	x := service.Link{ID: "x", URL: "https://x.xx", Aliases: []string{"xxxx"}}
+	is.NoErr(r.Save(ctx, x))
+
+	err := r.SaveMany(ctx, []service.Link{
+		{ID: "y", URL: "https://y.yy", Aliases: []string{"yyyy"}},
+		{ID: "z", URL: "https://z.zz", Aliases: []string{"zzzz", "xxxx"}},
+		x,
+		{ID: "w", URL: "https://w.ww", Aliases: []string{"yyyy"}},
+	})
+
+	is.Equal(service.LinkErrors{
+		1: service.ErrInvalidAlias,
+		2: service.ErrVersionConflict,
+		3: service.ErrInvalidAlias, // alias is taken by link saved earlier
+	}, err)
+	is.Equal(2, len(r.All()))
+	x.Version++
+	x.Aliases = append(x.Aliases, "aaaa")
+	is.NoErr(r.Save(ctx, x)) // aliases of the link itself are not taken
+	is.Equal(service.ErrInvalidAlias, r.Save(ctx, service.Link{ID: "v", Aliases: []string{"aaaa"}}))
+}
@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"sync"
+)
+
+// VariantCounter is the in-memory variant clicks counter.
+type VariantCounter struct {
+	mu     sync.Mutex
+	counts map[string]map[string]int64
+}
+
+// NewVariantCounter creates and returns a new VariantCounter instance.
+func NewVariantCounter() *VariantCounter {
+	return &VariantCounter{counts: make(map[string]map[string]int64)}
+}
+
+// Incr increments clicks of the link variant.
+func (c *VariantCounter) Incr(ctx context.Context, linkID, variant string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.counts[linkID] == nil {
+		c.counts[linkID] = make(map[string]int64)
+	}
+	c.counts[linkID][variant]++
+
+	return nil
+}
+
+// Counts returns clicks of all link variants by variant name.
+func (c *VariantCounter) Counts(ctx context.Context, linkID string) (map[string]int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counts := make(map[string]int64, len(c.counts[linkID]))
+	for variant, n := range c.counts[linkID] {
+		counts[variant] = n
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryInterceptor is gRPC interceptor counting and timing unary calls.
+func (m *Metrics) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	m.observeGRPC(info.FullMethod, start, err)
+
+	return resp, err
+}
+
+// StreamInterceptor is gRPC interceptor counting and timing streaming calls.
+func (m *Metrics) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	m.observeGRPC(info.FullMethod, start, err)
+
+	return err
+}
+
+// observeGRPC records the call of the method started at start.
+func (m *Metrics) observeGRPC(method string, start time.Time, err error) {
+	m.grpcRequests.WithLabelValues(method, status.Code(err).String()).Inc()
+	m.grpcDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
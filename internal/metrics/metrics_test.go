@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/matryer/is"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"tinee/internal/memory"
+	"tinee/internal/service"
+)
+
+func TestMetrics_HTTP(t *testing.T) {
+	is := is.New(t)
+	m := New()
+	r := chi.NewRouter()
+	r.Get("/{alias}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusFound)
+	})
+	r.Post("/api/v1/shorten", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{}"))
+	})
+	h := m.HTTP(r)
+
+	for _, target := range []string{"/xxxx", "/yyyy"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/shorten", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/x/y/z", nil))
+
+	is.Equal(2.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/{alias}", "302")))
+	is.Equal(1.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("POST", "/api/v1/shorten", "200")))
+	is.Equal(1.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", unmatchedRoute, "404")))
+	is.Equal(3, testutil.CollectAndCount(m.httpDuration))
+}
+
+func TestMetrics_UnaryInterceptor(t *testing.T) {
+	is := is.New(t)
+	m := New()
+	info := &grpc.UnaryServerInfo{FullMethod: "/tinee.TineeURL/UrlByAlias"}
+
+	handlers := []grpc.UnaryHandler{
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "link not found")
+		},
+	}
+	for _, h := range handlers {
+		_, _ = m.UnaryInterceptor(context.Background(), nil, info, h)
+	}
+
+	is.Equal(1.0, testutil.ToFloat64(m.grpcRequests.WithLabelValues(info.FullMethod, "OK")))
+	is.Equal(1.0, testutil.ToFloat64(m.grpcRequests.WithLabelValues(info.FullMethod, "NotFound")))
+}
+
+type failingCache struct {
+	service.LinkCache
+}
+
+func (c failingCache) Get(ctx context.Context, alias string) (service.Link, error) {
+	return service.Link{}, errors.New("unexpected error")
+}
+
+type failingRepo struct {
+	service.LinkRepo
+}
+
+func (r failingRepo) SaveMany(ctx context.Context, links []service.Link) error {
+	return service.LinkErrors{0: service.ErrVersionConflict, 1: errors.New("unexpected error")}
+}
+
+func TestLinkRepo(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	m := New()
+	r := NewLinkRepo(m, StoreMongoDB, memory.NewLinkRepo())
+
+	l := service.NewLink("https://x.xx")
+	is.NoErr(r.Save(ctx, l))
+	is.NoErr(r.SaveMany(ctx, []service.Link{service.NewLink("https://y.yy"), service.NewLink("https://z.zz")}))
+	l, err := r.FindByID(ctx, l.ID)
+	is.NoErr(err)
+	is.NoErr(r.Save(ctx, l)) // updated link is not created
+	is.Equal(service.ErrVersionConflict, r.Save(ctx, l))
+	err = r.SaveMany(ctx, []service.Link{l, service.NewLink("https://w.ww")}) // batch is saved partially
+	is.Equal(service.LinkErrors{0: service.ErrVersionConflict}, err)
+	_, err = r.FindByAlias(ctx, "none")
+	is.Equal(service.ErrLinkNotFound, err)
+	_ = NewLinkRepo(m, StoreMongoDB, failingRepo{}).SaveMany(ctx, []service.Link{l, l})
+
+	is.Equal(4.0, testutil.ToFloat64(m.linksCreated))
+	// expected outcomes are not errors of the store
+	is.Equal(0.0, testutil.ToFloat64(m.storeErrors.WithLabelValues(StoreMongoDB, "save")))
+	is.Equal(0.0, testutil.ToFloat64(m.storeErrors.WithLabelValues(StoreMongoDB, "find_by_alias")))
+	is.Equal(1.0, testutil.ToFloat64(m.storeErrors.WithLabelValues(StoreMongoDB, "save_many")))
+	is.Equal(4, testutil.CollectAndCount(m.storeOps))
+}
+
+func TestLinkCache(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	m := New()
+	c := NewLinkCache(m, StoreRedis, memory.NewLinkCache())
+
+	is.NoErr(c.Set(ctx, "xxxx", service.NewLink("https://x.xx")))
+	_, err := c.Get(ctx, "xxxx")
+	is.NoErr(err)
+	_, _ = c.Get(ctx, "yyyy")
+	_, _ = NewLinkCache(m, StoreRedis, failingCache{}).Get(ctx, "zzzz")
+
+	is.Equal(1.0, testutil.ToFloat64(m.cacheLookups.WithLabelValues("hit")))
+	is.Equal(2.0, testutil.ToFloat64(m.cacheLookups.WithLabelValues("miss")))
+	is.Equal(1.0, testutil.ToFloat64(m.storeErrors.WithLabelValues(StoreRedis, "get")))
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	is := is.New(t)
+	m := New()
+	m.linksCreated.Inc()
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	is.Equal(http.StatusOK, w.Code)
+	is.True(strings.Contains(w.Body.String(), "tinee_links_created_total 1"))
+	is.True(strings.Contains(w.Body.String(), "go_goroutines"))
+}
@@ -0,0 +1,207 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"tinee/internal/service"
+)
+
+const (
+	// StoreMongoDB is store label of MongoDB operations.
+	StoreMongoDB = "mongodb"
+	// StoreRedis is store label of Redis operations.
+	StoreRedis = "redis"
+)
+
+// observe records the operation of the store started at start.
+func (m *Metrics) observe(store, op string, start time.Time, err error) {
+	m.storeOps.WithLabelValues(store, op).Observe(time.Since(start).Seconds())
+	if failed(err) {
+		m.storeErrors.WithLabelValues(store, op).Inc()
+	}
+}
+
+// failed reports whether err is error of the store. Links that are not
+// found, taken aliases and version conflicts are expected outcomes of
+// operations, and batch failed if any of its links failed.
+func failed(err error) bool {
+	if errs, ok := err.(service.LinkErrors); ok {
+		for _, err := range errs {
+			if failed(err) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return err != nil && err != service.ErrLinkNotFound && err != service.ErrInvalidAlias && err != service.ErrVersionConflict
+}
+
+// LinkRepo is service.LinkRepo recording metrics of operations of the
+// underlying one, and links it created.
+type LinkRepo struct {
+	m     *Metrics
+	store string
+	r     service.LinkRepo
+}
+
+// NewLinkRepo creates and returns a new LinkRepo instance recording
+// operations of r with the store label.
+func NewLinkRepo(m *Metrics, store string, r service.LinkRepo) *LinkRepo {
+	return &LinkRepo{m: m, store: store, r: r}
+}
+
+// Save implements service.LinkRepo interface.
+func (r *LinkRepo) Save(ctx context.Context, l service.Link) (err error) {
+	defer func(start time.Time) { r.m.observe(r.store, "save", start, err) }(time.Now())
+	if err = r.r.Save(ctx, l); err == nil && l.Version == 0 {
+		r.m.linksCreated.Inc()
+	}
+
+	return err
+}
+
+// SaveMany implements service.LinkRepo interface.
+func (r *LinkRepo) SaveMany(ctx context.Context, links []service.Link) (err error) {
+	defer func(start time.Time) { r.m.observe(r.store, "save_many", start, err) }(time.Now())
+	err = r.r.SaveMany(ctx, links)
+	errs, partial := err.(service.LinkErrors)
+	if err != nil && !partial {
+		return err
+	}
+	// links of partially saved batch are created unless they failed
+	for i, l := range links {
+		if _, ok := errs[i]; !ok && l.Version == 0 {
+			r.m.linksCreated.Inc()
+		}
+	}
+
+	return err
+}
+
+// FindByID implements service.LinkRepo interface.
+func (r *LinkRepo) FindByID(ctx context.Context, ID string) (l service.Link, err error) {
+	defer func(start time.Time) { r.m.observe(r.store, "find_by_id", start, err) }(time.Now())
+
+	return r.r.FindByID(ctx, ID)
+}
+
+// FindByURL implements service.LinkRepo interface.
+func (r *LinkRepo) FindByURL(ctx context.Context, URL string) (l service.Link, err error) {
+	defer func(start time.Time) { r.m.observe(r.store, "find_by_url", start, err) }(time.Now())
+
+	return r.r.FindByURL(ctx, URL)
+}
+
+// FindByAlias implements service.LinkRepo interface.
+func (r *LinkRepo) FindByAlias(ctx context.Context, alias string) (l service.Link, err error) {
+	defer func(start time.Time) { r.m.observe(r.store, "find_by_alias", start, err) }(time.Now())
+
+	return r.r.FindByAlias(ctx, alias)
+}
+
+// FindByAliases implements service.LinkRepo interface.
+func (r *LinkRepo) FindByAliases(ctx context.Context, aliases []string) (links []service.Link, err error) {
+	defer func(start time.Time) { r.m.observe(r.store, "find_by_aliases", start, err) }(time.Now())
+
+	return r.r.FindByAliases(ctx, aliases)
+}
+
+// Each implements service.LinkRepo interface.
+func (r *LinkRepo) Each(ctx context.Context, fn func(service.Link) error) (err error) {
+	defer func(start time.Time) { r.m.observe(r.store, "each", start, err) }(time.Now())
+
+	return r.r.Each(ctx, fn)
+}
+
+// List implements service.LinkRepo interface.
+func (r *LinkRepo) List(ctx context.Context, f service.LinkFilter) (links []service.Link, err error) {
+	defer func(start time.Time) { r.m.observe(r.store, "list", start, err) }(time.Now())
+
+	return r.r.List(ctx, f)
+}
+
+// LinkCache is service.LinkCache recording metrics of operations of the
+// underlying one, and hits and misses of lookups by alias.
+type LinkCache struct {
+	m     *Metrics
+	store string
+	c     service.LinkCache
+}
+
+// NewLinkCache creates and returns a new LinkCache instance recording
+// operations of c with the store label.
+func NewLinkCache(m *Metrics, store string, c service.LinkCache) *LinkCache {
+	return &LinkCache{m: m, store: store, c: c}
+}
+
+// Set implements service.LinkCache interface.
+func (c *LinkCache) Set(ctx context.Context, alias string, l service.Link) (err error) {
+	defer func(start time.Time) { c.m.observe(c.store, "set", start, err) }(time.Now())
+
+	return c.c.Set(ctx, alias, l)
+}
+
+// Get implements service.LinkCache interface.
+func (c *LinkCache) Get(ctx context.Context, alias string) (l service.Link, err error) {
+	defer func(start time.Time) { c.m.observe(c.store, "get", start, err) }(time.Now())
+
+	l, err = c.c.Get(ctx, alias)
+	if err == nil {
+		c.m.cacheLookups.WithLabelValues("hit").Inc()
+	} else {
+		c.m.cacheLookups.WithLabelValues("miss").Inc()
+	}
+
+	return l, err
+}
+
+// SetMany implements service.LinkCache interface.
+func (c *LinkCache) SetMany(ctx context.Context, links map[string]service.Link) (err error) {
+	defer func(start time.Time) { c.m.observe(c.store, "set_many", start, err) }(time.Now())
+
+	return c.c.SetMany(ctx, links)
+}
+
+// GetMany implements service.LinkCache interface.
+func (c *LinkCache) GetMany(ctx context.Context, aliases []string) (links map[string]service.Link, err error) {
+	defer func(start time.Time) { c.m.observe(c.store, "get_many", start, err) }(time.Now())
+
+	return c.c.GetMany(ctx, aliases)
+}
+
+// Delete implements service.LinkCache interface.
+func (c *LinkCache) Delete(ctx context.Context, aliases []string) (err error) {
+	defer func(start time.Time) { c.m.observe(c.store, "delete", start, err) }(time.Now())
+
+	return c.c.Delete(ctx, aliases)
+}
+
+// VariantCounter is service.VariantCounter recording metrics of
+// operations of the underlying one.
+type VariantCounter struct {
+	m     *Metrics
+	store string
+	vc    service.VariantCounter
+}
+
+// NewVariantCounter creates and returns a new VariantCounter instance
+// recording operations of vc with the store label.
+func NewVariantCounter(m *Metrics, store string, vc service.VariantCounter) *VariantCounter {
+	return &VariantCounter{m: m, store: store, vc: vc}
+}
+
+// Incr implements service.VariantCounter interface.
+func (c *VariantCounter) Incr(ctx context.Context, linkID, variant string) (err error) {
+	defer func(start time.Time) { c.m.observe(c.store, "incr", start, err) }(time.Now())
+
+	return c.vc.Incr(ctx, linkID, variant)
+}
+
+// Counts implements service.VariantCounter interface.
+func (c *VariantCounter) Counts(ctx context.Context, linkID string) (counts map[string]int64, err error) {
+	defer func(start time.Time) { c.m.observe(c.store, "counts", start, err) }(time.Now())
+
+	return c.vc.Counts(ctx, linkID)
+}
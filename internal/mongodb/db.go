@@ -6,8 +6,10 @@ import (
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 
 	"tinee/internal/config"
+	"tinee/internal/tls"
 )
 
 // DB represents MongoDB database.
@@ -24,6 +26,13 @@ func Open(ctx context.Context, cfg config.MongoDB) (*DB, error) {
 			Password: cfg.Password,
 		},
 	)
+	if cfg.TLS {
+		tc, err := tls.ClientConfig(cfg.TLSCAFile, cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		clientOptions.SetTLSConfig(tc)
+	}
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
 		return nil, err
@@ -32,6 +41,11 @@ func Open(ctx context.Context, cfg config.MongoDB) (*DB, error) {
 	return &DB{cfg: cfg, client: client}, client.Ping(ctx, nil)
 }
 
+// Ping checks connection to the primary of the database.
+func (db *DB) Ping(ctx context.Context) error {
+	return db.client.Ping(ctx, readpref.Primary())
+}
+
 // Collection returns collection by name.
 func (db *DB) Collection(name string) *mongo.Collection {
 	return db.client.Database(db.cfg.DbName).Collection(name)
@@ -2,24 +2,103 @@ package mongodb
 
 import (
 	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"tinee/internal/service"
 )
 
-// Link is service.Link entity for the database.
+// Link is the document of service.Link in the database.
 type Link struct {
-	ID      string   `mongodb:"_id"`
-	URL     string   `mongodb:"url"`
-	Aliases []string `mongodb:"aliases"`
+	ID         string            `bson:"_id"`
+	URL        string            `bson:"url"`
+	Aliases    []string          `bson:"aliases"`
+	Forwarding Forwarding        `bson:"forwarding"`
+	Params     map[string]string `bson:"params,omitempty"`
+	Targets    []Target          `bson:"targets,omitempty"`
+	Variants   []Variant         `bson:"variants,omitempty"`
+	CreatedAt  time.Time         `bson:"createdAt"`
+	UpdatedAt  time.Time         `bson:"updatedAt"`
+	// Version is incremented on every save, the document is replaced only
+	// if its version is the version of the saved service.Link.
+	Version int64 `bson:"version"`
+}
+
+// Forwarding is the document of service.Forwarding.
+type Forwarding struct {
+	Path  bool   `bson:"path"`
+	Query string `bson:"query"`
+}
+
+// Target is the document of service.Target.
+type Target struct {
+	URL       string   `bson:"url"`
+	Devices   []string `bson:"devices"`
+	Languages []string `bson:"languages"`
+	Countries []string `bson:"countries"`
+}
+
+// Variant is the document of service.Variant.
+type Variant struct {
+	Name   string `bson:"name"`
+	URL    string `bson:"url"`
+	Weight int    `bson:"weight"`
+}
+
+// newLink returns the document of the service.Link.
+func newLink(l service.Link) Link {
+	d := Link{
+		ID:         l.ID,
+		URL:        l.URL,
+		Aliases:    l.Aliases,
+		Forwarding: Forwarding{Path: l.Forwarding.Path, Query: string(l.Forwarding.Query)},
+		Params:     l.Params,
+		CreatedAt:  l.CreatedAt,
+		Version:    l.Version,
+	}
+	for _, t := range l.Targets {
+		d.Targets = append(d.Targets, Target(t))
+	}
+	for _, v := range l.Variants {
+		d.Variants = append(d.Variants, Variant(v))
+	}
+
+	return d
+}
+
+// link returns service.Link of the document.
+func (d Link) link() service.Link {
+	l := service.Link{
+		ID:         d.ID,
+		URL:        d.URL,
+		Aliases:    d.Aliases,
+		Forwarding: service.Forwarding{Path: d.Forwarding.Path, Query: service.QueryPolicy(d.Forwarding.Query)},
+		Params:     d.Params,
+		CreatedAt:  d.CreatedAt,
+		Version:    d.Version,
+	}
+	for _, t := range d.Targets {
+		l.Targets = append(l.Targets, service.Target(t))
+	}
+	for _, v := range d.Variants {
+		l.Variants = append(l.Variants, service.Variant(v))
+	}
+
+	return l
 }
 
 // LinkRepo is the link repository.
 type LinkRepo struct {
 	links *mongo.Collection
+	// now returns the current time, links are updated at.
+	now func() time.Time
 }
 
 // LinkCollectionName is the name of link collection.
@@ -27,35 +106,257 @@ const LinkCollectionName = "links"
 
 // NewLinkRepo creates and returns a new LinkRepo instance.
 func NewLinkRepo(db *DB) *LinkRepo {
-	return &LinkRepo{links: db.Collection(LinkCollectionName)}
+	return &LinkRepo{links: db.Collection(LinkCollectionName), now: time.Now}
 }
 
-// Save saves a Link to the database.
+// Save inserts a Link of zero version, or replaces the stored Link of
+// the same version. It returns service.ErrVersionConflict if the stored
+// Link has other version, and service.ErrInvalidAlias if any alias of
+// the Link is taken by another Link.
 func (r *LinkRepo) Save(ctx context.Context, l service.Link) error {
-	opts := options.Update().SetUpsert(true)
-	filter := bson.M{"_id": l.ID}
-	update := bson.M{"$set": l}
+	d := r.document(l)
+	if l.Version == 0 {
+		_, err := r.links.InsertOne(ctx, d)
+		return writeError(err)
+	}
+
+	res, err := r.links.ReplaceOne(ctx, bson.M{"_id": l.ID, "version": l.Version}, d)
+	if err != nil {
+		return writeError(err)
+	}
+	if res.MatchedCount == 0 {
+		return service.ErrVersionConflict
+	}
 
-	_, err := r.links.UpdateOne(ctx, filter, update, opts)
-	return err
+	return nil
 }
 
-// FindByURL finds a Link by URL.
-func (r *LinkRepo) FindByURL(ctx context.Context, URL string) (l service.Link, err error) {
-	err = r.links.FindOne(ctx, bson.M{"url": URL}).Decode(&l)
-	if err == mongo.ErrNoDocuments {
-		return l, service.ErrLinkNotFound
+// SaveMany saves Links to the database in a single unordered bulk write,
+// like Save does with every Link. If some of Links were not saved, it
+// returns service.LinkErrors with errors of them.
+func (r *LinkRepo) SaveMany(ctx context.Context, links []service.Link) error {
+	if len(links) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(links))
+	docs := make([]Link, 0, len(links))
+	var replaced int64
+	for _, l := range links {
+		d := r.document(l)
+		docs = append(docs, d)
+		if l.Version == 0 {
+			models = append(models, mongo.NewInsertOneModel().SetDocument(d))
+			continue
+		}
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": l.ID, "version": l.Version}).
+			SetReplacement(d),
+		)
+		replaced++
+	}
+
+	res, err := r.links.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	errs := make(service.LinkErrors)
+	var bwe mongo.BulkWriteException
+	if errors.As(err, &bwe) && bwe.WriteConcernError == nil {
+		for _, we := range bwe.WriteErrors {
+			errs[we.Index] = writeError(mongo.WriteException{WriteErrors: mongo.WriteErrors{we.WriteError}})
+		}
+	} else if err != nil {
+		return writeError(err)
 	}
 
-	return l, err
+	if res.MatchedCount < replaced {
+		r.conflicts(ctx, docs, errs)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// conflicts adds service.ErrVersionConflict to errs for replaced documents
+// that were not written. Bulk write reports only the number of replaced
+// documents, so they are found by versions and update times of docs. If
+// they can't be found, error of the lookup is added for all of them.
+func (r *LinkRepo) conflicts(ctx context.Context, docs []Link, errs service.LinkErrors) {
+	// inserted documents are of the first version
+	pending := make(map[string]Link)
+	var IDs []string
+	for i, d := range docs {
+		if d.Version > 1 && errs[i] == nil {
+			pending[d.ID] = d
+			IDs = append(IDs, d.ID)
+		}
+	}
+	if len(IDs) == 0 {
+		return
+	}
+
+	stored, err := r.versions(ctx, IDs)
+	for _, d := range stored {
+		if p := pending[d.ID]; p.Version == d.Version && p.UpdatedAt.Equal(d.UpdatedAt) {
+			delete(pending, d.ID)
+		}
+	}
+
+	for i, d := range docs {
+		if _, ok := pending[d.ID]; !ok {
+			continue
+		} else if err != nil {
+			errs[i] = err
+		} else {
+			errs[i] = service.ErrVersionConflict
+		}
+	}
+}
+
+// versions returns versions and update times of documents with IDs.
+func (r *LinkRepo) versions(ctx context.Context, IDs []string) ([]Link, error) {
+	cur, err := r.links.Find(ctx, bson.M{"_id": bson.M{"$in": IDs}},
+		options.Find().SetProjection(bson.M{"version": 1, "updatedAt": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var stored []Link
+	if err := cur.All(ctx, &stored); err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+// document returns the document the Link is saved as.
+func (r *LinkRepo) document(l service.Link) Link {
+	d := newLink(l)
+	// time is stored with millisecond precision
+	d.UpdatedAt = r.now().UTC().Truncate(time.Millisecond)
+	d.Version++
+
+	return d
+}
+
+// writeError maps duplicate key errors of writes to errors of service.
+func writeError(err error) error {
+	if !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	// the other unique index is _id, the Link was inserted concurrently
+	if strings.Contains(err.Error(), "index: "+aliasesIndex+" ") {
+		return service.ErrInvalidAlias
+	}
+
+	return service.ErrVersionConflict
+}
+
+// FindByID finds a Link by ID.
+func (r *LinkRepo) FindByID(ctx context.Context, ID string) (service.Link, error) {
+	return r.findOne(ctx, bson.M{"_id": ID})
+}
+
+// FindByURL finds a Link by URL.
+func (r *LinkRepo) FindByURL(ctx context.Context, URL string) (service.Link, error) {
+	return r.findOne(ctx, bson.M{"url": URL})
 }
 
 // FindByAlias finds a Link by alias.
-func (r *LinkRepo) FindByAlias(ctx context.Context, alias string) (l service.Link, err error) {
-	err = r.links.FindOne(ctx, bson.M{"aliases": bson.M{"$in": []string{alias}}}).Decode(&l)
+func (r *LinkRepo) FindByAlias(ctx context.Context, alias string) (service.Link, error) {
+	return r.findOne(ctx, bson.M{"aliases": alias})
+}
+
+// FindByAliases finds Links having any of aliases.
+func (r *LinkRepo) FindByAliases(ctx context.Context, aliases []string) ([]service.Link, error) {
+	return r.find(ctx, bson.M{"aliases": bson.M{"$in": aliases}})
+}
+
+// Each calls fn for every Link in the database until fn returns error.
+func (r *LinkRepo) Each(ctx context.Context, fn func(service.Link) error) error {
+	cur, err := r.links.Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var d Link
+		if err = cur.Decode(&d); err != nil {
+			return err
+		}
+		if err = fn(d.link()); err != nil {
+			return err
+		}
+	}
+
+	return cur.Err()
+}
+
+// List lists Links matching the filter ordered by creation time and ID.
+func (r *LinkRepo) List(ctx context.Context, f service.LinkFilter) ([]service.Link, error) {
+	conditions := bson.A{}
+	if f.Domain != "" {
+		// host of URL is the domain or its subdomain
+		pattern := `^[a-z][a-z0-9+.-]*://([^/?#]*@)?([^/?#@]*\.)?` + regexp.QuoteMeta(f.Domain) + `(:[0-9]*)?([/?#]|$)`
+		conditions = append(conditions, bson.M{"url": primitive.Regex{Pattern: pattern, Options: "i"}})
+	}
+	if f.AliasPrefix != "" {
+		conditions = append(conditions, bson.M{"aliases": primitive.Regex{Pattern: "^" + regexp.QuoteMeta(f.AliasPrefix)}})
+	}
+	if f.Search != "" {
+		conditions = append(conditions, bson.M{"url": primitive.Regex{Pattern: regexp.QuoteMeta(f.Search), Options: "i"}})
+	}
+
+	op, order := "$gt", 1
+	if f.Desc {
+		op, order = "$lt", -1
+	}
+	if f.After != nil {
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"createdAt": bson.M{op: f.After.CreatedAt}},
+			bson.M{"createdAt": f.After.CreatedAt, "_id": bson.M{op: f.After.ID}},
+		}})
+	}
+
+	filter := bson.M{}
+	if len(conditions) > 0 {
+		filter["$and"] = conditions
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: order}, {Key: "_id", Value: order}}).
+		SetLimit(int64(f.Limit))
+
+	return r.find(ctx, filter, opts)
+}
+
+// findOne finds the first Link matching the filter.
+func (r *LinkRepo) findOne(ctx context.Context, filter bson.M) (service.Link, error) {
+	var d Link
+	err := r.links.FindOne(ctx, filter).Decode(&d)
 	if err == mongo.ErrNoDocuments {
-		return l, service.ErrLinkNotFound
+		return service.Link{}, service.ErrLinkNotFound
+	} else if err != nil {
+		return service.Link{}, err
+	}
+
+	return d.link(), nil
+}
+
+// find finds all Links matching the filter.
+func (r *LinkRepo) find(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]service.Link, error) {
+	cur, err := r.links.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []Link
+	if err = cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	links := make([]service.Link, 0, len(docs))
+	for _, d := range docs {
+		links = append(links, d.link())
 	}
 
-	return l, err
+	return links, nil
 }
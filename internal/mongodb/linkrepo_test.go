@@ -0,0 +1,208 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"tinee/internal/service"
+)
+
+var (
+	// now is the time links are saved at in tests.
+	now = time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	takenAlias = mtest.WriteError{
+		Code:    11000,
+		Message: `E11000 duplicate key error collection: tinee.links index: aliases dup key: { aliases: "xxxx" }`,
+	}
+	takenID = mtest.WriteError{
+		Code:    11000,
+		Message: `E11000 duplicate key error collection: tinee.links index: _id_ dup key: { _id: "x" }`,
+	}
+)
+
+// mockLinkRepo returns LinkRepo of the mock client of mt saving links
+// at now.
+func mockLinkRepo(mt *mtest.T) *LinkRepo {
+	r := NewLinkRepo(mockDB(mt))
+	r.now = func() time.Time { return now }
+
+	return r
+}
+
+// writtenResponse returns response of the lookup of written documents
+// with versions by ID. Documents of other versions were written by
+// another save.
+func writtenResponse(versions map[string]int64) bson.D {
+	docs := make([]bson.D, 0, len(versions))
+	for ID, v := range versions {
+		docs = append(docs, bson.D{
+			{Key: "_id", Value: ID},
+			{Key: "version", Value: v},
+			{Key: "updatedAt", Value: now},
+		})
+	}
+
+	return mtest.CreateCursorResponse(0, "tinee."+LinkCollectionName, mtest.FirstBatch, docs...)
+}
+
+func TestLinkRepo_Save(t *testing.T) {
+	testcases := []struct {
+		name     string
+		version  int64
+		response bson.D
+		expErr   error
+	}{
+		{
+			name:     "new link is inserted",
+			version:  0,
+			response: mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+		},
+		{
+			name:     "new link of taken alias is not inserted",
+			version:  0,
+			response: mtest.CreateWriteErrorsResponse(takenAlias),
+			expErr:   service.ErrInvalidAlias,
+		},
+		{
+			name:     "link inserted concurrently is conflict",
+			version:  0,
+			response: mtest.CreateWriteErrorsResponse(takenID),
+			expErr:   service.ErrVersionConflict,
+		},
+		{
+			name:     "link of stored version is replaced",
+			version:  1,
+			response: mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		},
+		{
+			name:     "link of other version is conflict",
+			version:  1,
+			response: mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+			expErr:   service.ErrVersionConflict,
+		},
+		{
+			name:     "link of taken alias is not replaced",
+			version:  1,
+			response: mtest.CreateWriteErrorsResponse(takenAlias),
+			expErr:   service.ErrInvalidAlias,
+		},
+	}
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+	for _, tc := range testcases {
+		tc := tc
+		mt.Run(tc.name, func(mt *mtest.T) {
+			is := is.New(mt)
+			mt.AddMockResponses(tc.response)
+
+			err := mockLinkRepo(mt).Save(context.Background(), service.Link{ID: "x", Aliases: []string{"xxxx"}, Version: tc.version})
+
+			is.Equal(tc.expErr, err)
+		})
+	}
+}
+
+func TestLinkRepo_SaveMany(t *testing.T) {
+	testcases := []struct {
+		name      string
+		versions  []int64
+		responses []bson.D
+		expErr    error
+	}{
+		{
+			name:     "links are saved",
+			versions: []int64{0, 1},
+			responses: []bson.D{
+				mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+				mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			},
+		},
+		{
+			name:     "links of other versions are conflicts",
+			versions: []int64{1, 0, 3},
+			responses: []bson.D{
+				// inserts are written before replacements
+				mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+				mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+				// link 0 was written, link 2 was saved by another save
+				writtenResponse(map[string]int64{"0": 2, "2": 5}),
+			},
+			expErr: service.LinkErrors{2: service.ErrVersionConflict},
+		},
+		{
+			name:     "write errors are mapped to links",
+			versions: []int64{0, 1, 0},
+			responses: []bson.D{
+				// second insert is of link 2
+				mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 1, Code: takenAlias.Code, Message: takenAlias.Message}),
+				mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			},
+			expErr: service.LinkErrors{2: service.ErrInvalidAlias},
+		},
+		{
+			name:     "failed replacements are not looked up",
+			versions: []int64{1},
+			responses: []bson.D{
+				mtest.CreateWriteErrorsResponse(takenAlias),
+			},
+			expErr: service.LinkErrors{0: service.ErrInvalidAlias},
+		},
+		{
+			name:     "nothing is saved without links",
+			versions: nil,
+		},
+	}
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+	for _, tc := range testcases {
+		tc := tc
+		mt.Run(tc.name, func(mt *mtest.T) {
+			is := is.New(mt)
+			mt.AddMockResponses(tc.responses...)
+			links := make([]service.Link, 0, len(tc.versions))
+			for i, v := range tc.versions {
+				links = append(links, service.Link{ID: string(rune('0' + i)), Version: v})
+			}
+
+			err := mockLinkRepo(mt).SaveMany(context.Background(), links)
+
+			is.Equal(tc.expErr, err)
+		})
+	}
+}
+
+func TestLinkRepo_SaveManyLookupError(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("errors of failed lookup are set to not written links", func(mt *mtest.T) {
+		is := is.New(mt)
+		mt.AddMockResponses(
+			mtest.CreateWriteErrorsResponse(takenAlias),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 11601, Name: "Interrupted", Message: "interrupted"}),
+		)
+
+		err := mockLinkRepo(mt).SaveMany(context.Background(), []service.Link{
+			{ID: "x", Version: 0},
+			{ID: "y", Version: 1},
+			{ID: "z", Version: 1},
+		})
+
+		errs, ok := err.(service.LinkErrors)
+		is.True(ok)
+		is.Equal(3, len(errs))
+		is.Equal(service.ErrInvalidAlias, errs[0])
+		// written link can't be told from the conflicting one
+		is.True(errs[1] != nil && errs[1] != service.ErrVersionConflict)
+		is.Equal(errs[1], errs[2])
+	})
+}
@@ -0,0 +1,166 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationCollectionName is the name of collection of applied migrations.
+const MigrationCollectionName = "migrations"
+
+// aliasesIndex is the name of unique index of link aliases.
+const aliasesIndex = "aliases"
+
+// Migration is a versioned change of the database schema or data.
+// Migrations must be idempotent, since instances started at the same time
+// could apply the same migration concurrently.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *DB) error
+}
+
+// MigrationStatus is the status of a migration.
+type MigrationStatus struct {
+	Migration
+	// AppliedAt is zero if the migration was not applied.
+	AppliedAt time.Time
+}
+
+// Migrations are migrations of the database ordered by version. New
+// migrations are appended with the next version, applied ones must not
+// be changed.
+var Migrations = []Migration{
+	{
+		Version:     1,
+		Description: "create index of link URLs and unique index of link aliases",
+		Up:          createLinkIndexes,
+	},
+	{
+		Version:     2,
+		Description: "store links as documents with timestamps and versions",
+		Up:          migrateLinkDocuments,
+	},
+	{
+		Version:     3,
+		Description: "create index of link creation time for listing",
+		Up:          createListIndex,
+	},
+}
+
+// appliedMigration is a record of an applied migration.
+type appliedMigration struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"appliedAt"`
+}
+
+// Migrate applies migrations that were not applied yet in order of their
+// versions and returns versions of applied ones.
+func Migrate(ctx context.Context, db *DB, migrations []Migration) ([]int, error) {
+	statuses, err := MigrationStatuses(ctx, db, migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	records := db.Collection(MigrationCollectionName)
+	var applied []int
+	for _, s := range statuses {
+		if !s.AppliedAt.IsZero() {
+			continue
+		}
+		if err = s.Up(ctx, db); err != nil {
+			return applied, fmt.Errorf("migration %d: %w", s.Version, err)
+		}
+
+		_, err = records.InsertOne(ctx, appliedMigration{
+			Version:     s.Version,
+			Description: s.Description,
+			AppliedAt:   time.Now().UTC(),
+		})
+		// the migration could have been applied by another instance
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return applied, fmt.Errorf("migration %d: %w", s.Version, err)
+		}
+		applied = append(applied, s.Version)
+	}
+
+	return applied, nil
+}
+
+// MigrationStatuses returns statuses of migrations ordered by version.
+func MigrationStatuses(ctx context.Context, db *DB, migrations []Migration) ([]MigrationStatus, error) {
+	cur, err := db.Collection(MigrationCollectionName).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	var records []appliedMigration
+	if err = cur.All(ctx, &records); err != nil {
+		return nil, err
+	}
+	appliedAt := make(map[int]time.Time, len(records))
+	for _, r := range records {
+		appliedAt[r.Version] = r.AppliedAt
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		statuses = append(statuses, MigrationStatus{Migration: m, AppliedAt: appliedAt[m.Version]})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// createLinkIndexes creates indexes of links looked up by URL and alias.
+// Aliases index is unique, so an alias can't be taken by two links.
+func createLinkIndexes(ctx context.Context, db *DB) error {
+	_, err := db.Collection(LinkCollectionName).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "url", Value: 1}},
+			Options: options.Index().SetName("url"),
+		},
+		{
+			Keys:    bson.D{{Key: "aliases", Value: 1}},
+			Options: options.Index().SetName(aliasesIndex).SetUnique(true),
+		},
+	})
+
+	return err
+}
+
+// migrateLinkDocuments migrates links stored as service.Link to Link
+// documents. Field names of service.Link were lowercased, so the ID was
+// duplicated and timestamps and versions are missing. Creation time of
+// the links is unknown, it is set to the current time so they are not
+// skipped by listing.
+func migrateLinkDocuments(ctx context.Context, db *DB) error {
+	_, err := db.Collection(LinkCollectionName).UpdateMany(ctx,
+		bson.M{"version": bson.M{"$exists": false}},
+		bson.M{
+			"$set":         bson.M{"version": 1},
+			"$unset":       bson.M{"id": ""},
+			"$currentDate": bson.M{"createdAt": true, "updatedAt": true},
+		},
+	)
+
+	return err
+}
+
+// createListIndex creates index of links listed by creation time.
+func createListIndex(ctx context.Context, db *DB) error {
+	_, err := db.Collection(LinkCollectionName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}},
+		Options: options.Index().SetName("createdAt_id"),
+	})
+
+	return err
+}
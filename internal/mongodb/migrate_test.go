@@ -0,0 +1,140 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"tinee/internal/config"
+)
+
+// mockDB returns DB of the mock client of mt.
+func mockDB(mt *mtest.T) *DB {
+	return &DB{cfg: config.MongoDB{DbName: "tinee"}, client: mt.Client}
+}
+
+// recordsResponse returns response of migrations collection with records
+// of applied migrations of versions.
+func recordsResponse(versions ...int) bson.D {
+	records := make([]bson.D, 0, len(versions))
+	for _, v := range versions {
+		records = append(records, bson.D{{Key: "_id", Value: v}, {Key: "appliedAt", Value: time.Now()}})
+	}
+
+	return mtest.CreateCursorResponse(0, "tinee."+MigrationCollectionName, mtest.FirstBatch, records...)
+}
+
+func TestMigrate(t *testing.T) {
+	inserted := mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1})
+	duplicate := mtest.CreateWriteErrorsResponse(mtest.WriteError{
+		Code:    11000,
+		Message: "E11000 duplicate key error collection: tinee.migrations index: _id_ dup key: { _id: 1 }",
+	})
+	errUp := errors.New("up failed")
+
+	testcases := []struct {
+		name       string
+		responses  []bson.D
+		upErr      error
+		expApplied []int
+		expErr     error
+	}{
+		{
+			name:       "migrations are applied in order of versions",
+			responses:  []bson.D{recordsResponse(), inserted, inserted},
+			expApplied: []int{1, 2},
+		},
+		{
+			name:       "applied migrations are skipped",
+			responses:  []bson.D{recordsResponse(1), inserted},
+			expApplied: []int{2},
+		},
+		{
+			name:       "nothing is applied when all migrations were applied",
+			responses:  []bson.D{recordsResponse(2, 1)},
+			expApplied: nil,
+		},
+		{
+			name:       "migration recorded by another instance is applied",
+			responses:  []bson.D{recordsResponse(), duplicate, inserted},
+			expApplied: []int{1, 2},
+		},
+		{
+			name:       "migrating stops at failed migration",
+			responses:  []bson.D{recordsResponse(), inserted},
+			upErr:      errUp,
+			expApplied: []int{1},
+			expErr:     errUp,
+		},
+	}
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+	for _, tc := range testcases {
+		tc := tc
+		mt.Run(tc.name, func(mt *mtest.T) {
+			is := is.New(mt)
+			mt.AddMockResponses(tc.responses...)
+			var calls []int
+			up := func(version int, err error) func(context.Context, *DB) error {
+				return func(context.Context, *DB) error {
+					calls = append(calls, version)
+					return err
+				}
+			}
+			migrations := []Migration{
+				{Version: 2, Up: up(2, tc.upErr)},
+				{Version: 1, Up: up(1, nil)},
+			}
+
+			applied, err := Migrate(context.Background(), mockDB(mt), migrations)
+
+			is.True(errors.Is(err, tc.expErr))
+			is.Equal(tc.expApplied, applied)
+			// only migrations that were not applied yet are run
+			if tc.expErr == nil {
+				is.Equal(tc.expApplied, calls)
+			}
+		})
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("migrations are applied once", func(mt *mtest.T) {
+		is := is.New(mt)
+		ctx := context.Background()
+		db := mockDB(mt)
+		ok := mtest.CreateSuccessResponse()
+		inserted := mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1})
+		mt.AddMockResponses(recordsResponse(), ok, inserted, ok, inserted, ok, inserted)
+
+		applied, err := Migrate(ctx, db, Migrations)
+
+		is.NoErr(err)
+		is.Equal([]int{1, 2, 3}, applied)
+		var commands []string
+		for _, e := range mt.GetAllStartedEvents() {
+			commands = append(commands, e.CommandName)
+		}
+		is.Equal([]string{
+			"find",
+			"createIndexes", "insert",
+			"update", "insert",
+			"createIndexes", "insert",
+		}, commands)
+
+		mt.AddMockResponses(recordsResponse(1, 2, 3))
+		applied, err = Migrate(ctx, db, Migrations)
+
+		is.NoErr(err)
+		is.Equal(0, len(applied))
+	})
+}
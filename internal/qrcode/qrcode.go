@@ -0,0 +1,195 @@
+// Package qrcode renders QR codes as PNG and SVG images.
+package qrcode
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+
+	"rsc.io/qr"
+)
+
+const (
+	// FormatPNG is PNG image format.
+	FormatPNG = "png"
+	// FormatSVG is SVG image format.
+	FormatSVG = "svg"
+)
+
+const (
+	// DefaultSize is default image size in pixels.
+	DefaultSize = 256
+	// MaxSize is max image size in pixels.
+	MaxSize = 4096
+	// DefaultLevel is default error correction level.
+	DefaultLevel = "M"
+	// DefaultForeground is default color of dark modules.
+	DefaultForeground = "000000"
+	// DefaultBackground is default color of light modules.
+	DefaultBackground = "ffffff"
+	// quietZoneSize is the size of quiet zone in modules.
+	quietZoneSize = 4
+)
+
+// ErrInvalidOptions is returned when invalid options were provided.
+var ErrInvalidOptions = errors.New("invalid QR code options")
+
+// levels are error correction levels by name.
+var levels = map[string]qr.Level{"L": qr.L, "M": qr.M, "Q": qr.Q, "H": qr.H}
+
+// Options are QR code rendering options, zero values are replaced
+// by defaults.
+type Options struct {
+	// Format is image format, FormatPNG or FormatSVG.
+	Format string
+	// Size is width and height of image in pixels.
+	Size int
+	// Level is error correction level, one of L, M, Q or H.
+	Level string
+	// Foreground is color of dark modules in RRGGBB hex format.
+	Foreground string
+	// Background is color of light modules in RRGGBB hex format.
+	Background string
+	// QuietZone adds the standard 4 modules wide margin around the code.
+	QuietZone bool
+}
+
+// ContentType returns MIME type of image rendered with the options.
+func (o Options) ContentType() string {
+	if o.Format == FormatSVG {
+		return "image/svg+xml"
+	}
+
+	return "image/png"
+}
+
+// Render renders QR code of content.
+func Render(content string, o Options) ([]byte, error) {
+	if o.Format == "" {
+		o.Format = FormatPNG
+	}
+	if o.Size == 0 {
+		o.Size = DefaultSize
+	}
+	if o.Level == "" {
+		o.Level = DefaultLevel
+	}
+	if o.Foreground == "" {
+		o.Foreground = DefaultForeground
+	}
+	if o.Background == "" {
+		o.Background = DefaultBackground
+	}
+
+	level, ok := levels[strings.ToUpper(o.Level)]
+	if !ok || o.Size > MaxSize {
+		return nil, ErrInvalidOptions
+	}
+	fg, err := parseColor(o.Foreground)
+	if err != nil {
+		return nil, err
+	}
+	bg, err := parseColor(o.Background)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := qr.Encode(content, level)
+	if err != nil {
+		return nil, err
+	}
+	m := grid{code: code}
+	if o.QuietZone {
+		m.margin = quietZoneSize
+	}
+	if o.Size < m.size() {
+		return nil, ErrInvalidOptions
+	}
+
+	switch o.Format {
+	case FormatPNG:
+		return renderPNG(m, o.Size, fg, bg)
+	case FormatSVG:
+		return renderSVG(m, o.Size, fg, bg), nil
+	}
+
+	return nil, ErrInvalidOptions
+}
+
+// grid is QR code modules surrounded by margin of light modules.
+type grid struct {
+	code   *qr.Code
+	margin int
+}
+
+// size returns the number of modules on a side.
+func (g grid) size() int {
+	return g.code.Size + 2*g.margin
+}
+
+// dark reports whether the module at (x, y) is dark.
+func (g grid) dark(x, y int) bool {
+	return g.code.Black(x-g.margin, y-g.margin)
+}
+
+// renderPNG renders the grid as PNG image centered in size x size pixels.
+func renderPNG(g grid, size int, fg, bg color.RGBA) ([]byte, error) {
+	scale := size / g.size()
+	offset := (size - scale*g.size()) / 2
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{bg, fg})
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			mx, my := x-offset, y-offset
+			if mx >= 0 && my >= 0 && g.dark(mx/scale, my/scale) {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// renderSVG renders the grid as SVG image of size x size pixels.
+func renderSVG(g grid, size int, fg, bg color.RGBA) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		size, size, g.size(), g.size())
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="%s"/>`, g.size(), g.size(), hexColor(bg))
+	fmt.Fprintf(&b, `<path fill="%s" d="`, hexColor(fg))
+	for y := 0; y < g.size(); y++ {
+		for x := 0; x < g.size(); x++ {
+			if g.dark(x, y) {
+				fmt.Fprintf(&b, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	b.WriteString(`"/></svg>`)
+
+	return b.Bytes()
+}
+
+// parseColor parses color in RRGGBB hex format with optional leading #.
+func parseColor(s string) (color.RGBA, error) {
+	rgb, err := hex.DecodeString(strings.TrimPrefix(s, "#"))
+	if err != nil || len(rgb) != 3 {
+		return color.RGBA{}, ErrInvalidOptions
+	}
+
+	return color.RGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: 0xff}, nil
+}
+
+// hexColor formats color in #RRGGBB hex format.
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
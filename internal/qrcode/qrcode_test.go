@@ -0,0 +1,122 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestRender(t *testing.T) {
+	testcases := []struct {
+		name   string
+		opts   Options
+		expErr error
+	}{
+		{
+			name: "PNG with defaults",
+		},
+		{
+			name: "SVG with custom options",
+			opts: Options{Format: FormatSVG, Size: 512, Level: "h", Foreground: "#112233", Background: "fafafa", QuietZone: true},
+		},
+		{
+			name:   "unknown format",
+			opts:   Options{Format: "gif"},
+			expErr: ErrInvalidOptions,
+		},
+		{
+			name:   "unknown level",
+			opts:   Options{Level: "X"},
+			expErr: ErrInvalidOptions,
+		},
+		{
+			name:   "invalid color",
+			opts:   Options{Foreground: "black"},
+			expErr: ErrInvalidOptions,
+		},
+		{
+			name:   "size is too small",
+			opts:   Options{Size: 10},
+			expErr: ErrInvalidOptions,
+		},
+		{
+			name:   "size is too large",
+			opts:   Options{Size: MaxSize + 1},
+			expErr: ErrInvalidOptions,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			b, err := Render("tinee.io/xxxx", tc.opts)
+
+			is.Equal(tc.expErr, err)
+			if tc.expErr == nil {
+				is.True(len(b) > 0)
+			}
+		})
+	}
+}
+
+func TestRender_PNG(t *testing.T) {
+	testcases := []struct {
+		name      string
+		opts      Options
+		expCorner color.Color
+	}{
+		{
+			name:      "code starts at the corner",
+			opts:      Options{Size: 100, Foreground: "ff0000"},
+			expCorner: color.RGBA{R: 0xff, A: 0xff},
+		},
+		{
+			name:      "quiet zone surrounds the code",
+			opts:      Options{Size: 100, Foreground: "ff0000", QuietZone: true},
+			expCorner: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			b, err := Render("tinee.io/xxxx", tc.opts)
+			is.NoErr(err)
+			img, err := png.Decode(bytes.NewReader(b))
+			is.NoErr(err)
+
+			is.Equal(100, img.Bounds().Dx())
+			is.Equal(100, img.Bounds().Dy())
+			// image is centered, so the first module starts after offset
+			offset := 100 % 21 / 2
+			if tc.opts.QuietZone {
+				offset = 100 % 29 / 2
+			}
+			is.Equal(tc.expCorner, img.At(offset, offset))
+		})
+	}
+}
+
+func TestRender_SVG(t *testing.T) {
+	is := is.New(t)
+
+	b, err := Render("tinee.io/xxxx", Options{Format: FormatSVG, Size: 128, Foreground: "112233"})
+
+	is.NoErr(err)
+	svg := string(b)
+	is.True(strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="128" height="128" viewBox="0 0 21 21"`))
+	is.True(strings.Contains(svg, `<path fill="#112233" d="M0 0h1v1h-1z`))
+}
+
+func TestOptions_ContentType(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal("image/png", Options{}.ContentType())
+	is.Equal("image/svg+xml", Options{Format: FormatSVG}.ContentType())
+}
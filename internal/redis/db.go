@@ -3,10 +3,12 @@ package redis
 
 import (
 	"context"
+	"net"
 
 	"github.com/go-redis/redis/v8"
 
 	"tinee/internal/config"
+	"tinee/internal/tls"
 )
 
 // DB represents Redis database.
@@ -17,10 +19,21 @@ type DB struct {
 
 // Open connects to the database and returns DB instance.
 func Open(ctx context.Context, cfg config.Redis) (*DB, error) {
-	client := redis.NewClient(&redis.Options{
+	opts := &redis.Options{
 		Addr:     cfg.Addr,
 		Password: cfg.Password,
-	})
+	}
+	if cfg.TLS {
+		tc, err := tls.ClientConfig(cfg.TLSCAFile, cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		// certificate of Redis is verified against its host
+		tc.ServerName, _, _ = net.SplitHostPort(cfg.Addr)
+		opts.TLSConfig = tc
+	}
+
+	client := redis.NewClient(opts)
 	if _, err := client.Ping(ctx).Result(); err != nil {
 		return nil, err
 	}
@@ -28,6 +41,11 @@ func Open(ctx context.Context, cfg config.Redis) (*DB, error) {
 	return &DB{cfg: cfg, client: client}, nil
 }
 
+// Ping checks connection to the database.
+func (db *DB) Ping(ctx context.Context) error {
+	return db.client.Ping(ctx).Err()
+}
+
 // Close closes database connection.
 func (db *DB) Close() error {
 	return db.client.Close()
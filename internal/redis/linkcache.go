@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 
+	"github.com/go-redis/redis/v8"
+
 	"tinee/internal/service"
 )
 
@@ -29,12 +31,73 @@ func (c *LinkCache) Set(ctx context.Context, alias string, l service.Link) error
 	return err
 }
 
-// Get gets service.Link by alias from Redis.
+// Get gets service.Link by alias from Redis. It returns
+// service.ErrLinkNotFound if the alias is not cached.
 func (c *LinkCache) Get(ctx context.Context, alias string) (l service.Link, err error) {
 	s, err := c.db.client.Get(ctx, alias).Result()
-	if err != nil {
+	if err == redis.Nil {
+		return service.Link{}, service.ErrLinkNotFound
+	} else if err != nil {
 		return service.Link{}, err
 	}
 
 	return l, json.Unmarshal([]byte(s), &l)
 }
+
+// SetMany saves service.Links to Redis with alias keys in a single pipeline.
+func (c *LinkCache) SetMany(ctx context.Context, links map[string]service.Link) error {
+	if len(links) == 0 {
+		return nil
+	}
+
+	pipe := c.db.client.Pipeline()
+	for alias, l := range links {
+		s, err := json.Marshal(l)
+		if err != nil {
+			return err
+		}
+		pipe.Set(ctx, alias, s, 0)
+	}
+
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+// Delete removes service.Links with alias keys from Redis.
+func (c *LinkCache) Delete(ctx context.Context, aliases []string) error {
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	return c.db.client.Del(ctx, aliases...).Err()
+}
+
+// GetMany gets service.Links by aliases from Redis with a single MGET.
+// Aliases without cached service.Link are not present in the returned map.
+func (c *LinkCache) GetMany(ctx context.Context, aliases []string) (map[string]service.Link, error) {
+	links := make(map[string]service.Link, len(aliases))
+	if len(aliases) == 0 {
+		return links, nil
+	}
+
+	values, err := c.db.client.MGet(ctx, aliases...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+
+		var l service.Link
+		if err = json.Unmarshal([]byte(s), &l); err != nil {
+			return nil, err
+		}
+		links[aliases[i]] = l
+	}
+
+	return links, nil
+}
@@ -0,0 +1,42 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+)
+
+// variantsKeyPrefix is the prefix of hash keys with variant clicks.
+// Aliases cannot contain colon, so the keys never clash with cached links.
+const variantsKeyPrefix = "variants:"
+
+// VariantCounter is the variant clicks counter.
+type VariantCounter struct {
+	db *DB
+}
+
+// NewVariantCounter creates and returns a new VariantCounter instance.
+func NewVariantCounter(db *DB) *VariantCounter {
+	return &VariantCounter{db: db}
+}
+
+// Incr increments clicks of the link variant.
+func (c *VariantCounter) Incr(ctx context.Context, linkID, variant string) error {
+	return c.db.client.HIncrBy(ctx, variantsKeyPrefix+linkID, variant, 1).Err()
+}
+
+// Counts returns clicks of all link variants by variant name.
+func (c *VariantCounter) Counts(ctx context.Context, linkID string) (map[string]int64, error) {
+	m, err := c.db.client.HGetAll(ctx, variantsKeyPrefix+linkID).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(m))
+	for variant, s := range m {
+		if counts[variant], err = strconv.ParseInt(s, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+
+	return counts, nil
+}
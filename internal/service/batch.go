@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrBatchTooLarge is returned when batch exceeds max batch size.
+var ErrBatchTooLarge = errors.New("batch is too large")
+
+// LinkErrors is returned by LinkRepo.SaveMany when some of Links were not
+// saved. It maps indexes of Links to their errors, Links without errors
+// were saved.
+type LinkErrors map[int]error
+
+// Error implements error interface.
+func (e LinkErrors) Error() string {
+	return fmt.Sprintf("%d links were not saved", len(e))
+}
+
+// ShortenItem is a URL of shortening batch.
+type ShortenItem struct {
+	URL   string
+	Alias string
+}
+
+// ShortenResult is the result of shortening a ShortenItem.
+type ShortenResult struct {
+	TineeURL string
+	Err      error
+}
+
+// ShortenBatch shortens provided URLs concurrently and saves changed links
+// at once. Links that were modified concurrently are shortened again and
+// saved one by one like Shorten does, and only items of links that were not
+// saved fail. Results are returned in the order of items.
+func (s *Service) ShortenBatch(ctx context.Context, items []ShortenItem) ([]ShortenResult, error) {
+	ctx, span := tracer.Start(ctx, "Service.ShortenBatch")
+	defer span.End()
+
+	cfg := s.config()
+	if cfg.MaxBatchSize > 0 && len(items) > cfg.MaxBatchSize {
+		return nil, ErrBatchTooLarge
+	}
+
+	// items with the same URL are shortened by a single worker in order,
+	// so that all their aliases are added to the same Link
+	var urls []string
+	groups := make(map[string][]int)
+	for i, item := range items {
+		if _, ok := groups[item.URL]; !ok {
+			urls = append(urls, item.URL)
+		}
+		groups[item.URL] = append(groups[item.URL], i)
+	}
+
+	results := make([]ShortenResult, len(items))
+	links := make([]*Link, len(urls))
+	claims := &aliasClaims{owners: make(map[string]string)}
+
+	workers := cfg.BatchWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for g := range jobs {
+				links[g] = s.shortenGroup(ctx, urls[g], groups[urls[g]], items, results, claims)
+			}
+		}()
+	}
+	for g := range urls {
+		jobs <- g
+	}
+	close(jobs)
+	wg.Wait()
+
+	var changed []Link
+	var changedGroups []int
+	for g, l := range links {
+		if l != nil {
+			changed = append(changed, *l)
+			changedGroups = append(changedGroups, g)
+		}
+	}
+	if len(changed) == 0 {
+		return results, nil
+	}
+
+	err := s.r.SaveMany(ctx, changed)
+	if err == nil {
+		return results, nil
+	}
+	errs, partial := err.(LinkErrors)
+	for j, g := range changedGroups {
+		gerr := err
+		if partial {
+			gerr = errs[j]
+		}
+
+		if gerr == ErrVersionConflict {
+			s.retryGroup(ctx, urls[g], groups[urls[g]], items, results)
+		} else if gerr != nil {
+			failGroup(groups[urls[g]], results, gerr)
+		}
+	}
+
+	return results, nil
+}
+
+// retryGroup shortens items with the same URL again and saves the Link
+// like Shorten does. It is used for groups whose Link was modified
+// concurrently while the batch was saved.
+func (s *Service) retryGroup(ctx context.Context, URL string, indexes []int, items []ShortenItem, results []ShortenResult) {
+	err := retryConflict(ctx, func() error {
+		// other groups are saved, aliases taken by them are rejected by
+		// the repository
+		claims := &aliasClaims{owners: make(map[string]string)}
+		if link := s.shortenGroup(ctx, URL, indexes, items, results, claims); link != nil {
+			return s.r.Save(ctx, *link)
+		}
+		return nil
+	})
+	if err != nil {
+		failGroup(indexes, results, err)
+	}
+}
+
+// failGroup reports err as the result of shortened items of a group whose
+// Link was not saved.
+func failGroup(indexes []int, results []ShortenResult, err error) {
+	for _, i := range indexes {
+		if results[i].Err == nil {
+			results[i] = ShortenResult{Err: err}
+		}
+	}
+}
+
+// shortenGroup shortens items with the same URL and writes their results.
+// The Link is returned if it has to be saved.
+func (s *Service) shortenGroup(
+	ctx context.Context, URL string, indexes []int, items []ShortenItem, results []ShortenResult, claims *aliasClaims,
+) *Link {
+	link, created, err := s.linkByURL(ctx, URL)
+	if err == nil && created && !claims.claim(link.Aliases[0], link.ID) {
+		err = ErrInvalidAlias
+	}
+	if err != nil {
+		for _, i := range indexes {
+			results[i] = ShortenResult{Err: err}
+		}
+		return nil
+	}
+
+	changed, shortened := created, false
+	for _, i := range indexes {
+		alias := items[i].Alias
+		if alias != "" && !claims.claim(alias, link.ID) {
+			results[i] = ShortenResult{Err: ErrInvalidAlias}
+			continue
+		}
+
+		tineeURL, added, err := s.addAlias(ctx, &link, alias)
+		results[i] = ShortenResult{TineeURL: tineeURL, Err: err}
+		changed = changed || added
+		shortened = shortened || err == nil
+	}
+
+	if !changed || !shortened {
+		return nil
+	}
+
+	return &link
+}
+
+// aliasClaims tracks links aliases are added to within a batch, so that
+// the same alias is not added to different links.
+type aliasClaims struct {
+	mu     sync.Mutex
+	owners map[string]string
+}
+
+// claim claims alias for the Link with provided ID and reports whether
+// the alias is not claimed by another Link.
+func (c *aliasClaims) claim(alias, linkID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if owner, ok := c.owners[alias]; ok {
+		return owner == linkID
+	}
+	c.owners[alias] = linkID
+
+	return true
+}
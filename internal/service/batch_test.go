@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"tinee/internal/config"
+)
+
+func TestService_ShortenBatch(t *testing.T) {
+	testcases := []struct {
+		name       string
+		cfg        config.Service
+		r          *mockLinkRepo
+		items      []ShortenItem
+		expResults []ShortenResult
+		expSaved   int
+		expErr     error
+	}{
+		{
+			name: "URLs are shortened and saved at once",
+			cfg:  config.Service{Domain: "tinee.io", BatchWorkers: 4},
+			r: &mockLinkRepo{
+				findByURL: func(ctx context.Context, URL string) (Link, error) {
+					if URL == "https://x.xx" {
+						return Link{ID: "x", URL: URL, Aliases: []string{"xxxxxxxx"}}, nil
+					}
+
+					return Link{}, ErrLinkNotFound
+				},
+				findByAlias: func(ctx context.Context, alias string) (Link, error) {
+					return Link{}, ErrLinkNotFound
+				},
+			},
+			items: []ShortenItem{
+				{URL: "https://x.xx"},
+				{URL: "https://x.xx", Alias: "aaaa"},
+				{URL: "https://y.yy", Alias: "bbbb"},
+				{URL: "https://x.xx", Alias: "cccc"},
+			},
+			expResults: []ShortenResult{
+				{TineeURL: "tinee.io/xxxxxxxx"},
+				{TineeURL: "tinee.io/aaaa"},
+				{TineeURL: "tinee.io/bbbb"},
+				{TineeURL: "tinee.io/cccc"},
+			},
+			expSaved: 2,
+		},
+		{
+			name: "unchanged links are not saved",
+			cfg:  config.Service{Domain: "tinee.io"},
+			r: &mockLinkRepo{
+				findByURL: func(ctx context.Context, URL string) (Link, error) {
+					return Link{ID: "x", URL: URL, Aliases: []string{"xxxxxxxx"}}, nil
+				},
+			},
+			items:      []ShortenItem{{URL: "https://x.xx"}},
+			expResults: []ShortenResult{{TineeURL: "tinee.io/xxxxxxxx"}},
+		},
+		{
+			name: "per item errors",
+			// single worker shortens URLs in order, so the first of them
+			// claims the alias
+			cfg: config.Service{Domain: "tinee.io", BatchWorkers: 1},
+			r: &mockLinkRepo{
+				findByURL: func(ctx context.Context, URL string) (Link, error) {
+					return Link{ID: URL, URL: URL, Aliases: []string{"xxxxxxxx"}}, nil
+				},
+				findByAlias: func(ctx context.Context, alias string) (Link, error) {
+					if alias == "taken" {
+						return Link{ID: "z"}, nil
+					}
+
+					return Link{}, ErrLinkNotFound
+				},
+			},
+			items: []ShortenItem{
+				{URL: "x.xx"},
+				{URL: "https://x.xx", Alias: "taken"},
+				{URL: "https://x.xx", Alias: "aaaa"},
+				{URL: "https://y.yy", Alias: "aaaa"},
+			},
+			expResults: []ShortenResult{
+				{Err: ErrInvalidURL},
+				{Err: ErrInvalidAlias},
+				{TineeURL: "tinee.io/aaaa"},
+				{Err: ErrInvalidAlias},
+			},
+			expSaved: 1,
+		},
+		{
+			name:   "batch is too large",
+			cfg:    config.Service{MaxBatchSize: 1},
+			items:  []ShortenItem{{URL: "https://x.xx"}, {URL: "https://y.yy"}},
+			expErr: ErrBatchTooLarge,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			var saved []Link
+			if tc.r != nil {
+				tc.r.saveMany = func(ctx context.Context, links []Link) error {
+					saved = append(saved, links...)
+					return nil
+				}
+			}
+			c := &mockLinkCache{
+				get: func(ctx context.Context, alias string) (Link, error) {
+					return Link{}, ErrLinkNotFound
+				},
+				set: func(ctx context.Context, alias string, l Link) error {
+					return nil
+				},
+			}
+			s := New(tc.cfg, tc.r, c, nil)
+
+			results, err := s.ShortenBatch(context.Background(), tc.items)
+
+			is.Equal(tc.expErr, err)
+			is.Equal(tc.expResults, results)
+			is.Equal(tc.expSaved, len(saved))
+		})
+	}
+}
+
+func TestService_ShortenBatch_SaveManyError(t *testing.T) {
+	is := is.New(t)
+	r := &mockLinkRepo{
+		findByURL: func(ctx context.Context, URL string) (Link, error) {
+			return Link{}, ErrLinkNotFound
+		},
+		findByAlias: func(ctx context.Context, alias string) (Link, error) {
+			return Link{}, ErrLinkNotFound
+		},
+		saveMany: func(ctx context.Context, links []Link) error {
+			return errors.New("unexpected error")
+		},
+	}
+	s := New(config.Service{}, r, nil, nil)
+
+	results, err := s.ShortenBatch(context.Background(), []ShortenItem{{URL: "https://x.xx"}, {URL: "x.xx"}})
+
+	is.NoErr(err)
+	is.Equal([]ShortenResult{{Err: errors.New("unexpected error")}, {Err: ErrInvalidURL}}, results)
+}
+
+func TestService_ShortenBatch_PartialSave(t *testing.T) {
+	is := is.New(t)
+	r := &mockLinkRepo{
+		findByURL: func(ctx context.Context, URL string) (Link, error) {
+			return Link{ID: URL, URL: URL, Aliases: []string{"xxxxxxxx"}, Version: 1}, nil
+		},
+		findByAlias: func(ctx context.Context, alias string) (Link, error) {
+			return Link{}, ErrLinkNotFound
+		},
+		saveMany: func(ctx context.Context, links []Link) error {
+			errs := make(LinkErrors)
+			for i, l := range links {
+				if l.URL == "https://y.yy" {
+					errs[i] = ErrInvalidAlias
+				}
+			}
+			return errs
+		},
+	}
+	c := &mockLinkCache{
+		get: func(ctx context.Context, alias string) (Link, error) {
+			return Link{}, ErrLinkNotFound
+		},
+	}
+	s := New(config.Service{Domain: "tinee.io", BatchWorkers: 1}, r, c, nil)
+
+	results, err := s.ShortenBatch(context.Background(), []ShortenItem{
+		{URL: "https://x.xx", Alias: "aaaa"},
+		{URL: "https://y.yy", Alias: "bbbb"},
+		{URL: "https://y.yy", Alias: "cccc"},
+	})
+
+	is.NoErr(err)
+	is.Equal([]ShortenResult{
+		{TineeURL: "tinee.io/aaaa"},
+		{Err: ErrInvalidAlias},
+		{Err: ErrInvalidAlias},
+	}, results) // only items of the link that was not saved fail
+}
+
+func TestService_ShortenBatch_VersionConflict(t *testing.T) {
+	is := is.New(t)
+	var found, saved int
+	r := &mockLinkRepo{
+		findByURL: func(ctx context.Context, URL string) (Link, error) {
+			found++
+			// the link got another alias concurrently
+			if found > 1 {
+				return Link{ID: "x", URL: URL, Aliases: []string{"xxxxxxxx", "zzzz"}, Version: 2}, nil
+			}
+			return Link{ID: "x", URL: URL, Aliases: []string{"xxxxxxxx"}, Version: 1}, nil
+		},
+		findByAlias: func(ctx context.Context, alias string) (Link, error) {
+			return Link{}, ErrLinkNotFound
+		},
+		saveMany: func(ctx context.Context, links []Link) error {
+			return LinkErrors{0: ErrVersionConflict}
+		},
+		save: func(ctx context.Context, l Link) error {
+			saved++
+			is.Equal([]string{"xxxxxxxx", "zzzz", "aaaa"}, l.Aliases) // concurrent alias is kept
+			return nil
+		},
+	}
+	c := &mockLinkCache{
+		get: func(ctx context.Context, alias string) (Link, error) {
+			return Link{}, ErrLinkNotFound
+		},
+	}
+	s := New(config.Service{Domain: "tinee.io"}, r, c, nil)
+
+	results, err := s.ShortenBatch(context.Background(), []ShortenItem{{URL: "https://x.xx", Alias: "aaaa"}})
+
+	is.NoErr(err)
+	is.Equal([]ShortenResult{{TineeURL: "tinee.io/aaaa"}}, results)
+	is.Equal(1, saved)
+}
+
+func TestAliasClaims_Claim(t *testing.T) {
+	is := is.New(t)
+	c := &aliasClaims{owners: make(map[string]string)}
+
+	var wg sync.WaitGroup
+	claimed := make([]bool, 10)
+	for i := range claimed {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			claimed[i] = c.claim("xxxx", string(rune('a'+i)))
+		}(i)
+	}
+	wg.Wait()
+
+	n := 0
+	for _, ok := range claimed {
+		if ok {
+			n++
+		}
+	}
+	is.Equal(1, n)
+	is.True(c.claim("xxxx", c.owners["xxxx"]))
+}
+
+func TestService_Reload(t *testing.T) {
+	is := is.New(t)
+	s := New(config.Service{Domain: "tinee.io", MaxBatchSize: 2}, nil, nil, nil)
+
+	s.Reload(config.Service{Domain: "tinee.xx", MaxBatchSize: 1})
+
+	_, err := s.ShortenBatch(context.Background(), []ShortenItem{{URL: "https://x.xx"}, {URL: "https://y.yy"}})
+	is.Equal(ErrBatchTooLarge, err)
+	is.Equal("tinee.io", s.config().Domain) // domain is not reloaded
+}
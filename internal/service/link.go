@@ -3,6 +3,10 @@ package service
 
 import (
 	"math/rand"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,9 +19,189 @@ func init() {
 
 // Link is entity that connects URL and its aliases.
 type Link struct {
-	ID      string
-	URL     string
-	Aliases []string
+	ID         string
+	URL        string
+	Aliases    []string
+	Forwarding Forwarding
+	// Params are query parameter templates set on the destination.
+	Params map[string]string
+	// Targets are rules that override URL for matching visits.
+	Targets []Target
+	// Variants are weighted destinations visits not matching
+	// any of Targets are split across.
+	Variants []Variant
+	// CreatedAt is the time the link was created at.
+	CreatedAt time.Time
+	// Version is the version of the stored Link, zero if it is not stored
+	// yet. LinkRepo saves the Link only if the stored one has the same
+	// version, and increments it.
+	Version int64
+}
+
+// Variant is a weighted destination of a Link.
+type Variant struct {
+	Name   string
+	URL    string
+	Weight int
+}
+
+// VariantClicks is a Variant with the number of visits it received.
+type VariantClicks struct {
+	Variant
+	Clicks int64
+}
+
+const (
+	// DeviceIOS is iOS device.
+	DeviceIOS = "ios"
+	// DeviceAndroid is Android device.
+	DeviceAndroid = "android"
+	// DeviceOther is any other device.
+	DeviceOther = "other"
+)
+
+// Device returns device of the User-Agent.
+func Device(userAgent string) string {
+	switch {
+	case strings.Contains(userAgent, "iPhone"),
+		strings.Contains(userAgent, "iPad"),
+		strings.Contains(userAgent, "iPod"):
+		return DeviceIOS
+	case strings.Contains(userAgent, "Android"):
+		return DeviceAndroid
+	}
+
+	return DeviceOther
+}
+
+// Target is a rule that redirects matching visits to its URL.
+// Empty criteria match any visit.
+type Target struct {
+	URL string
+	// Devices are devices the rule matches.
+	Devices []string
+	// Languages are language tags the rule matches, "en" matches "en-US".
+	Languages []string
+	// Countries are ISO 3166-1 alpha-2 country codes the rule matches.
+	Countries []string
+}
+
+// Matches reports whether the visit matches the rule.
+func (t Target) Matches(v Visit) bool {
+	return matchAny(t.Devices, v.Device, strings.EqualFold) &&
+		matchAny(t.Languages, v.Language, matchLanguage) &&
+		matchAny(t.Countries, v.Country, strings.EqualFold)
+}
+
+// matchAny reports whether s matches any of patterns, or patterns are empty.
+func matchAny(patterns []string, s string, match func(p, s string) bool) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if match(p, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchLanguage reports whether language tag matches the pattern tag
+// or its subtags.
+func matchLanguage(p, s string) bool {
+	p, s = strings.ToLower(p), strings.ToLower(s)
+
+	return p == s || strings.HasPrefix(s, p+"-")
+}
+
+// QueryPolicy defines how query parameters of a request are merged with
+// the query of the link destination.
+type QueryPolicy string
+
+const (
+	// QueryDrop ignores request query parameters.
+	QueryDrop QueryPolicy = ""
+	// QueryKeep adds request query parameters, but keeps destination
+	// values for keys present in both.
+	QueryKeep QueryPolicy = "keep"
+	// QueryOverride adds request query parameters, replacing destination
+	// values for keys present in both.
+	QueryOverride QueryPolicy = "override"
+	// QueryAppend adds request query parameters to destination values
+	// for keys present in both.
+	QueryAppend QueryPolicy = "append"
+)
+
+// Valid reports whether p is a known query policy.
+func (p QueryPolicy) Valid() bool {
+	switch p {
+	case QueryDrop, QueryKeep, QueryOverride, QueryAppend:
+		return true
+	}
+
+	return false
+}
+
+// Forwarding is the policy of forwarding request path and query
+// to the link destination.
+type Forwarding struct {
+	// Path enables forwarding of the path that follows the alias, except
+	// qr path of the QR code of the link.
+	Path bool
+	// Query is the policy of merging request query parameters.
+	Query QueryPolicy
+}
+
+const (
+	// ParamAlias is placeholder of parameter template for visited alias.
+	ParamAlias = "{alias}"
+	// ParamTimestamp is placeholder of parameter template for Unix time
+	// of the visit.
+	ParamTimestamp = "{timestamp}"
+	// ParamDate is placeholder of parameter template for date of the visit
+	// in YYYY-MM-DD format.
+	ParamDate = "{date}"
+)
+
+// placeholderRegExp is regular expression pattern for placeholders
+// of parameter templates.
+var placeholderRegExp = regexp.MustCompile(`\{[^{}]*\}`)
+
+// ValidateParams validates query parameter templates.
+func ValidateParams(params map[string]string) error {
+	for k, v := range params {
+		if k == "" {
+			return ErrInvalidParams
+		}
+		for _, p := range placeholderRegExp.FindAllString(v, -1) {
+			if p != ParamAlias && p != ParamTimestamp && p != ParamDate {
+				return ErrInvalidParams
+			}
+		}
+	}
+
+	return nil
+}
+
+// Visit is a single request for a link.
+type Visit struct {
+	// Alias is the visited alias.
+	Alias string
+	// Time is the time of the visit.
+	Time time.Time
+	// Path is the path that follows the alias.
+	Path string
+	// Query is the query of the request.
+	Query url.Values
+	// Device is the device of the visitor.
+	Device string
+	// Language is the preferred language tag of the visitor.
+	Language string
+	// Country is ISO 3166-1 alpha-2 country code of the visitor.
+	Country string
+	// Variant is the name of the variant assigned to the visitor.
+	Variant string
 }
 
 const (
@@ -35,5 +219,123 @@ func NewLink(URL string) Link {
 		alias[i] = aliasAlphabet[rand.Intn(len(aliasAlphabet))]
 	}
 
-	return Link{ID: uuid.New().String(), URL: URL, Aliases: []string{string(alias)}}
+	return Link{
+		ID:        uuid.New().String(),
+		URL:       URL,
+		Aliases:   []string{string(alias)},
+		CreatedAt: time.Now(),
+	}
+}
+
+// Destination returns URL the visit is redirected to.
+func (l Link) Destination(v Visit) (string, error) {
+	if v.Path != "" && !l.Forwarding.Path {
+		return "", ErrLinkNotFound
+	}
+
+	u, err := url.Parse(l.target(v))
+	if err != nil {
+		return "", err
+	}
+
+	if v.Path != "" {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + strings.TrimPrefix(v.Path, "/")
+		u.RawPath = ""
+	}
+
+	if l.Forwarding.Query != QueryDrop && len(v.Query) > 0 {
+		u.RawQuery = mergeQuery(u.Query(), v.Query, l.Forwarding.Query).Encode()
+	}
+
+	if len(l.Params) > 0 {
+		q := u.Query()
+		r := strings.NewReplacer(
+			ParamAlias, v.Alias,
+			ParamTimestamp, strconv.FormatInt(v.Time.Unix(), 10),
+			ParamDate, v.Time.UTC().Format("2006-01-02"),
+		)
+		for k, t := range l.Params {
+			q.Set(k, r.Replace(t))
+		}
+		u.RawQuery = q.Encode()
+	}
+
+	return u.String(), nil
+}
+
+// Targeted reports whether the visit matches any of targeting rules.
+func (l Link) Targeted(v Visit) bool {
+	for _, t := range l.Targets {
+		if t.Matches(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Variant returns the variant with provided name.
+func (l Link) Variant(name string) (Variant, bool) {
+	for _, v := range l.Variants {
+		if v.Name == name {
+			return v, true
+		}
+	}
+
+	return Variant{}, false
+}
+
+// PickVariant picks random variant according to variant weights.
+func (l Link) PickVariant() Variant {
+	total := 0
+	for _, v := range l.Variants {
+		total += v.Weight
+	}
+	if total <= 0 {
+		return Variant{}
+	}
+
+	n := rand.Intn(total)
+	for _, v := range l.Variants {
+		if n < v.Weight {
+			return v
+		}
+		n -= v.Weight
+	}
+
+	return Variant{}
+}
+
+// target returns URL of the first rule the visit matches, URL of the
+// visit variant if there is no such rule, or link URL otherwise.
+func (l Link) target(v Visit) string {
+	for _, t := range l.Targets {
+		if t.Matches(v) {
+			return t.URL
+		}
+	}
+
+	if variant, ok := l.Variant(v.Variant); ok {
+		return variant.URL
+	}
+
+	return l.URL
+}
+
+// mergeQuery merges query parameters of a request into destination ones
+// according to the policy.
+func mergeQuery(dst, src url.Values, p QueryPolicy) url.Values {
+	for k, vs := range src {
+		if _, ok := dst[k]; ok {
+			switch p {
+			case QueryKeep:
+				continue
+			case QueryOverride:
+				dst[k] = nil
+			}
+		}
+		dst[k] = append(dst[k], vs...)
+	}
+
+	return dst
 }
@@ -1,8 +1,10 @@
 package service
 
 import (
+	"net/url"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -20,3 +22,220 @@ func TestNewLink(t *testing.T) {
 		t.Errorf("invalid alias: %v", l.Aliases[0])
 	}
 }
+
+func TestLink_Destination(t *testing.T) {
+	testcases := []struct {
+		name   string
+		link   Link
+		visit  Visit
+		expURL string
+		expErr error
+	}{
+		{
+			name:   "destination is URL",
+			link:   Link{URL: "https://x.xx/a?b=c"},
+			visit:  Visit{Query: url.Values{"b": {"d"}}},
+			expURL: "https://x.xx/a?b=c",
+		},
+		{
+			name:   "path is forwarded",
+			link:   Link{URL: "https://x.xx/a/", Forwarding: Forwarding{Path: true}},
+			visit:  Visit{Path: "b/c"},
+			expURL: "https://x.xx/a/b/c",
+		},
+		{
+			name:   "path is not forwarded without opt in",
+			link:   Link{URL: "https://x.xx/a"},
+			visit:  Visit{Path: "b"},
+			expErr: ErrLinkNotFound,
+		},
+		{
+			name:   "query is merged keeping destination values",
+			link:   Link{URL: "https://x.xx?a=1", Forwarding: Forwarding{Query: QueryKeep}},
+			visit:  Visit{Query: url.Values{"a": {"2"}, "b": {"3"}}},
+			expURL: "https://x.xx?a=1&b=3",
+		},
+		{
+			name:   "query is merged overriding destination values",
+			link:   Link{URL: "https://x.xx?a=1", Forwarding: Forwarding{Query: QueryOverride}},
+			visit:  Visit{Query: url.Values{"a": {"2"}, "b": {"3"}}},
+			expURL: "https://x.xx?a=2&b=3",
+		},
+		{
+			name:   "query is merged appending values",
+			link:   Link{URL: "https://x.xx?a=1", Forwarding: Forwarding{Query: QueryAppend}},
+			visit:  Visit{Query: url.Values{"a": {"2"}}},
+			expURL: "https://x.xx?a=1&a=2",
+		},
+		{
+			name: "first matching target is chosen",
+			link: Link{
+				URL: "https://x.xx",
+				Targets: []Target{
+					{URL: "https://x.xx/ios", Devices: []string{DeviceIOS}},
+					{URL: "https://x.xx/de", Languages: []string{"de"}},
+					{URL: "https://x.xx/any"},
+				},
+			},
+			visit:  Visit{Device: DeviceOther, Language: "de-AT"},
+			expURL: "https://x.xx/de",
+		},
+		{
+			name: "visit variant is chosen",
+			link: Link{
+				URL: "https://x.xx",
+				Variants: []Variant{
+					{Name: "aaaa", URL: "https://x.xx/a", Weight: 1},
+					{Name: "bbbb", URL: "https://x.xx/b", Weight: 1},
+				},
+			},
+			visit:  Visit{Variant: "bbbb"},
+			expURL: "https://x.xx/b",
+		},
+		{
+			name: "params are expanded",
+			link: Link{
+				URL: "https://x.xx?utm_source=x",
+				Params: map[string]string{
+					"utm_source":   "newsletter",
+					"utm_campaign": "{alias}-{date}",
+					"t":            "{timestamp}",
+				},
+			},
+			visit:  Visit{Alias: "xxxx", Time: time.Unix(1600000000, 0)},
+			expURL: "https://x.xx?t=1600000000&utm_campaign=xxxx-2020-09-13&utm_source=newsletter",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			dst, err := tc.link.Destination(tc.visit)
+
+			is.Equal(tc.expErr, err)
+			is.Equal(tc.expURL, dst)
+		})
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	testcases := []struct {
+		name   string
+		params map[string]string
+		expErr error
+	}{
+		{
+			name:   "params are valid",
+			params: map[string]string{"utm_source": "x", "utm_campaign": "{alias}{timestamp}{date}"},
+		},
+		{
+			name:   "empty key",
+			params: map[string]string{"": "x"},
+			expErr: ErrInvalidParams,
+		},
+		{
+			name:   "unknown placeholder",
+			params: map[string]string{"x": "{x}"},
+			expErr: ErrInvalidParams,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			is.Equal(tc.expErr, ValidateParams(tc.params))
+		})
+	}
+}
+
+func TestDevice(t *testing.T) {
+	testcases := []struct {
+		name      string
+		userAgent string
+		expDevice string
+	}{
+		{
+			name:      "iPhone",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 15_0 like Mac OS X)",
+			expDevice: DeviceIOS,
+		},
+		{
+			name:      "Android",
+			userAgent: "Mozilla/5.0 (Linux; Android 12; Pixel 6)",
+			expDevice: DeviceAndroid,
+		},
+		{
+			name:      "desktop",
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64)",
+			expDevice: DeviceOther,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			is.Equal(tc.expDevice, Device(tc.userAgent))
+		})
+	}
+}
+
+func TestTarget_Matches(t *testing.T) {
+	testcases := []struct {
+		name     string
+		target   Target
+		visit    Visit
+		expMatch bool
+	}{
+		{
+			name:     "empty target matches any visit",
+			visit:    Visit{Device: DeviceIOS, Language: "en", Country: "US"},
+			expMatch: true,
+		},
+		{
+			name:     "all criteria match",
+			target:   Target{Devices: []string{DeviceIOS}, Languages: []string{"en"}, Countries: []string{"us"}},
+			visit:    Visit{Device: DeviceIOS, Language: "en-US", Country: "US"},
+			expMatch: true,
+		},
+		{
+			name:   "language does not match",
+			target: Target{Languages: []string{"en-GB"}},
+			visit:  Visit{Language: "en"},
+		},
+		{
+			name:   "unknown country does not match",
+			target: Target{Countries: []string{"US"}},
+			visit:  Visit{},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			is.Equal(tc.expMatch, tc.target.Matches(tc.visit))
+		})
+	}
+}
+
+func TestLink_PickVariant(t *testing.T) {
+	is := is.New(t)
+	l := Link{Variants: []Variant{
+		{Name: "aaaa", Weight: 1},
+		{Name: "bbbb", Weight: 3},
+	}}
+
+	picks := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		picks[l.PickVariant().Name]++
+	}
+
+	is.Equal(1000, picks["aaaa"]+picks["bbbb"])
+	if picks["aaaa"] == 0 || picks["aaaa"] > picks["bbbb"] {
+		t.Errorf("variants are not picked by weight: %v", picks)
+	}
+	is.Equal(Variant{}, Link{}.PickVariant())
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SortOrder is the order of listed Links by creation time.
+type SortOrder string
+
+const (
+	// SortAsc lists the oldest Links first.
+	SortAsc SortOrder = "asc"
+	// SortDesc lists the newest Links first.
+	SortDesc SortOrder = "desc"
+)
+
+const (
+	// DefaultListLimit is the number of listed Links if limit is not set.
+	DefaultListLimit = 50
+	// MaxListLimit is max number of Links listed at once.
+	MaxListLimit = 1000
+)
+
+var (
+	// ErrInvalidListQuery is returned when invalid order or limit of
+	// listing was provided.
+	ErrInvalidListQuery = errors.New("invalid list query")
+	// ErrInvalidCursor is returned when invalid cursor of listing was provided.
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// ListQuery is the query of listed Links.
+type ListQuery struct {
+	// Domain filters Links with URL host being the domain or its subdomain.
+	Domain string
+	// AliasPrefix filters Links with any alias starting with the prefix.
+	AliasPrefix string
+	// Search filters Links with URL containing the substring, ignoring case.
+	Search string
+	// Order is SortAsc if empty.
+	Order SortOrder
+	// Limit is DefaultListLimit if zero.
+	Limit int
+	// Cursor is NextCursor of the previous page, empty for the first page.
+	Cursor string
+}
+
+// LinkPage is a page of listed Links.
+type LinkPage struct {
+	Links []Link
+	// NextCursor is the cursor of the next page, empty for the last page.
+	NextCursor string
+}
+
+// LinkFilter is the filter of Links listed by LinkRepo.
+type LinkFilter struct {
+	Domain      string
+	AliasPrefix string
+	Search      string
+	// Desc lists the newest Links first.
+	Desc bool
+	// After is the position Links are listed after, nil lists from the start.
+	After *Position
+	Limit int
+}
+
+// Matches reports whether the Link matches the filter, regardless of
+// its position.
+func (f LinkFilter) Matches(l Link) bool {
+	if f.Domain != "" && !matchDomain(l.URL, f.Domain) {
+		return false
+	}
+	if f.Search != "" && !strings.Contains(strings.ToLower(l.URL), strings.ToLower(f.Search)) {
+		return false
+	}
+	if f.AliasPrefix == "" {
+		return true
+	}
+	for _, a := range l.Aliases {
+		if strings.HasPrefix(a, f.AliasPrefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchDomain reports whether URL host is the domain or its subdomain.
+func matchDomain(URL, domain string) bool {
+	u, err := url.Parse(URL)
+	if err != nil {
+		return false
+	}
+	host, domain := strings.ToLower(u.Hostname()), strings.ToLower(domain)
+
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// Position is the position of a Link in the list. Links are ordered by
+// creation time, and by ID if they were created at the same time.
+type Position struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+// PositionOf returns the position of the Link.
+func PositionOf(l Link) Position {
+	return Position{CreatedAt: l.CreatedAt, ID: l.ID}
+}
+
+// Less reports whether p goes before q in ascending order.
+func (p Position) Less(q Position) bool {
+	if !p.CreatedAt.Equal(q.CreatedAt) {
+		return p.CreatedAt.Before(q.CreatedAt)
+	}
+
+	return p.ID < q.ID
+}
+
+// ListLinks lists Links matching the query page by page.
+func (s *Service) ListLinks(ctx context.Context, q ListQuery) (LinkPage, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListLinks")
+	defer span.End()
+
+	if q.Limit == 0 {
+		q.Limit = DefaultListLimit
+	}
+	if q.Limit < 0 || q.Limit > MaxListLimit {
+		return LinkPage{}, ErrInvalidListQuery
+	}
+	if q.Order != "" && q.Order != SortAsc && q.Order != SortDesc {
+		return LinkPage{}, ErrInvalidListQuery
+	}
+
+	f := LinkFilter{
+		Domain:      q.Domain,
+		AliasPrefix: q.AliasPrefix,
+		Search:      q.Search,
+		Desc:        q.Order == SortDesc,
+		// one more Link is listed to know whether there is the next page
+		Limit: q.Limit + 1,
+	}
+	if q.Cursor != "" {
+		p, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return LinkPage{}, ErrInvalidCursor
+		}
+		f.After = &p
+	}
+
+	links, err := s.r.List(ctx, f)
+	if err != nil {
+		return LinkPage{}, err
+	}
+
+	page := LinkPage{Links: links}
+	if len(links) > q.Limit {
+		page.Links = links[:q.Limit]
+		page.NextCursor = encodeCursor(PositionOf(page.Links[q.Limit-1]))
+	}
+
+	return page, nil
+}
+
+// encodeCursor returns opaque cursor of the position.
+func encodeCursor(p Position) string {
+	b, _ := json.Marshal(p)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor returns position of the cursor.
+func decodeCursor(cursor string) (p Position, err error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return p, err
+	}
+
+	return p, json.Unmarshal(b, &p)
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"tinee/internal/config"
+)
+
+func TestLinkFilter_Matches(t *testing.T) {
+	l := Link{URL: "https://docs.Example.com/Guide?x=1", Aliases: []string{"xxxx", "docs1"}}
+	testcases := []struct {
+		name   string
+		filter LinkFilter
+		exp    bool
+	}{
+		{name: "empty filter", exp: true},
+		{name: "domain", filter: LinkFilter{Domain: "docs.example.com"}, exp: true},
+		{name: "parent domain", filter: LinkFilter{Domain: "example.com"}, exp: true},
+		{name: "other domain", filter: LinkFilter{Domain: "ample.com"}},
+		{name: "alias prefix", filter: LinkFilter{AliasPrefix: "doc"}, exp: true},
+		{name: "other alias prefix", filter: LinkFilter{AliasPrefix: "y"}},
+		{name: "search ignores case", filter: LinkFilter{Search: "guide"}, exp: true},
+		{name: "search", filter: LinkFilter{Search: "tutorial"}},
+		{
+			name:   "all criteria",
+			filter: LinkFilter{Domain: "example.com", AliasPrefix: "x", Search: "x=1"},
+			exp:    true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			is.Equal(tc.exp, tc.filter.Matches(l))
+		})
+	}
+}
+
+func TestPosition_Less(t *testing.T) {
+	is := is.New(t)
+	now := time.Now()
+
+	is.True(Position{CreatedAt: now, ID: "y"}.Less(Position{CreatedAt: now.Add(time.Second), ID: "x"}))
+	is.True(Position{CreatedAt: now, ID: "x"}.Less(Position{CreatedAt: now, ID: "y"}))
+	is.True(!Position{CreatedAt: now, ID: "x"}.Less(Position{CreatedAt: now, ID: "x"}))
+}
+
+func TestService_ListLinks(t *testing.T) {
+	created := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	links := []Link{
+		{ID: "x", URL: "https://x.xx", CreatedAt: created},
+		{ID: "y", URL: "https://y.yy", CreatedAt: created.Add(time.Second)},
+		{ID: "z", URL: "https://z.zz", CreatedAt: created.Add(2 * time.Second)},
+	}
+	// cursor of the position of link y
+	cursor := encodeCursor(PositionOf(links[1]))
+	testcases := []struct {
+		name      string
+		q         ListQuery
+		listed    []Link
+		listErr   error
+		expFilter LinkFilter
+		expPage   LinkPage
+		expErr    error
+	}{
+		{
+			name:      "first page",
+			q:         ListQuery{Domain: "x.xx", AliasPrefix: "x", Search: "x", Limit: 2},
+			listed:    links,
+			expFilter: LinkFilter{Domain: "x.xx", AliasPrefix: "x", Search: "x", Limit: 3},
+			expPage:   LinkPage{Links: links[:2], NextCursor: cursor},
+		},
+		{
+			name:      "last page",
+			q:         ListQuery{Order: SortDesc, Limit: 2, Cursor: cursor},
+			listed:    links[:1],
+			expFilter: LinkFilter{Desc: true, Limit: 3, After: &Position{CreatedAt: links[1].CreatedAt, ID: "y"}},
+			expPage:   LinkPage{Links: links[:1]},
+		},
+		{
+			name:      "default limit",
+			q:         ListQuery{Order: SortAsc},
+			expFilter: LinkFilter{Limit: DefaultListLimit + 1},
+			expPage:   LinkPage{},
+		},
+		{
+			name:   "invalid limit",
+			q:      ListQuery{Limit: MaxListLimit + 1},
+			expErr: ErrInvalidListQuery,
+		},
+		{
+			name:   "invalid order",
+			q:      ListQuery{Order: "x"},
+			expErr: ErrInvalidListQuery,
+		},
+		{
+			name:   "invalid cursor",
+			q:      ListQuery{Cursor: "x"},
+			expErr: ErrInvalidCursor,
+		},
+		{
+			name:      "unexpected error",
+			listErr:   errors.New("unexpected error"),
+			expFilter: LinkFilter{Limit: DefaultListLimit + 1},
+			expErr:    errors.New("unexpected error"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			r := &mockLinkRepo{
+				list: func(ctx context.Context, f LinkFilter) ([]Link, error) {
+					is.Equal(tc.expFilter, f)
+					return tc.listed, tc.listErr
+				},
+			}
+			s := New(config.Service{}, r, nil, nil)
+
+			page, err := s.ListLinks(context.Background(), tc.q)
+
+			is.Equal(tc.expErr, err)
+			is.Equal(tc.expPage, page)
+		})
+	}
+}
@@ -7,8 +7,14 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
 
 	"tinee/internal/config"
+	"tinee/internal/logging"
+	"tinee/internal/qrcode"
 )
 
 const (
@@ -18,8 +24,18 @@ const (
 	GeneratedAliasRegExp = "^[A-Za-z0-9]{8}$"
 	// CustomAliasRegExp is regular expression pattern for custom aliases.
 	CustomAliasRegExp = "^[A-Za-z0-9]{4,}$"
+	// ImportedAliasRegExp is regular expression pattern for aliases
+	// imported from other URL shorteners, it matches their short codes,
+	// such as sequential YOURLS keywords or Bitly codes with dashes.
+	ImportedAliasRegExp = "^[A-Za-z0-9_-]{1,64}$"
+	// VariantNameRegExp is regular expression pattern for variant names,
+	// they are stored in cookies of assigned variants.
+	VariantNameRegExp = "^[A-Za-z0-9_-]{1,32}$"
 )
 
+// ReservedAliases are paths of tinee endpoints that can't be custom aliases.
+var ReservedAliases = []string{"healthz", "readyz"}
+
 var (
 	// ErrInvalidURL is returned when invalid URL was provided.
 	ErrInvalidURL = errors.New("invalid URL")
@@ -27,70 +43,177 @@ var (
 	ErrInvalidAlias = errors.New("invalid alias")
 	// ErrLinkNotFound is returned when link was not found in store.
 	ErrLinkNotFound = errors.New("link not found")
+	// ErrInvalidQueryPolicy is returned when unknown query policy was provided.
+	ErrInvalidQueryPolicy = errors.New("invalid query policy")
+	// ErrInvalidParams is returned when invalid parameter templates were provided.
+	ErrInvalidParams = errors.New("invalid params")
+	// ErrInvalidTarget is returned when invalid targeting rule was provided.
+	ErrInvalidTarget = errors.New("invalid target")
+	// ErrInvalidVariant is returned when invalid variant was provided.
+	ErrInvalidVariant = errors.New("invalid variant")
+	// ErrVersionConflict is returned by LinkRepo when saved Link was
+	// modified or created concurrently.
+	ErrVersionConflict = errors.New("link was modified concurrently")
 )
 
+// tracer is the tracer of Service operations.
+var tracer = otel.Tracer("tinee/internal/service")
+
+// saveAttempts is max number of attempts to modify a Link that is
+// modified concurrently.
+const saveAttempts = 5
+
 // LinkRepo is link repository interface.
 type LinkRepo interface {
 	Save(context.Context, Link) error
+	SaveMany(context.Context, []Link) error
+	FindByID(context.Context, string) (Link, error)
 	FindByURL(context.Context, string) (Link, error)
 	FindByAlias(context.Context, string) (Link, error)
+	FindByAliases(context.Context, []string) ([]Link, error)
+	Each(context.Context, func(Link) error) error
+	List(context.Context, LinkFilter) ([]Link, error)
 }
 
 // LinkCache is link cache interface.
 type LinkCache interface {
 	Set(ctx context.Context, alias string, l Link) error
 	Get(ctx context.Context, alias string) (Link, error)
+	SetMany(ctx context.Context, links map[string]Link) error
+	GetMany(ctx context.Context, aliases []string) (map[string]Link, error)
+	Delete(ctx context.Context, aliases []string) error
+}
+
+// VariantCounter is variant clicks counter interface.
+type VariantCounter interface {
+	Incr(ctx context.Context, linkID, variant string) error
+	Counts(ctx context.Context, linkID string) (map[string]int64, error)
 }
 
 // Service is URL shortening service.
 type Service struct {
+	mu  sync.RWMutex
 	cfg config.Service
 	r   LinkRepo
 	c   LinkCache
+	vc  VariantCounter
 }
 
 // New creates and returns a new Service instance.
-func New(cfg config.Service, r LinkRepo, c LinkCache) *Service {
-	return &Service{cfg: cfg, r: r, c: c}
+func New(cfg config.Service, r LinkRepo, c LinkCache, vc VariantCounter) *Service {
+	return &Service{cfg: cfg, r: r, c: c, vc: vc}
 }
 
-// Shorten shortens provided URL.
+// Reload applies batch limits of cfg, other settings are not changed.
+func (s *Service) Reload(cfg config.Service) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cfg.BatchWorkers = cfg.BatchWorkers
+	s.cfg.MaxBatchSize = cfg.MaxBatchSize
+}
+
+// config returns configuration of Service.
+func (s *Service) config() config.Service {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.cfg
+}
+
+// Shorten shortens provided URL. Shortening is retried if the Link was
+// modified concurrently, so concurrently added aliases are not lost.
 func (s *Service) Shorten(ctx context.Context, URL, alias string) (tineeURL string, err error) {
-	if err = s.ValidateURL(URL); err != nil {
-		return "", err
-	}
+	ctx, span := tracer.Start(ctx, "Service.Shorten")
+	defer span.End()
 
-	link, err := s.r.FindByURL(ctx, URL)
-	if err == ErrLinkNotFound {
-		link, err = s.CreateLink(ctx, URL)
+	err = retryConflict(ctx, func() error {
+		link, created, err := s.linkByURL(ctx, URL)
 		if err != nil {
-			return "", err
+			return err
 		}
-	} else if err != nil {
+
+		var added bool
+		if tineeURL, added, err = s.addAlias(ctx, &link, alias); err != nil {
+			return err
+		}
+
+		if created || added {
+			return s.r.Save(ctx, link)
+		}
+		return nil
+	})
+	if err != nil {
 		return "", err
 	}
 
+	return tineeURL, nil
+}
+
+// retryConflict calls fn until it returns error other than
+// ErrVersionConflict or attempts are exhausted.
+func retryConflict(ctx context.Context, fn func() error) (err error) {
+	for i := 0; i < saveAttempts; i++ {
+		if err = fn(); err != ErrVersionConflict {
+			return err
+		}
+		logging.FromContext(ctx).Debug("link was modified concurrently", zap.Int("attempt", i+1))
+	}
+
+	return err
+}
+
+// linkByURL finds the Link with provided URL, or generates a new one
+// that is not saved yet.
+func (s *Service) linkByURL(ctx context.Context, URL string) (link Link, created bool, err error) {
+	if err = s.ValidateURL(URL); err != nil {
+		return Link{}, false, err
+	}
+
+	link, err = s.r.FindByURL(ctx, URL)
+	if err == ErrLinkNotFound {
+		link, err = s.newLink(ctx, URL)
+		return link, err == nil, err
+	}
+
+	return link, false, err
+}
+
+// addAlias adds custom alias to the Link and returns tineeURL with it,
+// or tineeURL with generated alias if custom alias is empty. The Link is
+// not saved, added reports whether it has to be.
+func (s *Service) addAlias(ctx context.Context, link *Link, alias string) (tineeURL string, added bool, err error) {
 	if alias == "" {
-		return s.TineeURL(link.Aliases[0]), nil
+		return s.TineeURL(link.Aliases[0]), false, nil
 	}
 
 	if err = s.ValidateCustomAlias(alias); err != nil {
-		return "", err
+		return "", false, err
+	}
+	for _, a := range link.Aliases {
+		if a == alias {
+			return s.TineeURL(alias), false, nil
+		}
 	}
+
 	l, err := s.LinkByAlias(ctx, alias)
 	if err == ErrLinkNotFound {
 		link.Aliases = append(link.Aliases, alias)
+		return s.TineeURL(alias), true, nil
 	} else if err == nil && link.ID != l.ID {
-		return "", ErrInvalidAlias
+		return "", false, ErrInvalidAlias
 	} else if err != nil {
-		return "", err
+		return "", false, err
 	}
 
-	return s.TineeURL(alias), s.r.Save(ctx, link)
+	return s.TineeURL(alias), false, nil
 }
 
 // LinkByAlias finds and returns a Link by alias.
 func (s *Service) LinkByAlias(ctx context.Context, alias string) (l Link, err error) {
+	ctx, span := tracer.Start(ctx, "Service.LinkByAlias")
+	defer span.End()
+
 	if l, err = s.c.Get(ctx, alias); err == nil {
 		return l, nil
 	}
@@ -102,8 +225,68 @@ func (s *Service) LinkByAlias(ctx context.Context, alias string) (l Link, err er
 	return l, err
 }
 
+// LinksByAliases finds and returns Links by aliases. Aliases without
+// a Link are not present in the returned map.
+func (s *Service) LinksByAliases(ctx context.Context, aliases []string) (map[string]Link, error) {
+	ctx, span := tracer.Start(ctx, "Service.LinksByAliases")
+	defer span.End()
+
+	if limit := s.config().MaxBatchSize; limit > 0 && len(aliases) > limit {
+		return nil, ErrBatchTooLarge
+	}
+
+	links, err := s.c.GetMany(ctx, aliases)
+	if err != nil {
+		links = make(map[string]Link, len(aliases))
+	}
+
+	var misses []string
+	for _, a := range aliases {
+		if _, ok := links[a]; !ok {
+			misses = append(misses, a)
+		}
+	}
+	if len(misses) == 0 {
+		return links, nil
+	}
+
+	found, err := s.r.FindByAliases(ctx, misses)
+	if err != nil {
+		return nil, err
+	}
+
+	missed := make(map[string]bool, len(misses))
+	for _, a := range misses {
+		missed[a] = true
+	}
+	cached := make(map[string]Link, len(misses))
+	for _, l := range found {
+		for _, a := range l.Aliases {
+			if missed[a] {
+				links[a], cached[a] = l, l
+			}
+		}
+	}
+	_ = s.c.SetMany(ctx, cached)
+
+	return links, nil
+}
+
 // CreateLink creates a Link with provided URL and generated alias.
 func (s *Service) CreateLink(ctx context.Context, URL string) (l Link, err error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateLink")
+	defer span.End()
+
+	if l, err = s.newLink(ctx, URL); err != nil {
+		return Link{}, err
+	}
+
+	return l, s.r.Save(ctx, l)
+}
+
+// newLink returns a new Link with provided URL and generated alias
+// that is not taken yet.
+func (s *Service) newLink(ctx context.Context, URL string) (l Link, err error) {
 	l = NewLink(URL)
 	if _, err = s.r.FindByAlias(ctx, l.Aliases[0]); err == nil {
 		return Link{}, ErrInvalidAlias
@@ -111,12 +294,137 @@ func (s *Service) CreateLink(ctx context.Context, URL string) (l Link, err error
 		return Link{}, err
 	}
 
-	return l, s.r.Save(ctx, l)
+	return l, nil
+}
+
+// SetForwarding sets path and query forwarding policy of the Link
+// with provided alias.
+func (s *Service) SetForwarding(ctx context.Context, alias string, f Forwarding) error {
+	ctx, span := tracer.Start(ctx, "Service.SetForwarding")
+	defer span.End()
+
+	if !f.Query.Valid() {
+		return ErrInvalidQueryPolicy
+	}
+
+	return s.update(ctx, alias, func(l *Link) {
+		l.Forwarding = f
+	})
+}
+
+// SetParams sets query parameter templates of the Link with provided alias.
+func (s *Service) SetParams(ctx context.Context, alias string, params map[string]string) error {
+	ctx, span := tracer.Start(ctx, "Service.SetParams")
+	defer span.End()
+
+	if err := ValidateParams(params); err != nil {
+		return err
+	}
+
+	return s.update(ctx, alias, func(l *Link) {
+		l.Params = params
+	})
+}
+
+// SetTargets sets targeting rules of the Link with provided alias.
+func (s *Service) SetTargets(ctx context.Context, alias string, targets []Target) error {
+	ctx, span := tracer.Start(ctx, "Service.SetTargets")
+	defer span.End()
+
+	if err := s.validateTargets(targets); err != nil {
+		return err
+	}
+
+	return s.update(ctx, alias, func(l *Link) {
+		l.Targets = targets
+	})
+}
+
+// SetVariants sets weighted destinations of the Link with provided alias.
+func (s *Service) SetVariants(ctx context.Context, alias string, variants []Variant) error {
+	ctx, span := tracer.Start(ctx, "Service.SetVariants")
+	defer span.End()
+
+	if err := s.validateVariants(variants); err != nil {
+		return err
+	}
+
+	return s.update(ctx, alias, func(l *Link) {
+		l.Variants = variants
+	})
+}
+
+// Variants returns variants of the Link with provided alias together with
+// the number of clicks each of them received.
+func (s *Service) Variants(ctx context.Context, alias string) ([]VariantClicks, error) {
+	ctx, span := tracer.Start(ctx, "Service.Variants")
+	defer span.End()
+
+	l, err := s.LinkByAlias(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.vc.Counts(ctx, l.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	variants := make([]VariantClicks, 0, len(l.Variants))
+	for _, v := range l.Variants {
+		variants = append(variants, VariantClicks{Variant: v, Clicks: counts[v.Name]})
+	}
+
+	return variants, nil
+}
+
+// CountVariant counts a click of the Link variant.
+func (s *Service) CountVariant(ctx context.Context, linkID, variant string) error {
+	ctx, span := tracer.Start(ctx, "Service.CountVariant")
+	defer span.End()
+
+	return s.vc.Incr(ctx, linkID, variant)
+}
+
+// update applies fn to the Link with provided alias, saves it and
+// refreshes cached copies of the Link. It is retried if the Link was
+// modified concurrently.
+func (s *Service) update(ctx context.Context, alias string, fn func(*Link)) error {
+	var l Link
+	err := retryConflict(ctx, func() (err error) {
+		if l, err = s.r.FindByAlias(ctx, alias); err != nil {
+			return err
+		}
+
+		fn(&l)
+		return s.r.Save(ctx, l)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, a := range l.Aliases {
+		_ = s.c.Set(ctx, a, l)
+	}
+
+	return nil
+}
+
+// QRCode renders QR code of tineeURL with provided alias.
+func (s *Service) QRCode(ctx context.Context, alias string, o qrcode.Options) ([]byte, error) {
+	ctx, span := tracer.Start(ctx, "Service.QRCode")
+	defer span.End()
+
+	if _, err := s.LinkByAlias(ctx, alias); err != nil {
+		return nil, err
+	}
+
+	return qrcode.Render(s.TineeURL(alias), o)
 }
 
 // TineeURL forms tineeURL with provided alias.
 func (s *Service) TineeURL(alias string) string {
-	return fmt.Sprintf("%s/%s", s.cfg.Domain, alias)
+	return fmt.Sprintf("%s/%s", s.config().Domain, alias)
 }
 
 // ValidateURL validates URL.
@@ -128,11 +436,82 @@ func (s *Service) ValidateURL(URL string) error {
 	return nil
 }
 
+// ValidateTarget validates targeting rule.
+func (s *Service) ValidateTarget(t Target) error {
+	if err := s.ValidateURL(t.URL); err != nil {
+		return ErrInvalidTarget
+	}
+	for _, d := range t.Devices {
+		if d != DeviceIOS && d != DeviceAndroid && d != DeviceOther {
+			return ErrInvalidTarget
+		}
+	}
+
+	return nil
+}
+
+// ValidateVariant validates variant.
+func (s *Service) ValidateVariant(v Variant) error {
+	if matched, err := regexp.MatchString(VariantNameRegExp, v.Name); err != nil || !matched {
+		return ErrInvalidVariant
+	}
+	if err := s.ValidateURL(v.URL); err != nil {
+		return ErrInvalidVariant
+	}
+	if v.Weight <= 0 {
+		return ErrInvalidVariant
+	}
+
+	return nil
+}
+
+// validateTargets validates targeting rules.
+func (s *Service) validateTargets(targets []Target) error {
+	for _, t := range targets {
+		if err := s.ValidateTarget(t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateVariants validates variants, their names must be unique.
+func (s *Service) validateVariants(variants []Variant) error {
+	names := make(map[string]bool, len(variants))
+	for _, v := range variants {
+		if err := s.ValidateVariant(v); err != nil {
+			return err
+		}
+		if names[v.Name] {
+			return ErrInvalidVariant
+		}
+		names[v.Name] = true
+	}
+
+	return nil
+}
+
+// ValidateImportedAlias validates alias imported from other URL shortener.
+func (s *Service) ValidateImportedAlias(alias string) error {
+	if matched, err := regexp.MatchString(ImportedAliasRegExp, alias); err != nil || !matched {
+		return ErrInvalidAlias
+	}
+	if contains(ReservedAliases, alias) {
+		return ErrInvalidAlias
+	}
+
+	return nil
+}
+
 // ValidateCustomAlias validates custom alias.
 func (s *Service) ValidateCustomAlias(alias string) error {
 	if matched, err := regexp.MatchString(CustomAliasRegExp, alias); err != nil || !matched {
 		return ErrInvalidAlias
 	}
+	if contains(ReservedAliases, alias) {
+		return ErrInvalidAlias
+	}
 
 	return nil
 }
@@ -4,23 +4,38 @@ import (
 	"context"
 	"errors"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/matryer/is"
 
 	"tinee/internal/config"
+	"tinee/internal/qrcode"
 )
 
 type mockLinkRepo struct {
-	save        func(context.Context, Link) error
-	findByURL   func(context.Context, string) (Link, error)
-	findByAlias func(context.Context, string) (Link, error)
+	save          func(context.Context, Link) error
+	findByID      func(context.Context, string) (Link, error)
+	saveMany      func(context.Context, []Link) error
+	findByURL     func(context.Context, string) (Link, error)
+	findByAlias   func(context.Context, string) (Link, error)
+	findByAliases func(context.Context, []string) ([]Link, error)
+	each          func(context.Context, func(Link) error) error
+	list          func(context.Context, LinkFilter) ([]Link, error)
 }
 
 func (r *mockLinkRepo) Save(ctx context.Context, link Link) error {
 	return r.save(ctx, link)
 }
 
+func (r *mockLinkRepo) SaveMany(ctx context.Context, links []Link) error {
+	return r.saveMany(ctx, links)
+}
+
+func (r *mockLinkRepo) FindByID(ctx context.Context, ID string) (Link, error) {
+	return r.findByID(ctx, ID)
+}
+
 func (r *mockLinkRepo) FindByURL(ctx context.Context, URL string) (Link, error) {
 	return r.findByURL(ctx, URL)
 }
@@ -29,9 +44,24 @@ func (r *mockLinkRepo) FindByAlias(ctx context.Context, alias string) (Link, err
 	return r.findByAlias(ctx, alias)
 }
 
+func (r *mockLinkRepo) FindByAliases(ctx context.Context, aliases []string) ([]Link, error) {
+	return r.findByAliases(ctx, aliases)
+}
+
+func (r *mockLinkRepo) Each(ctx context.Context, fn func(Link) error) error {
+	return r.each(ctx, fn)
+}
+
+func (r *mockLinkRepo) List(ctx context.Context, f LinkFilter) ([]Link, error) {
+	return r.list(ctx, f)
+}
+
 type mockLinkCache struct {
-	get func(context.Context, string) (Link, error)
-	set func(context.Context, string, Link) error
+	get     func(context.Context, string) (Link, error)
+	set     func(context.Context, string, Link) error
+	getMany func(context.Context, []string) (map[string]Link, error)
+	setMany func(context.Context, map[string]Link) error
+	delete  func(context.Context, []string) error
 }
 
 func (c *mockLinkCache) Get(ctx context.Context, alias string) (Link, error) {
@@ -42,13 +72,39 @@ func (c *mockLinkCache) Set(ctx context.Context, alias string, l Link) error {
 	return c.set(ctx, alias, l)
 }
 
+func (c *mockLinkCache) GetMany(ctx context.Context, aliases []string) (map[string]Link, error) {
+	return c.getMany(ctx, aliases)
+}
+
+func (c *mockLinkCache) SetMany(ctx context.Context, links map[string]Link) error {
+	return c.setMany(ctx, links)
+}
+
+func (c *mockLinkCache) Delete(ctx context.Context, aliases []string) error {
+	return c.delete(ctx, aliases)
+}
+
+type mockVariantCounter struct {
+	incr   func(context.Context, string, string) error
+	counts func(context.Context, string) (map[string]int64, error)
+}
+
+func (c *mockVariantCounter) Incr(ctx context.Context, linkID, variant string) error {
+	return c.incr(ctx, linkID, variant)
+}
+
+func (c *mockVariantCounter) Counts(ctx context.Context, linkID string) (map[string]int64, error) {
+	return c.counts(ctx, linkID)
+}
+
 func TestNewService(t *testing.T) {
 	is := is.New(t)
 	cfg := config.Service{}
 	r := &mockLinkRepo{}
 	c := &mockLinkCache{}
+	vc := &mockVariantCounter{}
 
-	is.Equal(&Service{cfg: cfg, r: r, c: c}, New(cfg, r, c))
+	is.Equal(&Service{cfg: cfg, r: r, c: c, vc: vc}, New(cfg, r, c, vc))
 }
 
 func TestService_Shorten(t *testing.T) {
@@ -109,6 +165,63 @@ func TestService_Shorten(t *testing.T) {
 			alias:  "xxxx",
 			expErr: nil,
 		},
+		{
+			name: "Shorten is retried when Link was modified concurrently",
+			r: func() *mockLinkRepo {
+				conflicts := 1
+				return &mockLinkRepo{
+					findByURL: func(ctx context.Context, url string) (Link, error) {
+						return Link{ID: "1", URL: url, Aliases: []string{"xxxxxxxx"}, Version: 2}, nil
+					},
+					findByAlias: func(ctx context.Context, alias string) (Link, error) {
+						return Link{}, ErrLinkNotFound
+					},
+					save: func(ctx context.Context, link Link) error {
+						if conflicts > 0 {
+							conflicts--
+							return ErrVersionConflict
+						}
+						return nil
+					},
+				}
+			}(),
+			c: &mockLinkCache{
+				get: func(ctx context.Context, alias string) (Link, error) {
+					return Link{}, ErrLinkNotFound
+				},
+				set: func(ctx context.Context, alias string, l Link) error {
+					return nil
+				},
+			},
+			url:    "https://x.xx",
+			alias:  "xxxx",
+			expErr: nil,
+		},
+		{
+			name: "Link is modified concurrently on every attempt",
+			r: &mockLinkRepo{
+				findByURL: func(ctx context.Context, url string) (Link, error) {
+					return Link{ID: "1", URL: url, Aliases: []string{"xxxxxxxx"}, Version: 2}, nil
+				},
+				findByAlias: func(ctx context.Context, alias string) (Link, error) {
+					return Link{}, ErrLinkNotFound
+				},
+				save: func(ctx context.Context, link Link) error {
+					return ErrVersionConflict
+				},
+			},
+			c: &mockLinkCache{
+				get: func(ctx context.Context, alias string) (Link, error) {
+					return Link{}, ErrLinkNotFound
+				},
+				set: func(ctx context.Context, alias string, l Link) error {
+					return nil
+				},
+			},
+			url:    "https://x.xx",
+			alias:  "xxxx",
+			expErr: ErrVersionConflict,
+		},
 		{
 			name:   "invalid URL",
 			url:    "x.xx",
@@ -247,7 +360,7 @@ func TestService_Shorten(t *testing.T) {
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			is := is.New(t)
-			s := New(config.Service{}, tc.r, tc.c)
+			s := New(config.Service{}, tc.r, tc.c, nil)
 
 			tineeURL, err := s.Shorten(context.Background(), tc.url, tc.alias)
 
@@ -310,7 +423,7 @@ func TestService_LinkByAlias(t *testing.T) {
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			is := is.New(t)
-			s := New(config.Service{}, tc.r, tc.c)
+			s := New(config.Service{}, tc.r, tc.c, nil)
 
 			l, err := s.LinkByAlias(context.Background(), tc.alias)
 
@@ -320,6 +433,112 @@ func TestService_LinkByAlias(t *testing.T) {
 	}
 }
 
+func TestService_LinksByAliases(t *testing.T) {
+	testcases := []struct {
+		name      string
+		cfg       config.Service
+		r         *mockLinkRepo
+		c         *mockLinkCache
+		aliases   []string
+		expLinks  map[string]Link
+		expCached map[string]Link
+		expErr    error
+	}{
+		{
+			name: "links are found in cache and repository",
+			r: &mockLinkRepo{
+				findByAliases: func(ctx context.Context, aliases []string) ([]Link, error) {
+					if len(aliases) != 2 {
+						return nil, errors.New("unexpected aliases")
+					}
+
+					return []Link{{URL: "https://y.yy", Aliases: []string{"yyyy", "zzzz"}}}, nil
+				},
+			},
+			c: &mockLinkCache{
+				getMany: func(ctx context.Context, aliases []string) (map[string]Link, error) {
+					return map[string]Link{"xxxx": {URL: "https://x.xx"}}, nil
+				},
+			},
+			aliases: []string{"xxxx", "yyyy", "wwww"},
+			expLinks: map[string]Link{
+				"xxxx": {URL: "https://x.xx"},
+				"yyyy": {URL: "https://y.yy", Aliases: []string{"yyyy", "zzzz"}},
+			},
+			expCached: map[string]Link{
+				"yyyy": {URL: "https://y.yy", Aliases: []string{"yyyy", "zzzz"}},
+			},
+		},
+		{
+			name: "links are found in cache",
+			c: &mockLinkCache{
+				getMany: func(ctx context.Context, aliases []string) (map[string]Link, error) {
+					return map[string]Link{"xxxx": {URL: "https://x.xx"}}, nil
+				},
+			},
+			aliases:  []string{"xxxx"},
+			expLinks: map[string]Link{"xxxx": {URL: "https://x.xx"}},
+		},
+		{
+			name: "links are found in repository on cache error",
+			r: &mockLinkRepo{
+				findByAliases: func(ctx context.Context, aliases []string) ([]Link, error) {
+					return []Link{{URL: "https://x.xx", Aliases: []string{"xxxx"}}}, nil
+				},
+			},
+			c: &mockLinkCache{
+				getMany: func(ctx context.Context, aliases []string) (map[string]Link, error) {
+					return nil, errors.New("unexpected error")
+				},
+			},
+			aliases:   []string{"xxxx"},
+			expLinks:  map[string]Link{"xxxx": {URL: "https://x.xx", Aliases: []string{"xxxx"}}},
+			expCached: map[string]Link{"xxxx": {URL: "https://x.xx", Aliases: []string{"xxxx"}}},
+		},
+		{
+			name: "FindByAliases unexpected error",
+			r: &mockLinkRepo{
+				findByAliases: func(ctx context.Context, aliases []string) ([]Link, error) {
+					return nil, errors.New("unexpected error")
+				},
+			},
+			c: &mockLinkCache{
+				getMany: func(ctx context.Context, aliases []string) (map[string]Link, error) {
+					return map[string]Link{}, nil
+				},
+			},
+			aliases: []string{"xxxx"},
+			expErr:  errors.New("unexpected error"),
+		},
+		{
+			name:    "batch is too large",
+			cfg:     config.Service{MaxBatchSize: 1},
+			aliases: []string{"xxxx", "yyyy"},
+			expErr:  ErrBatchTooLarge,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			var cached map[string]Link
+			if tc.c != nil {
+				tc.c.setMany = func(ctx context.Context, links map[string]Link) error {
+					cached = links
+					return nil
+				}
+			}
+			s := New(tc.cfg, tc.r, tc.c, nil)
+
+			links, err := s.LinksByAliases(context.Background(), tc.aliases)
+
+			is.Equal(tc.expErr, err)
+			is.Equal(tc.expLinks, links)
+			is.Equal(tc.expCached, cached)
+		})
+	}
+}
+
 func TestService_CreateLink(t *testing.T) {
 	testcases := []struct {
 		name   string
@@ -364,7 +583,7 @@ func TestService_CreateLink(t *testing.T) {
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			is := is.New(t)
-			s := New(config.Service{}, tc.r, nil)
+			s := New(config.Service{}, tc.r, nil, nil)
 
 			l, err := s.CreateLink(context.Background(), tc.url)
 
@@ -376,9 +595,366 @@ func TestService_CreateLink(t *testing.T) {
 	}
 }
 
+func TestService_SetForwarding(t *testing.T) {
+	testcases := []struct {
+		name       string
+		r          *mockLinkRepo
+		forwarding Forwarding
+		expErr     error
+	}{
+		{
+			name: "forwarding is set",
+			r: &mockLinkRepo{
+				findByAlias: func(ctx context.Context, alias string) (Link, error) {
+					return Link{Aliases: []string{"xxxx", "yyyy"}}, nil
+				},
+				save: func(ctx context.Context, l Link) error {
+					if !l.Forwarding.Path || l.Forwarding.Query != QueryKeep {
+						return errors.New("forwarding is not set")
+					}
+
+					return nil
+				},
+			},
+			forwarding: Forwarding{Path: true, Query: QueryKeep},
+		},
+		{
+			name:       "invalid query policy",
+			forwarding: Forwarding{Query: "x"},
+			expErr:     ErrInvalidQueryPolicy,
+		},
+		{
+			name: "link not found",
+			r: &mockLinkRepo{
+				findByAlias: func(ctx context.Context, alias string) (Link, error) {
+					return Link{}, ErrLinkNotFound
+				},
+			},
+			expErr: ErrLinkNotFound,
+		},
+		{
+			name: "Save unexpected error",
+			r: &mockLinkRepo{
+				findByAlias: func(ctx context.Context, alias string) (Link, error) {
+					return Link{}, nil
+				},
+				save: func(ctx context.Context, l Link) error {
+					return errors.New("unexpected error")
+				},
+			},
+			expErr: errors.New("unexpected error"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			var cached []string
+			c := &mockLinkCache{
+				set: func(ctx context.Context, alias string, l Link) error {
+					cached = append(cached, alias)
+					return nil
+				},
+			}
+			s := New(config.Service{}, tc.r, c, nil)
+
+			err := s.SetForwarding(context.Background(), "xxxx", tc.forwarding)
+
+			is.Equal(tc.expErr, err)
+			if tc.expErr == nil {
+				is.Equal([]string{"xxxx", "yyyy"}, cached)
+			}
+		})
+	}
+}
+
+func TestService_SetParams(t *testing.T) {
+	testcases := []struct {
+		name   string
+		r      *mockLinkRepo
+		params map[string]string
+		expErr error
+	}{
+		{
+			name: "params are set",
+			r: &mockLinkRepo{
+				findByAlias: func(ctx context.Context, alias string) (Link, error) {
+					return Link{Aliases: []string{"xxxx"}}, nil
+				},
+				save: func(ctx context.Context, l Link) error {
+					if l.Params["utm_campaign"] != "{alias}" {
+						return errors.New("params are not set")
+					}
+
+					return nil
+				},
+			},
+			params: map[string]string{"utm_campaign": "{alias}"},
+		},
+		{
+			name:   "invalid params",
+			params: map[string]string{"utm_campaign": "{x}"},
+			expErr: ErrInvalidParams,
+		},
+		{
+			name: "link not found",
+			r: &mockLinkRepo{
+				findByAlias: func(ctx context.Context, alias string) (Link, error) {
+					return Link{}, ErrLinkNotFound
+				},
+			},
+			expErr: ErrLinkNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			c := &mockLinkCache{
+				set: func(ctx context.Context, alias string, l Link) error {
+					return nil
+				},
+			}
+			s := New(config.Service{}, tc.r, c, nil)
+
+			is.Equal(tc.expErr, s.SetParams(context.Background(), "xxxx", tc.params))
+		})
+	}
+}
+
+func TestService_SetTargets(t *testing.T) {
+	testcases := []struct {
+		name    string
+		r       *mockLinkRepo
+		targets []Target
+		expErr  error
+	}{
+		{
+			name: "targets are set",
+			r: &mockLinkRepo{
+				findByAlias: func(ctx context.Context, alias string) (Link, error) {
+					return Link{Aliases: []string{"xxxx"}}, nil
+				},
+				save: func(ctx context.Context, l Link) error {
+					if len(l.Targets) != 1 {
+						return errors.New("targets are not set")
+					}
+
+					return nil
+				},
+			},
+			targets: []Target{{URL: "https://x.xx", Devices: []string{DeviceIOS}}},
+		},
+		{
+			name:    "invalid target",
+			targets: []Target{{URL: "x.xx"}},
+			expErr:  ErrInvalidTarget,
+		},
+		{
+			name: "link not found",
+			r: &mockLinkRepo{
+				findByAlias: func(ctx context.Context, alias string) (Link, error) {
+					return Link{}, ErrLinkNotFound
+				},
+			},
+			expErr: ErrLinkNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			c := &mockLinkCache{
+				set: func(ctx context.Context, alias string, l Link) error {
+					return nil
+				},
+			}
+			s := New(config.Service{}, tc.r, c, nil)
+
+			is.Equal(tc.expErr, s.SetTargets(context.Background(), "xxxx", tc.targets))
+		})
+	}
+}
+
+func TestService_SetVariants(t *testing.T) {
+	testcases := []struct {
+		name     string
+		r        *mockLinkRepo
+		variants []Variant
+		expErr   error
+	}{
+		{
+			name: "variants are set",
+			r: &mockLinkRepo{
+				findByAlias: func(ctx context.Context, alias string) (Link, error) {
+					return Link{Aliases: []string{"xxxx"}}, nil
+				},
+				save: func(ctx context.Context, l Link) error {
+					if len(l.Variants) != 2 {
+						return errors.New("variants are not set")
+					}
+
+					return nil
+				},
+			},
+			variants: []Variant{
+				{Name: "aaaa", URL: "https://x.xx/a", Weight: 1},
+				{Name: "bbbb", URL: "https://x.xx/b", Weight: 3},
+			},
+		},
+		{
+			name:     "invalid variant",
+			variants: []Variant{{Name: "aaaa", URL: "https://x.xx/a"}},
+			expErr:   ErrInvalidVariant,
+		},
+		{
+			name: "duplicate variant name",
+			variants: []Variant{
+				{Name: "aaaa", URL: "https://x.xx/a", Weight: 1},
+				{Name: "aaaa", URL: "https://x.xx/b", Weight: 1},
+			},
+			expErr: ErrInvalidVariant,
+		},
+		{
+			name: "link not found",
+			r: &mockLinkRepo{
+				findByAlias: func(ctx context.Context, alias string) (Link, error) {
+					return Link{}, ErrLinkNotFound
+				},
+			},
+			expErr: ErrLinkNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			c := &mockLinkCache{
+				set: func(ctx context.Context, alias string, l Link) error {
+					return nil
+				},
+			}
+			s := New(config.Service{}, tc.r, c, nil)
+
+			is.Equal(tc.expErr, s.SetVariants(context.Background(), "xxxx", tc.variants))
+		})
+	}
+}
+
+func TestService_Variants(t *testing.T) {
+	testcases := []struct {
+		name        string
+		c           *mockLinkCache
+		vc          *mockVariantCounter
+		expVariants []VariantClicks
+		expErr      error
+	}{
+		{
+			name: "variants are returned with clicks",
+			c: &mockLinkCache{
+				get: func(ctx context.Context, alias string) (Link, error) {
+					return Link{ID: "x-x-x-x", Variants: []Variant{
+						{Name: "aaaa", URL: "https://x.xx/a", Weight: 1},
+						{Name: "bbbb", URL: "https://x.xx/b", Weight: 1},
+					}}, nil
+				},
+			},
+			vc: &mockVariantCounter{
+				counts: func(ctx context.Context, linkID string) (map[string]int64, error) {
+					return map[string]int64{"aaaa": 3}, nil
+				},
+			},
+			expVariants: []VariantClicks{
+				{Variant: Variant{Name: "aaaa", URL: "https://x.xx/a", Weight: 1}, Clicks: 3},
+				{Variant: Variant{Name: "bbbb", URL: "https://x.xx/b", Weight: 1}},
+			},
+		},
+		{
+			name: "Counts unexpected error",
+			c: &mockLinkCache{
+				get: func(ctx context.Context, alias string) (Link, error) {
+					return Link{}, nil
+				},
+			},
+			vc: &mockVariantCounter{
+				counts: func(ctx context.Context, linkID string) (map[string]int64, error) {
+					return nil, errors.New("unexpected error")
+				},
+			},
+			expErr: errors.New("unexpected error"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			s := New(config.Service{}, nil, tc.c, tc.vc)
+
+			variants, err := s.Variants(context.Background(), "xxxx")
+
+			is.Equal(tc.expErr, err)
+			is.Equal(tc.expVariants, variants)
+		})
+	}
+}
+
+func TestService_QRCode(t *testing.T) {
+	testcases := []struct {
+		name   string
+		c      *mockLinkCache
+		opts   qrcode.Options
+		expErr error
+	}{
+		{
+			name: "QR code is rendered",
+			c: &mockLinkCache{
+				get: func(ctx context.Context, alias string) (Link, error) {
+					return Link{}, nil
+				},
+			},
+		},
+		{
+			name: "invalid options",
+			c: &mockLinkCache{
+				get: func(ctx context.Context, alias string) (Link, error) {
+					return Link{}, nil
+				},
+			},
+			opts:   qrcode.Options{Format: "x"},
+			expErr: qrcode.ErrInvalidOptions,
+		},
+		{
+			name: "link not found",
+			c: &mockLinkCache{
+				get: func(ctx context.Context, alias string) (Link, error) {
+					return Link{}, ErrLinkNotFound
+				},
+			},
+			expErr: ErrLinkNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			r := &mockLinkRepo{
+				findByAlias: func(ctx context.Context, alias string) (Link, error) {
+					return Link{}, ErrLinkNotFound
+				},
+			}
+			s := New(config.Service{Domain: "tinee.io"}, r, tc.c, nil)
+
+			b, err := s.QRCode(context.Background(), "xxxx", tc.opts)
+
+			is.Equal(tc.expErr, err)
+			is.Equal(tc.expErr == nil, len(b) > 0)
+		})
+	}
+}
+
 func TestService_TineeURL(t *testing.T) {
 	is := is.New(t)
-	s := New(config.Service{Domain: "tinee.io"}, nil, nil)
+	s := New(config.Service{Domain: "tinee.io"}, nil, nil, nil)
 
 	is.Equal("tinee.io/xxxx", s.TineeURL("xxxx"))
 }
@@ -419,13 +995,100 @@ func TestService_ValidateURL(t *testing.T) {
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			is := is.New(t)
-			s := New(config.Service{}, nil, nil)
+			s := New(config.Service{}, nil, nil, nil)
 
 			is.Equal(tc.expErr, s.ValidateURL(tc.url))
 		})
 	}
 }
 
+func TestService_ValidateTarget(t *testing.T) {
+	testcases := []struct {
+		name   string
+		target Target
+		expErr error
+	}{
+		{
+			name:   "target is valid",
+			target: Target{URL: "https://x.xx", Devices: []string{DeviceAndroid}},
+		},
+		{
+			name:   "invalid URL",
+			target: Target{URL: "x.xx"},
+			expErr: ErrInvalidTarget,
+		},
+		{
+			name:   "unknown device",
+			target: Target{URL: "https://x.xx", Devices: []string{"x"}},
+			expErr: ErrInvalidTarget,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			s := New(config.Service{}, nil, nil, nil)
+
+			is.Equal(tc.expErr, s.ValidateTarget(tc.target))
+		})
+	}
+}
+
+func TestService_ValidateVariant(t *testing.T) {
+	testcases := []struct {
+		name    string
+		variant Variant
+		expErr  error
+	}{
+		{
+			name:    "variant is valid",
+			variant: Variant{Name: "aaaa", URL: "https://x.xx", Weight: 1},
+		},
+		{
+			name:    "short name",
+			variant: Variant{Name: "A", URL: "https://x.xx", Weight: 1},
+		},
+		{
+			name:    "name with separators",
+			variant: Variant{Name: "control_v-2", URL: "https://x.xx", Weight: 1},
+		},
+		{
+			name:    "invalid name",
+			variant: Variant{Name: "$", URL: "https://x.xx", Weight: 1},
+			expErr:  ErrInvalidVariant,
+		},
+		{
+			name:    "empty name",
+			variant: Variant{URL: "https://x.xx", Weight: 1},
+			expErr:  ErrInvalidVariant,
+		},
+		{
+			name:    "too long name",
+			variant: Variant{Name: strings.Repeat("a", 33), URL: "https://x.xx", Weight: 1},
+			expErr:  ErrInvalidVariant,
+		},
+		{
+			name:    "invalid URL",
+			variant: Variant{Name: "aaaa", URL: "x.xx", Weight: 1},
+			expErr:  ErrInvalidVariant,
+		},
+		{
+			name:    "non-positive weight",
+			variant: Variant{Name: "aaaa", URL: "https://x.xx"},
+			expErr:  ErrInvalidVariant,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			s := New(config.Service{}, nil, nil, nil)
+
+			is.Equal(tc.expErr, s.ValidateVariant(tc.variant))
+		})
+	}
+}
+
 func TestService_ValidateCustomAlias(t *testing.T) {
 	testcases := []struct {
 		name   string
@@ -447,14 +1110,43 @@ func TestService_ValidateCustomAlias(t *testing.T) {
 			alias:  "$xxx",
 			expErr: ErrInvalidAlias,
 		},
+		{
+			name:   "alias is reserved",
+			alias:  "readyz",
+			expErr: ErrInvalidAlias,
+		},
 	}
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			is := is.New(t)
-			s := New(config.Service{}, nil, nil)
+			s := New(config.Service{}, nil, nil, nil)
 
 			is.Equal(tc.expErr, s.ValidateCustomAlias(tc.alias))
 		})
 	}
 }
+
+func TestService_ValidateImportedAlias(t *testing.T) {
+	testcases := []struct {
+		name   string
+		alias  string
+		expErr error
+	}{
+		{name: "YOURLS keyword", alias: "1"},
+		{name: "code with dash and underscore", alias: "a-b_c"},
+		{name: "empty alias", alias: "", expErr: ErrInvalidAlias},
+		{name: "alias with slash", alias: "a/b", expErr: ErrInvalidAlias},
+		{name: "alias is too long", alias: strings.Repeat("x", 65), expErr: ErrInvalidAlias},
+		{name: "alias is reserved", alias: "healthz", expErr: ErrInvalidAlias},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			s := New(config.Service{}, nil, nil, nil)
+
+			is.Equal(tc.expErr, s.ValidateImportedAlias(tc.alias))
+		})
+	}
+}
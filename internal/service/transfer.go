@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConflictPolicy defines how imported links conflicting with existing
+// ones are handled.
+type ConflictPolicy string
+
+const (
+	// ConflictSkip skips conflicting links.
+	ConflictSkip ConflictPolicy = "skip"
+	// ConflictOverwrite replaces existing link with the same ID or URL
+	// by imported one. Links without ID are always merged into existing
+	// link with the same URL.
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	// ConflictFail stops import at the first conflicting link.
+	ConflictFail ConflictPolicy = "fail"
+)
+
+var (
+	// ErrInvalidConflictPolicy is returned when unknown conflict policy
+	// was provided.
+	ErrInvalidConflictPolicy = errors.New("invalid conflict policy")
+	// ErrInvalidRecord is returned when imported record is malformed.
+	ErrInvalidRecord = errors.New("invalid record")
+	// ErrConflict is returned when imported link conflicts with existing one.
+	ErrConflict = errors.New("link conflicts with existing one")
+)
+
+// ImportError is an error of importing a single record.
+type ImportError struct {
+	// Row is 1-based number of the record.
+	Row int
+	Err error
+}
+
+// Error implements error interface.
+func (e ImportError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e ImportError) Unwrap() error {
+	return e.Err
+}
+
+// ImportReport is the summary of import.
+type ImportReport struct {
+	Imported int
+	Skipped  int
+	Failed   int
+	Errors   []ImportError
+	// Conflicts are skipped records conflicting with existing links.
+	Conflicts []ImportError
+}
+
+// ImportOptions are options of import.
+type ImportOptions struct {
+	Conflict ConflictPolicy
+	// DryRun enables validation and detection of conflicts without
+	// saving imported links.
+	DryRun bool
+	// Foreign marks links as exported by other URL shortener, their
+	// aliases are validated less strictly than custom aliases to accept
+	// short codes of the shortener.
+	Foreign bool
+}
+
+// Export calls fn for every Link until fn returns error.
+func (s *Service) Export(ctx context.Context, fn func(Link) error) error {
+	ctx, span := tracer.Start(ctx, "Service.Export")
+	defer span.End()
+
+	return s.r.Each(ctx, fn)
+}
+
+// Import imports Links returned by next until it returns io.EOF.
+// Invalid records are reported and skipped, conflicts with existing links
+// are handled according to the conflict policy. Import stops with
+// ImportError wrapping ErrConflict at the first conflict if the policy is
+// ConflictFail, records imported before it are kept.
+//
+// Links without ID are treated as links of other URL shorteners: their
+// aliases are added to the existing Link with the same URL whatever the
+// policy is, so that aliases of the Link keep working. Aliases are
+// validated as custom aliases unless the links are foreign.
+func (s *Service) Import(ctx context.Context, next func() (Link, error), o ImportOptions) (ImportReport, error) {
+	ctx, span := tracer.Start(ctx, "Service.Import")
+	defer span.End()
+
+	var report ImportReport
+	p := o.Conflict
+	if p != ConflictSkip && p != ConflictOverwrite && p != ConflictFail {
+		return report, ErrInvalidConflictPolicy
+	}
+
+	r := s.r
+	if o.DryRun {
+		r = newDryRunRepo(s.r)
+	}
+
+	for row := 1; ; row++ {
+		l, err := next()
+		if err == io.EOF {
+			return report, nil
+		} else if err != nil && !errors.Is(err, ErrInvalidRecord) {
+			return report, err
+		}
+		var dropped []string
+		if err == nil {
+			l, dropped, err = s.importLink(ctx, r, l, o)
+		}
+		if err == nil && !o.DryRun {
+			// dropped aliases of replaced link must not redirect to it
+			if len(dropped) > 0 {
+				_ = s.c.Delete(ctx, dropped)
+			}
+			for _, a := range l.Aliases {
+				_ = s.c.Set(ctx, a, l)
+			}
+		}
+
+		switch {
+		case err == nil:
+			report.Imported++
+		case errors.Is(err, ErrConflict) && p == ConflictSkip:
+			report.Skipped++
+			report.Conflicts = append(report.Conflicts, ImportError{Row: row, Err: err})
+		case errors.Is(err, ErrConflict) && p == ConflictFail:
+			report.Failed++
+			return report, ImportError{Row: row, Err: err}
+		case errors.Is(err, ErrConflict), err == ErrInvalidURL, err == ErrInvalidAlias, err == ErrVersionConflict,
+			err == ErrInvalidQueryPolicy, err == ErrInvalidParams, err == ErrInvalidTarget, err == ErrInvalidVariant,
+			errors.Is(err, ErrInvalidRecord):
+			report.Failed++
+			report.Errors = append(report.Errors, ImportError{Row: row, Err: err})
+		default:
+			return report, ImportError{Row: row, Err: err}
+		}
+	}
+}
+
+// importLink validates and saves imported Link to r and returns saved Link
+// together with aliases of the replaced Link that the saved one does not
+// have. Error wrapping ErrConflict is returned if the Link conflicts with
+// existing one and cannot be imported with the policy of options.
+func (s *Service) importLink(ctx context.Context, r LinkRepo, l Link, o ImportOptions) (Link, []string, error) {
+	p := o.Conflict
+	if err := s.ValidateURL(l.URL); err != nil {
+		return Link{}, nil, err
+	}
+	if len(l.Aliases) == 0 {
+		return Link{}, nil, ErrInvalidAlias
+	}
+	validateAlias := s.ValidateCustomAlias
+	if o.Foreign {
+		validateAlias = s.ValidateImportedAlias
+	}
+	for _, a := range l.Aliases {
+		if err := validateAlias(a); err != nil {
+			return Link{}, nil, err
+		}
+	}
+	// settings are validated like they are by Set methods
+	if !l.Forwarding.Query.Valid() {
+		return Link{}, nil, ErrInvalidQueryPolicy
+	}
+	if err := ValidateParams(l.Params); err != nil {
+		return Link{}, nil, err
+	}
+	if err := s.validateTargets(l.Targets); err != nil {
+		return Link{}, nil, err
+	}
+	if err := s.validateVariants(l.Variants); err != nil {
+		return Link{}, nil, err
+	}
+	if l.CreatedAt.IsZero() {
+		l.CreatedAt = time.Now()
+	}
+
+	var replaced Link
+	existing, err := r.FindByURL(ctx, l.URL)
+	switch {
+	case err == nil && l.ID == "":
+		aliases := l.Aliases
+		l = existing
+		l.Aliases = append([]string(nil), existing.Aliases...)
+		for _, a := range aliases {
+			if !contains(l.Aliases, a) {
+				l.Aliases = append(l.Aliases, a)
+			}
+		}
+		if len(l.Aliases) == len(existing.Aliases) {
+			return Link{}, nil, fmt.Errorf("%w: link of URL %s already exists", ErrConflict, l.URL)
+		}
+	case err == nil:
+		if p != ConflictOverwrite || (existing.ID != l.ID && !idFree(ctx, r, l.ID)) {
+			return Link{}, nil, fmt.Errorf("%w: link of URL %s already exists", ErrConflict, l.URL)
+		}
+		// imported link replaces the existing one with the same URL
+		l.ID, l.Version = existing.ID, existing.Version
+		replaced = existing
+	case err != ErrLinkNotFound:
+		return Link{}, nil, err
+	case l.ID == "":
+		l.ID = uuid.New().String()
+	case p != ConflictOverwrite:
+		if !idFree(ctx, r, l.ID) {
+			return Link{}, nil, fmt.Errorf("%w: link with ID %s already exists", ErrConflict, l.ID)
+		}
+	default:
+		// imported link replaces the stored one with the same ID
+		stored, err := r.FindByID(ctx, l.ID)
+		if err != nil && err != ErrLinkNotFound {
+			return Link{}, nil, err
+		}
+		l.Version, replaced = stored.Version, stored
+	}
+
+	owners, err := r.FindByAliases(ctx, l.Aliases)
+	if err != nil {
+		return Link{}, nil, err
+	}
+	for _, o := range owners {
+		if o.ID == l.ID {
+			continue
+		}
+		for _, a := range o.Aliases {
+			if contains(l.Aliases, a) {
+				return Link{}, nil, fmt.Errorf("%w: alias %s is taken", ErrConflict, a)
+			}
+		}
+	}
+
+	if err := r.Save(ctx, l); err != nil {
+		return Link{}, nil, err
+	}
+
+	var dropped []string
+	for _, a := range replaced.Aliases {
+		if !contains(l.Aliases, a) {
+			dropped = append(dropped, a)
+		}
+	}
+
+	return l, dropped, nil
+}
+
+// idFree reports whether there is no Link with provided ID in r.
+func idFree(ctx context.Context, r LinkRepo, ID string) bool {
+	_, err := r.FindByID(ctx, ID)
+
+	return err == ErrLinkNotFound
+}
+
+// contains reports whether ss contains s.
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dryRunRepo is LinkRepo that keeps saved Links in memory on top of
+// the underlying LinkRepo, so conflicts between records of a dry run
+// are detected without writing them.
+type dryRunRepo struct {
+	LinkRepo
+	links   map[string]Link
+	urls    map[string]string
+	aliases map[string]string
+}
+
+// newDryRunRepo creates and returns a new dryRunRepo instance.
+func newDryRunRepo(r LinkRepo) *dryRunRepo {
+	return &dryRunRepo{
+		LinkRepo: r,
+		links:    make(map[string]Link),
+		urls:     make(map[string]string),
+		aliases:  make(map[string]string),
+	}
+}
+
+// Save keeps the Link in memory.
+func (r *dryRunRepo) Save(ctx context.Context, l Link) error {
+	r.links[l.ID] = l
+	r.urls[l.URL] = l.ID
+	for _, a := range l.Aliases {
+		r.aliases[a] = l.ID
+	}
+
+	return nil
+}
+
+// FindByID finds Link by ID in memory and the underlying LinkRepo.
+func (r *dryRunRepo) FindByID(ctx context.Context, ID string) (Link, error) {
+	if l, ok := r.links[ID]; ok {
+		return l, nil
+	}
+
+	return r.LinkRepo.FindByID(ctx, ID)
+}
+
+// FindByURL finds Link by URL in memory and the underlying LinkRepo.
+func (r *dryRunRepo) FindByURL(ctx context.Context, URL string) (Link, error) {
+	if ID, ok := r.urls[URL]; ok {
+		return r.links[ID], nil
+	}
+
+	return r.LinkRepo.FindByURL(ctx, URL)
+}
+
+// FindByAliases finds Links by aliases in memory and the underlying
+// LinkRepo.
+func (r *dryRunRepo) FindByAliases(ctx context.Context, aliases []string) ([]Link, error) {
+	found := make(map[string]Link)
+	var rest []string
+	for _, a := range aliases {
+		if ID, ok := r.aliases[a]; ok {
+			found[ID] = r.links[ID]
+		} else {
+			rest = append(rest, a)
+		}
+	}
+
+	if len(rest) > 0 {
+		links, err := r.LinkRepo.FindByAliases(ctx, rest)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range links {
+			if _, ok := r.links[l.ID]; !ok {
+				found[l.ID] = l
+			}
+		}
+	}
+
+	links := make([]Link, 0, len(found))
+	for _, l := range found {
+		links = append(links, l)
+	}
+
+	return links, nil
+}
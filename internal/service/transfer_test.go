@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"tinee/internal/config"
+)
+
+// records returns iterator over links and errors.
+func records(items ...interface{}) func() (Link, error) {
+	return func() (Link, error) {
+		if len(items) == 0 {
+			return Link{}, io.EOF
+		}
+		item := items[0]
+		items = items[1:]
+
+		if err, ok := item.(error); ok {
+			return Link{}, err
+		}
+
+		return item.(Link), nil
+	}
+}
+
+func TestService_Export(t *testing.T) {
+	is := is.New(t)
+	r := &mockLinkRepo{
+		each: func(ctx context.Context, fn func(Link) error) error {
+			for _, l := range []Link{{ID: "x"}, {ID: "y"}} {
+				if err := fn(l); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+	s := New(config.Service{}, r, nil, nil)
+
+	var exported []string
+	err := s.Export(context.Background(), func(l Link) error {
+		exported = append(exported, l.ID)
+		return nil
+	})
+
+	is.NoErr(err)
+	is.Equal([]string{"x", "y"}, exported)
+}
+
+func TestService_Import(t *testing.T) {
+	created := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	// existing link is https://x.xx with ID x and alias xxxx
+	existing := Link{ID: "x", URL: "https://x.xx", Aliases: []string{"xxxx"}, CreatedAt: created}
+	testcases := []struct {
+		name      string
+		next      func() (Link, error)
+		options   ImportOptions
+		expReport ImportReport
+		expSaved  []Link
+		// expDeleted are aliases removed from cache
+		expDeleted []string
+		expErr     error
+	}{
+		{
+			name: "links are imported",
+			next: records(
+				Link{ID: "y", URL: "https://y.yy", Aliases: []string{"yyyy"}},
+				Link{URL: "https://z.zz", Aliases: []string{"zzzz"}, CreatedAt: created},
+			),
+			options:   ImportOptions{Conflict: ConflictSkip},
+			expReport: ImportReport{Imported: 2},
+		},
+		{
+			name: "invalid records are reported",
+			next: records(
+				Link{ID: "y", URL: "y.yy", Aliases: []string{"yyyy"}},
+				fmt.Errorf("%w: x", ErrInvalidRecord),
+				Link{ID: "z", URL: "https://z.zz", Aliases: []string{"$"}},
+				Link{ID: "w", URL: "https://w.ww"},
+			),
+			options: ImportOptions{Conflict: ConflictSkip},
+			expReport: ImportReport{Failed: 4, Errors: []ImportError{
+				{Row: 1, Err: ErrInvalidURL},
+				{Row: 2, Err: fmt.Errorf("%w: x", ErrInvalidRecord)},
+				{Row: 3, Err: ErrInvalidAlias},
+				{Row: 4, Err: ErrInvalidAlias},
+			}},
+		},
+		{
+			name: "invalid settings are reported",
+			next: records(
+				Link{ID: "y", URL: "https://y.yy", Aliases: []string{"yyyy"}, Forwarding: Forwarding{Query: "x"}},
+				Link{ID: "z", URL: "https://z.zz", Aliases: []string{"zzzz"}, Params: map[string]string{"x": "{x}"}},
+				Link{ID: "w", URL: "https://w.ww", Aliases: []string{"wwww"}, Targets: []Target{{URL: "w.ww"}}},
+				Link{ID: "v", URL: "https://v.vv", Aliases: []string{"vvvv"}, Variants: []Variant{{Name: "a", URL: "https://v.vv", Weight: 0}}},
+				Link{ID: "u", URL: "https://u.uu", Aliases: []string{"uuuu"}, Variants: []Variant{
+					{Name: "a", URL: "https://u.uu/a", Weight: 1},
+					{Name: "a", URL: "https://u.uu/b", Weight: 1},
+				}},
+			),
+			options: ImportOptions{Conflict: ConflictSkip},
+			expReport: ImportReport{Failed: 5, Errors: []ImportError{
+				{Row: 1, Err: ErrInvalidQueryPolicy},
+				{Row: 2, Err: ErrInvalidParams},
+				{Row: 3, Err: ErrInvalidTarget},
+				{Row: 4, Err: ErrInvalidVariant},
+				{Row: 5, Err: ErrInvalidVariant},
+			}},
+			expSaved: []Link{},
+		},
+		{
+			name: "conflicts are skipped",
+			next: records(
+				Link{ID: "x", URL: "https://y.yy", Aliases: []string{"yyyy"}},
+				Link{ID: "y", URL: "https://x.xx", Aliases: []string{"yyyy"}},
+				Link{ID: "y", URL: "https://y.yy", Aliases: []string{"xxxx"}},
+			),
+			options: ImportOptions{Conflict: ConflictSkip},
+			expReport: ImportReport{Skipped: 3, Conflicts: []ImportError{
+				{Row: 1, Err: fmt.Errorf("%w: link with ID x already exists", ErrConflict)},
+				{Row: 2, Err: fmt.Errorf("%w: link of URL https://x.xx already exists", ErrConflict)},
+				{Row: 3, Err: fmt.Errorf("%w: alias xxxx is taken", ErrConflict)},
+			}},
+		},
+		{
+			name: "conflicts are overwritten",
+			next: records(
+				Link{ID: "x", URL: "https://x.xx", Aliases: []string{"xxxx", "aaaa"}, CreatedAt: created},
+				Link{ID: "y", URL: "https://x.xx", Aliases: []string{"bbbb"}, CreatedAt: created},
+				Link{ID: "y", URL: "https://y.yy", Aliases: []string{"xxxx"}},
+			),
+			options: ImportOptions{Conflict: ConflictOverwrite},
+			expReport: ImportReport{Imported: 2, Failed: 1, Errors: []ImportError{
+				{Row: 3, Err: fmt.Errorf("%w: alias xxxx is taken", ErrConflict)},
+			}},
+			expSaved: []Link{
+				{ID: "x", URL: "https://x.xx", Aliases: []string{"xxxx", "aaaa"}, CreatedAt: created},
+				{ID: "x", URL: "https://x.xx", Aliases: []string{"bbbb"}, CreatedAt: created},
+			},
+			expDeleted: []string{"xxxx"},
+		},
+		{
+			name: "short codes of other URL shorteners are imported",
+			next: records(
+				Link{URL: "https://y.yy", Aliases: []string{"1"}},
+				Link{URL: "https://z.zz", Aliases: []string{"a-b_c"}},
+				Link{URL: "https://w.ww", Aliases: []string{"healthz"}},
+			),
+			options: ImportOptions{Conflict: ConflictSkip, Foreign: true},
+			expReport: ImportReport{Imported: 2, Failed: 1, Errors: []ImportError{
+				{Row: 3, Err: ErrInvalidAlias},
+			}},
+		},
+		{
+			name: "aliases of tinee exports are validated as custom aliases",
+			next: records(
+				Link{ID: "y", URL: "https://y.yy", Aliases: []string{"a"}},
+				Link{ID: "z", URL: "https://z.zz", Aliases: []string{"x_y"}},
+			),
+			options: ImportOptions{Conflict: ConflictSkip},
+			expReport: ImportReport{Failed: 2, Errors: []ImportError{
+				{Row: 1, Err: ErrInvalidAlias},
+				{Row: 2, Err: ErrInvalidAlias},
+			}},
+		},
+		{
+			name: "links without ID are merged with overwrite policy",
+			next: records(
+				Link{URL: "https://x.xx", Aliases: []string{"aaaa"}},
+			),
+			options:   ImportOptions{Conflict: ConflictOverwrite},
+			expReport: ImportReport{Imported: 1},
+			expSaved: []Link{
+				{ID: "x", URL: "https://x.xx", Aliases: []string{"xxxx", "aaaa"}, CreatedAt: created},
+			},
+		},
+		{
+			name: "aliases of links without ID are added to existing link",
+			next: records(
+				Link{URL: "https://x.xx", Aliases: []string{"aaaa"}, CreatedAt: created.Add(time.Hour)},
+				Link{URL: "https://x.xx", Aliases: []string{"xxxx"}},
+			),
+			options: ImportOptions{Conflict: ConflictSkip},
+			expReport: ImportReport{Imported: 1, Skipped: 1, Conflicts: []ImportError{
+				{Row: 2, Err: fmt.Errorf("%w: link of URL https://x.xx already exists", ErrConflict)},
+			}},
+			expSaved: []Link{
+				{ID: "x", URL: "https://x.xx", Aliases: []string{"xxxx", "aaaa"}, CreatedAt: created},
+			},
+		},
+		{
+			name: "import fails at conflict",
+			next: records(
+				Link{ID: "y", URL: "https://y.yy", Aliases: []string{"yyyy"}},
+				Link{ID: "z", URL: "https://x.xx", Aliases: []string{"zzzz"}},
+				Link{ID: "w", URL: "https://w.ww", Aliases: []string{"wwww"}},
+			),
+			options:   ImportOptions{Conflict: ConflictFail},
+			expReport: ImportReport{Imported: 1, Failed: 1},
+			expErr: ImportError{
+				Row: 2,
+				Err: fmt.Errorf("%w: link of URL https://x.xx already exists", ErrConflict),
+			},
+		},
+		{
+			name: "dry run detects conflicts without saving",
+			next: records(
+				Link{URL: "https://y.yy", Aliases: []string{"yyyy"}},
+				Link{URL: "https://z.zz", Aliases: []string{"yyyy"}},
+				Link{URL: "https://y.yy", Aliases: []string{"aaaa"}},
+				Link{URL: "https://y.yy", Aliases: []string{"aaaa"}},
+				Link{URL: "https://w.ww", Aliases: []string{"xxxx"}},
+			),
+			options: ImportOptions{Conflict: ConflictSkip, DryRun: true},
+			expReport: ImportReport{Imported: 2, Skipped: 3, Conflicts: []ImportError{
+				{Row: 2, Err: fmt.Errorf("%w: alias yyyy is taken", ErrConflict)},
+				{Row: 4, Err: fmt.Errorf("%w: link of URL https://y.yy already exists", ErrConflict)},
+				{Row: 5, Err: fmt.Errorf("%w: alias xxxx is taken", ErrConflict)},
+			}},
+			expSaved: []Link{},
+		},
+		{
+			name:    "unexpected error",
+			next:    records(errors.New("unexpected error")),
+			options: ImportOptions{Conflict: ConflictFail},
+			expErr:  errors.New("unexpected error"),
+		},
+		{
+			name:    "invalid conflict policy",
+			options: ImportOptions{Conflict: "x"},
+			expErr:  ErrInvalidConflictPolicy,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			saved := []Link{}
+			r := &mockLinkRepo{
+				findByURL: func(ctx context.Context, URL string) (Link, error) {
+					if URL == existing.URL {
+						return existing, nil
+					}
+
+					return Link{}, ErrLinkNotFound
+				},
+				findByID: func(ctx context.Context, ID string) (Link, error) {
+					if ID == existing.ID {
+						return existing, nil
+					}
+
+					return Link{}, ErrLinkNotFound
+				},
+				findByAliases: func(ctx context.Context, aliases []string) ([]Link, error) {
+					for _, a := range aliases {
+						if a == existing.Aliases[0] {
+							return []Link{existing}, nil
+						}
+					}
+
+					return nil, nil
+				},
+				save: func(ctx context.Context, l Link) error {
+					if l.ID == "" {
+						return errors.New("ID is not generated")
+					}
+					if l.CreatedAt.IsZero() {
+						return errors.New("creation time is not set")
+					}
+					saved = append(saved, l)
+					return nil
+				},
+			}
+			var deleted []string
+			c := &mockLinkCache{
+				set: func(ctx context.Context, alias string, l Link) error {
+					if tc.options.DryRun {
+						t.Error("cache is updated in dry run")
+					}
+
+					return nil
+				},
+				delete: func(ctx context.Context, aliases []string) error {
+					deleted = append(deleted, aliases...)
+					return nil
+				},
+			}
+			s := New(config.Service{}, r, c, nil)
+
+			report, err := s.Import(context.Background(), tc.next, tc.options)
+
+			is.Equal(tc.expErr, err)
+			is.Equal(tc.expReport, report)
+			if tc.expSaved != nil {
+				is.Equal(tc.expSaved, saved)
+			}
+			is.Equal(tc.expDeleted, deleted)
+		})
+	}
+}
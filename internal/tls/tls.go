@@ -0,0 +1,160 @@
+// Package tls provides TLS configuration of servers and clients with
+// certificates reloaded from disk when they change.
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ReloadInterval is min interval of checks of certificate files for changes.
+var ReloadInterval = 10 * time.Second
+
+// ErrNoCertificates is returned when CA file does not contain any PEM
+// encoded certificate.
+var ErrNoCertificates = errors.New("no certificates found")
+
+// Certificate is certificate and key pair loaded from files. Files are
+// checked for changes at most every ReloadInterval when the certificate is
+// used, and the pair is reloaded if they changed.
+type Certificate struct {
+	certFile, keyFile string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+	checked time.Time
+}
+
+// LoadCertificate loads certificate and key pair from PEM encoded files.
+func LoadCertificate(certFile, keyFile string) (*Certificate, error) {
+	c := &Certificate{certFile: certFile, keyFile: keyFile, checked: time.Now()}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// GetCertificate returns the certificate, it is used by servers.
+func (c *Certificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return c.get(), nil
+}
+
+// GetClientCertificate returns the certificate, it is used by clients.
+func (c *Certificate) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return c.get(), nil
+}
+
+// get returns the certificate, it is reloaded if files changed. If reload
+// fails, e.g. because only one of files was written yet, the previous
+// certificate is returned.
+func (c *Certificate) get() *tls.Certificate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now := time.Now(); now.Sub(c.checked) >= ReloadInterval {
+		c.checked = now
+		if err := c.load(); err != nil {
+			zap.L().Error(err.Error())
+		}
+	}
+
+	return c.cert
+}
+
+// load loads certificate and key pair if files changed since last load.
+func (c *Certificate) load() error {
+	var modTime time.Time
+	for _, file := range []string{c.certFile, c.keyFile} {
+		fi, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("loading certificate: %w", err)
+		}
+		if fi.ModTime().After(modTime) {
+			modTime = fi.ModTime()
+		}
+	}
+	if c.cert != nil && modTime.Equal(c.modTime) {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading certificate %s: %w", c.certFile, err)
+	}
+	if c.cert != nil {
+		zap.S().Infof("reloaded certificate %s", c.certFile)
+	}
+	c.cert, c.modTime = &cert, modTime
+
+	return nil
+}
+
+// ServerConfig returns TLS configuration of server with certificate and
+// key files. If clientCAFile is not empty, clients are required to present
+// certificate signed by its CAs.
+func ServerConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	cert, err := LoadCertificate(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: cert.GetCertificate,
+	}
+	if clientCAFile != "" {
+		if cfg.ClientCAs, err = loadCertPool(clientCAFile); err != nil {
+			return nil, err
+		}
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return cfg, nil
+}
+
+// ClientConfig returns TLS configuration of client trusting CAs of caFile,
+// or system CAs if it is empty. Client presents certificate of certFile and
+// keyFile if they are not empty.
+func ClientConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	var err error
+	if caFile != "" {
+		if cfg.RootCAs, err = loadCertPool(caFile); err != nil {
+			return nil, err
+		}
+	}
+	if certFile != "" {
+		cert, err := LoadCertificate(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.GetClientCertificate = cert.GetClientCertificate
+	}
+
+	return cfg, nil
+}
+
+// loadCertPool loads pool of PEM encoded certificates of CAs from file.
+func loadCertPool(file string) (*x509.CertPool, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("loading CA certificates: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("loading CA certificates %s: %w", file, ErrNoCertificates)
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,148 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"tinee/internal/tls/tlstest"
+)
+
+// handshake performs handshake of server and client over loopback
+// connection, it returns error of the server.
+func handshake(t *testing.T, server, client *tls.Config) error {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	go func() {
+		cc, err := net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			return
+		}
+		defer cc.Close()
+		c := tls.Client(cc, client)
+		if c.Handshake() == nil {
+			// alert of rejected certificate is read after handshake
+			_, _ = c.Read(make([]byte, 1))
+		}
+	}()
+
+	sc, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sc.Close()
+
+	return tls.Server(sc, server).Handshake()
+}
+
+func TestServerConfig(t *testing.T) {
+	ca := tlstest.NewAuthority(t)
+	caFile := ca.WriteCA(t)
+	certFile, keyFile := ca.Write(t, "tinee", 2)
+	clientCertFile, clientKeyFile := ca.Write(t, "client", 3)
+	other := tlstest.NewAuthority(t)
+	otherCertFile, otherKeyFile := other.Write(t, "other", 4)
+
+	testcases := []struct {
+		name           string
+		clientCAFile   string
+		clientCertFile string
+		clientKeyFile  string
+		expOK          bool
+	}{
+		{
+			name:  "TLS",
+			expOK: true,
+		},
+		{
+			name:           "mTLS with client certificate",
+			clientCAFile:   caFile,
+			clientCertFile: clientCertFile,
+			clientKeyFile:  clientKeyFile,
+			expOK:          true,
+		},
+		{
+			name:         "mTLS without client certificate",
+			clientCAFile: caFile,
+			expOK:        false,
+		},
+		{
+			name:           "mTLS with client certificate of unknown CA",
+			clientCAFile:   caFile,
+			clientCertFile: otherCertFile,
+			clientKeyFile:  otherKeyFile,
+			expOK:          false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			server, err := ServerConfig(certFile, keyFile, tc.clientCAFile)
+			is.NoErr(err)
+			client, err := ClientConfig(caFile, tc.clientCertFile, tc.clientKeyFile)
+			is.NoErr(err)
+			client.ServerName = "tinee"
+
+			err = handshake(t, server, client)
+
+			is.Equal(tc.expOK, err == nil)
+		})
+	}
+}
+
+func TestClientConfig_NoCertificates(t *testing.T) {
+	is := is.New(t)
+	file := filepath.Join(t.TempDir(), "ca.crt")
+	is.NoErr(os.WriteFile(file, []byte("xxxx"), 0o600))
+
+	_, err := ClientConfig(file, "", "")
+
+	is.True(errors.Is(err, ErrNoCertificates))
+}
+
+func TestCertificate_Reload(t *testing.T) {
+	is := is.New(t)
+	defer func(interval time.Duration) { ReloadInterval = interval }(ReloadInterval)
+	ReloadInterval = 0
+	ca := tlstest.NewAuthority(t)
+	certFile, keyFile := ca.Write(t, "tinee", 2)
+	c, err := LoadCertificate(certFile, keyFile)
+	is.NoErr(err)
+
+	serial := func() int64 {
+		cert, err := c.GetCertificate(nil)
+		is.NoErr(err)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		is.NoErr(err)
+		return leaf.SerialNumber.Int64()
+	}
+	touch := func(file string) {
+		future := time.Now().Add(time.Minute)
+		is.NoErr(os.Chtimes(file, future, future))
+	}
+	is.Equal(int64(2), serial())
+
+	// certificate is renewed
+	ca.Write(t, "tinee", 3)
+	touch(certFile)
+	is.Equal(int64(3), serial())
+
+	// only certificate of the next pair is written yet
+	cert, _ := ca.Issue(t, "tinee", 4)
+	tlstest.WritePEM(t, certFile, "CERTIFICATE", cert.Raw)
+	touch(certFile)
+	is.Equal(int64(3), serial()) // previous certificate is kept
+}
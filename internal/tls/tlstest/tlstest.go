@@ -0,0 +1,111 @@
+// Package tlstest provides certificates of a test CA for tests of TLS
+// servers and clients.
+package tlstest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Authority is CA issuing certificates for tests. Files are written to
+// temporary directory of the test.
+type Authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	dir  string
+}
+
+// NewAuthority creates and returns a new Authority with self-signed
+// certificate.
+func NewAuthority(t testing.TB) *Authority {
+	t.Helper()
+	ca := &Authority{dir: t.TempDir()}
+	ca.cert, ca.key = ca.issue(t, "ca", 1, true)
+
+	return ca
+}
+
+// Issue issues certificate of server and client with name and serial.
+func (ca *Authority) Issue(t testing.TB, name string, serial int64) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	return ca.issue(t, name, serial, false)
+}
+
+// issue issues certificate with serial, it is self-signed if ca.cert is nil.
+func (ca *Authority) issue(t testing.TB, name string, serial int64, isCA bool) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	parent, signer := tmpl, key
+	if ca.cert != nil {
+		parent, signer = ca.cert, ca.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert, key
+}
+
+// Write issues certificate and writes it and its key to files, and returns
+// their paths.
+func (ca *Authority) Write(t testing.TB, name string, serial int64) (certFile, keyFile string) {
+	t.Helper()
+	cert, key := ca.Issue(t, name, serial)
+	b, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile = filepath.Join(ca.dir, name+".crt"), filepath.Join(ca.dir, name+".key")
+	WritePEM(t, certFile, "CERTIFICATE", cert.Raw)
+	WritePEM(t, keyFile, "EC PRIVATE KEY", b)
+
+	return certFile, keyFile
+}
+
+// WriteCA writes certificate of CA to file and returns its path.
+func (ca *Authority) WriteCA(t testing.TB) string {
+	t.Helper()
+	file := filepath.Join(ca.dir, "ca.crt")
+	WritePEM(t, file, "CERTIFICATE", ca.cert.Raw)
+
+	return file
+}
+
+// WritePEM writes PEM block of type typ with bytes b to file.
+func WritePEM(t testing.TB, file, typ string, b []byte) {
+	t.Helper()
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: b}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// serverName is the name of HTTP server in span attributes.
+const serverName = "tinee"
+
+// HTTP is middleware tracing HTTP requests of chi router. Spans continue
+// traces of W3C trace context of the requests and are named by route
+// pattern, so all aliases are reported as one route.
+func HTTP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		// router fills route context provided to it, so its pattern is
+		// known after the request is served
+		rctx := chi.RouteContext(ctx)
+		if rctx == nil {
+			rctx = chi.NewRouteContext()
+			ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+		}
+
+		ctx, span := otel.Tracer(instrumentationName).Start(ctx, "HTTP "+r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest(serverName, "", r)...),
+		)
+		defer span.End()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		if route := rctx.RoutePattern(); route != "" {
+			span.SetName(r.Method + " " + route)
+			span.SetAttributes(semconv.HTTPRouteKey.String(route))
+		}
+		code := ww.Status()
+		if code == 0 {
+			code = http.StatusOK
+		}
+		span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(code)...)
+		span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(code, trace.SpanKindServer))
+	})
+}
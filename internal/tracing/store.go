@@ -0,0 +1,194 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"tinee/internal/service"
+)
+
+const (
+	// DBMongoDB is database system of MongoDB operations.
+	DBMongoDB = "mongodb"
+	// DBRedis is database system of Redis operations.
+	DBRedis = "redis"
+)
+
+// start starts client span of the operation of the database system.
+func start(ctx context.Context, system, op string) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, system+" "+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemKey.String(system), semconv.DBOperationKey.String(op)),
+	)
+}
+
+// end ends the span recording the error. Links that are not found are not
+// errors of the database.
+func end(span trace.Span, err error) {
+	if err != nil && err != service.ErrLinkNotFound {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// LinkRepo is service.LinkRepo tracing operations of the underlying one.
+type LinkRepo struct {
+	system string
+	r      service.LinkRepo
+}
+
+// NewLinkRepo creates and returns a new LinkRepo instance tracing
+// operations of r as operations of the database system.
+func NewLinkRepo(system string, r service.LinkRepo) *LinkRepo {
+	return &LinkRepo{system: system, r: r}
+}
+
+// Save implements service.LinkRepo interface.
+func (r *LinkRepo) Save(ctx context.Context, l service.Link) (err error) {
+	ctx, span := start(ctx, r.system, "save")
+	defer func() { end(span, err) }()
+
+	return r.r.Save(ctx, l)
+}
+
+// SaveMany implements service.LinkRepo interface.
+func (r *LinkRepo) SaveMany(ctx context.Context, links []service.Link) (err error) {
+	ctx, span := start(ctx, r.system, "save_many")
+	defer func() { end(span, err) }()
+
+	return r.r.SaveMany(ctx, links)
+}
+
+// FindByID implements service.LinkRepo interface.
+func (r *LinkRepo) FindByID(ctx context.Context, ID string) (l service.Link, err error) {
+	ctx, span := start(ctx, r.system, "find_by_id")
+	defer func() { end(span, err) }()
+
+	return r.r.FindByID(ctx, ID)
+}
+
+// FindByURL implements service.LinkRepo interface.
+func (r *LinkRepo) FindByURL(ctx context.Context, URL string) (l service.Link, err error) {
+	ctx, span := start(ctx, r.system, "find_by_url")
+	defer func() { end(span, err) }()
+
+	return r.r.FindByURL(ctx, URL)
+}
+
+// FindByAlias implements service.LinkRepo interface.
+func (r *LinkRepo) FindByAlias(ctx context.Context, alias string) (l service.Link, err error) {
+	ctx, span := start(ctx, r.system, "find_by_alias")
+	defer func() { end(span, err) }()
+
+	return r.r.FindByAlias(ctx, alias)
+}
+
+// FindByAliases implements service.LinkRepo interface.
+func (r *LinkRepo) FindByAliases(ctx context.Context, aliases []string) (links []service.Link, err error) {
+	ctx, span := start(ctx, r.system, "find_by_aliases")
+	defer func() { end(span, err) }()
+
+	return r.r.FindByAliases(ctx, aliases)
+}
+
+// Each implements service.LinkRepo interface.
+func (r *LinkRepo) Each(ctx context.Context, fn func(service.Link) error) (err error) {
+	ctx, span := start(ctx, r.system, "each")
+	defer func() { end(span, err) }()
+
+	return r.r.Each(ctx, fn)
+}
+
+// List implements service.LinkRepo interface.
+func (r *LinkRepo) List(ctx context.Context, f service.LinkFilter) (links []service.Link, err error) {
+	ctx, span := start(ctx, r.system, "list")
+	defer func() { end(span, err) }()
+
+	return r.r.List(ctx, f)
+}
+
+// LinkCache is service.LinkCache tracing operations of the underlying one.
+type LinkCache struct {
+	system string
+	c      service.LinkCache
+}
+
+// NewLinkCache creates and returns a new LinkCache instance tracing
+// operations of c as operations of the database system.
+func NewLinkCache(system string, c service.LinkCache) *LinkCache {
+	return &LinkCache{system: system, c: c}
+}
+
+// Set implements service.LinkCache interface.
+func (c *LinkCache) Set(ctx context.Context, alias string, l service.Link) (err error) {
+	ctx, span := start(ctx, c.system, "set")
+	defer func() { end(span, err) }()
+
+	return c.c.Set(ctx, alias, l)
+}
+
+// Get implements service.LinkCache interface.
+func (c *LinkCache) Get(ctx context.Context, alias string) (l service.Link, err error) {
+	ctx, span := start(ctx, c.system, "get")
+	defer func() { end(span, err) }()
+
+	return c.c.Get(ctx, alias)
+}
+
+// SetMany implements service.LinkCache interface.
+func (c *LinkCache) SetMany(ctx context.Context, links map[string]service.Link) (err error) {
+	ctx, span := start(ctx, c.system, "set_many")
+	defer func() { end(span, err) }()
+
+	return c.c.SetMany(ctx, links)
+}
+
+// GetMany implements service.LinkCache interface.
+func (c *LinkCache) GetMany(ctx context.Context, aliases []string) (links map[string]service.Link, err error) {
+	ctx, span := start(ctx, c.system, "get_many")
+	defer func() { end(span, err) }()
+
+	return c.c.GetMany(ctx, aliases)
+}
+
+// Delete implements service.LinkCache interface.
+func (c *LinkCache) Delete(ctx context.Context, aliases []string) (err error) {
+	ctx, span := start(ctx, c.system, "delete")
+	defer func() { end(span, err) }()
+
+	return c.c.Delete(ctx, aliases)
+}
+
+// VariantCounter is service.VariantCounter tracing operations of the
+// underlying one.
+type VariantCounter struct {
+	system string
+	vc     service.VariantCounter
+}
+
+// NewVariantCounter creates and returns a new VariantCounter instance
+// tracing operations of vc as operations of the database system.
+func NewVariantCounter(system string, vc service.VariantCounter) *VariantCounter {
+	return &VariantCounter{system: system, vc: vc}
+}
+
+// Incr implements service.VariantCounter interface.
+func (c *VariantCounter) Incr(ctx context.Context, linkID, variant string) (err error) {
+	ctx, span := start(ctx, c.system, "incr")
+	defer func() { end(span, err) }()
+
+	return c.vc.Incr(ctx, linkID, variant)
+}
+
+// Counts implements service.VariantCounter interface.
+func (c *VariantCounter) Counts(ctx context.Context, linkID string) (counts map[string]int64, err error) {
+	ctx, span := start(ctx, c.system, "counts")
+	defer func() { end(span, err) }()
+
+	return c.vc.Counts(ctx, linkID)
+}
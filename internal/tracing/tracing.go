@@ -0,0 +1,87 @@
+// Package tracing provides OpenTelemetry tracing of tinee.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+
+	"tinee/internal/config"
+)
+
+const (
+	// ExporterOTLP exports spans to OTLP gRPC collector.
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes spans to stdout.
+	ExporterStdout = "stdout"
+)
+
+// instrumentationName is the name of tracer of the package.
+const instrumentationName = "tinee/internal/tracing"
+
+// ErrUnknownExporter is returned when unknown exporter was configured.
+var ErrUnknownExporter = errors.New("unknown tracing exporter")
+
+// Provider is the provider of tracers exporting spans.
+type Provider struct {
+	tp *sdktrace.TracerProvider
+}
+
+// Open creates the configured exporter and sets global tracer provider
+// exporting to it. W3C trace context propagator is set globally even if
+// spans are not exported.
+func Open(ctx context.Context, cfg config.Tracing) (*Provider, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case "":
+		return &Provider{}, nil
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return nil, ErrUnknownExporter
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(cfg.ServiceName),
+		)),
+	)
+	otel.SetTracerProvider(tp)
+
+	return &Provider{tp: tp}, nil
+}
+
+// Close exports remaining spans and shuts the Provider down.
+func (p *Provider) Close(ctx context.Context) error {
+	if p.tp == nil {
+		return nil
+	}
+
+	return p.tp.Shutdown(ctx)
+}
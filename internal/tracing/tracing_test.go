@@ -0,0 +1,117 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/matryer/is"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"tinee/internal/config"
+	"tinee/internal/memory"
+	"tinee/internal/service"
+)
+
+// record sets global tracer provider recording spans for the test.
+func record(t *testing.T) *tracetest.SpanRecorder {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+	})
+
+	return sr
+}
+
+func TestOpen(t *testing.T) {
+	testcases := []struct {
+		name     string
+		exporter string
+		expErr   error
+	}{
+		{
+			name:     "spans are not exported",
+			exporter: "",
+			expErr:   nil,
+		},
+		{
+			name:     "spans are written to stdout",
+			exporter: ExporterStdout,
+			expErr:   nil,
+		},
+		{
+			name:     "unknown exporter",
+			exporter: "zipkin",
+			expErr:   ErrUnknownExporter,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			ctx := context.Background()
+
+			p, err := Open(ctx, config.Tracing{Exporter: tc.exporter, SampleRatio: 1, ServiceName: "tinee"})
+
+			is.Equal(tc.expErr, err)
+			if err == nil {
+				is.NoErr(p.Close(ctx))
+			}
+			otel.SetTracerProvider(trace.NewNoopTracerProvider())
+		})
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	is := is.New(t)
+	sr := record(t)
+	_, err := Open(context.Background(), config.Tracing{})
+	is.NoErr(err)
+
+	var handled trace.SpanContext
+	r := chi.NewRouter()
+	r.Get("/{alias}", func(w http.ResponseWriter, r *http.Request) {
+		handled = trace.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusFound)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/xxxx", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	HTTP(r).ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := sr.Ended()
+	is.Equal(1, len(spans))
+	is.Equal("GET /{alias}", spans[0].Name())
+	is.Equal("4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	is.Equal("00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+	is.Equal(spans[0].SpanContext().SpanID(), handled.SpanID()) // handler continues the span
+}
+
+func TestLinkRepo(t *testing.T) {
+	is := is.New(t)
+	sr := record(t)
+	ctx := context.Background()
+	r := NewLinkRepo(DBMongoDB, memory.NewLinkRepo())
+
+	l := service.NewLink("https://x.xx")
+	is.NoErr(r.Save(ctx, l))
+	is.Equal(service.ErrVersionConflict, r.Save(ctx, l))
+	_, err := r.FindByAlias(ctx, "none")
+	is.Equal(service.ErrLinkNotFound, err)
+
+	spans := sr.Ended()
+	is.Equal(3, len(spans))
+	is.Equal("mongodb save", spans[0].Name())
+	is.Equal(codes.Unset, spans[0].Status().Code)
+	is.Equal(codes.Error, spans[1].Status().Code)
+	is.Equal("mongodb find_by_alias", spans[2].Name())
+	is.Equal(codes.Unset, spans[2].Status().Code) // not found link is not an error
+}
@@ -0,0 +1,257 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"tinee/internal/service"
+)
+
+const (
+	// FormatBitly is CSV export of Bitly links with long_url, link and
+	// optional created_at columns.
+	FormatBitly = "bitly"
+	// FormatYOURLS is CSV export of YOURLS yourls_url table with url,
+	// keyword and optional timestamp columns.
+	FormatYOURLS = "yourls"
+	// FormatKutt is JSON response of Kutt links API, or JSON array of
+	// its links.
+	FormatKutt = "kutt"
+)
+
+// Foreign reports whether format is export format of other URL shortener.
+func Foreign(format string) bool {
+	return format == FormatBitly || format == FormatYOURLS || format == FormatKutt
+}
+
+// timeLayouts are layouts of creation times in exports of other URL
+// shorteners.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// parseTime parses creation time of exported link, times without
+// time zone are in UTC.
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+// shortCode returns short code of a short link, such as abc
+// of https://bit.ly/abc, or s itself if it is not a link.
+func shortCode(s string) string {
+	s = strings.TrimSuffix(strings.TrimSpace(s), "/")
+
+	return s[strings.LastIndex(s, "/")+1:]
+}
+
+// importedLink returns service.Link of a link exported by other
+// URL shortener. The Link has no ID, so it is merged into the existing
+// Link with the same URL on import.
+func importedLink(URL, code, created string) (service.Link, error) {
+	l := service.Link{URL: strings.TrimSpace(URL), Aliases: []string{shortCode(code)}}
+	if created = strings.TrimSpace(created); created != "" {
+		t, err := parseTime(created)
+		if err != nil {
+			return service.Link{}, invalidRecord(err)
+		}
+		l.CreatedAt = t
+	}
+
+	return l, nil
+}
+
+// columns are names of CSV columns of an export, the first of names
+// found in the header is used.
+type columns struct {
+	URL     []string
+	Code    []string
+	Created []string
+}
+
+var (
+	// bitlyColumns are columns of FormatBitly.
+	bitlyColumns = columns{
+		URL:     []string{"long_url", "long url"},
+		Code:    []string{"link", "bitlink", "id"},
+		Created: []string{"created_at", "created at", "created"},
+	}
+	// yourlsColumns are columns of FormatYOURLS.
+	yourlsColumns = columns{
+		URL:     []string{"url"},
+		Code:    []string{"keyword"},
+		Created: []string{"timestamp"},
+	}
+)
+
+// columnDecoder is Decoder of CSV exports with named columns.
+type columnDecoder struct {
+	r       *csv.Reader
+	columns columns
+	// index is the index of URL, short code and creation time columns,
+	// -1 if column is absent.
+	index      [3]int
+	readHeader bool
+}
+
+// newColumnDecoder creates and returns a new columnDecoder instance.
+func newColumnDecoder(r io.Reader, c columns) *columnDecoder {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	return &columnDecoder{r: cr, columns: c}
+}
+
+// Decode implements Decoder interface.
+func (d *columnDecoder) Decode() (service.Link, error) {
+	if !d.readHeader {
+		header, err := readHeader(d.r)
+		if err != nil {
+			return service.Link{}, err
+		}
+		for i, names := range [][]string{d.columns.URL, d.columns.Code, d.columns.Created} {
+			d.index[i] = column(header, names)
+		}
+		if d.index[0] < 0 || d.index[1] < 0 {
+			return service.Link{}, malformed(fmt.Errorf("invalid CSV header: %v", header))
+		}
+		d.readHeader = true
+	}
+
+	row, err := d.r.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return service.Link{}, invalidRecord(err)
+	} else if err != nil {
+		return service.Link{}, err
+	}
+
+	fields := make([]string, len(d.index))
+	for i, c := range d.index {
+		if c >= len(row) {
+			return service.Link{}, invalidRecord(fmt.Errorf("expected at least %d fields, got %d", c+1, len(row)))
+		} else if c >= 0 {
+			fields[i] = row[c]
+		}
+	}
+
+	return importedLink(fields[0], fields[1], fields[2])
+}
+
+// column returns index of the first of names in header, or -1
+// if there is none of them.
+func column(header, names []string) int {
+	for _, name := range names {
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), name) {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// kuttLink is a link of FormatKutt.
+type kuttLink struct {
+	Address   string `json:"address"`
+	Target    string `json:"target"`
+	CreatedAt string `json:"created_at"`
+}
+
+// kuttDecoder is Decoder of FormatKutt.
+type kuttDecoder struct {
+	d *json.Decoder
+	// inArray reports whether the decoder is inside the array of links.
+	inArray bool
+}
+
+// Decode implements Decoder interface.
+func (d *kuttDecoder) Decode() (service.Link, error) {
+	if !d.inArray {
+		if err := d.findLinks(); err != nil {
+			return service.Link{}, err
+		}
+		d.inArray = true
+	}
+
+	if !d.d.More() {
+		// the array must be closed
+		if _, err := d.d.Token(); err != nil {
+			return service.Link{}, jsonError(err)
+		}
+		return service.Link{}, io.EOF
+	}
+
+	var raw json.RawMessage
+	if err := d.d.Decode(&raw); err != nil {
+		return service.Link{}, jsonError(err)
+	}
+	var l kuttLink
+	if err := json.Unmarshal(raw, &l); err != nil {
+		return service.Link{}, invalidRecord(err)
+	}
+
+	return importedLink(l.Target, l.Address, l.CreatedAt)
+}
+
+// findLinks moves the decoder into the array of links, which is either
+// the top-level value or data field of API response.
+func (d *kuttDecoder) findLinks() error {
+	t, err := d.d.Token()
+	if err != nil {
+		return jsonError(err)
+	}
+	if t == json.Delim('[') {
+		return nil
+	} else if t != json.Delim('{') {
+		return malformed(errors.New("invalid Kutt export: expected object or array"))
+	}
+
+	for d.d.More() {
+		key, err := d.d.Token()
+		if err != nil {
+			return jsonError(err)
+		}
+		if key != "data" {
+			var skipped json.RawMessage
+			if err = d.d.Decode(&skipped); err != nil {
+				return jsonError(err)
+			}
+			continue
+		}
+
+		if t, err = d.d.Token(); err != nil {
+			return jsonError(err)
+		} else if t != json.Delim('[') {
+			return malformed(errors.New("invalid Kutt export: data is not an array"))
+		}
+		return nil
+	}
+
+	return malformed(errors.New("invalid Kutt export: data is missing"))
+}
+
+// jsonError wraps syntax errors of JSON input with ErrMalformedInput,
+// the decoder can't continue after them.
+func jsonError(err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || err == io.ErrUnexpectedEOF {
+		return malformed(err)
+	}
+
+	return err
+}
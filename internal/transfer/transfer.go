@@ -0,0 +1,329 @@
+// Package transfer encodes and decodes links for export and import.
+package transfer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"tinee/internal/service"
+)
+
+const (
+	// FormatCSV is CSV format with id, url, aliases and metadata columns.
+	// Aliases are separated by spaces, metadata is JSON object.
+	FormatCSV = "csv"
+	// FormatJSONL is JSON Lines format with a Record per line.
+	FormatJSONL = "jsonl"
+)
+
+var (
+	// ErrUnknownFormat is returned when unknown format was provided.
+	ErrUnknownFormat = errors.New("unknown format")
+	// ErrMalformedInput is returned when input can't be decoded any further,
+	// e.g. because its header is invalid.
+	ErrMalformedInput = errors.New("malformed input")
+)
+
+// maxLineSize is max size of a line of FormatJSONL.
+const maxLineSize = 1 << 20
+
+// csvHeader is the header of CSV format.
+var csvHeader = []string{"id", "url", "aliases", "metadata"}
+
+// Metadata is service.Link settings.
+type Metadata struct {
+	CreatedAt  *time.Time        `json:"createdAt,omitempty"`
+	Forwarding *Forwarding       `json:"forwarding,omitempty"`
+	Params     map[string]string `json:"params,omitempty"`
+	Targets    []Target          `json:"targets,omitempty"`
+	Variants   []Variant         `json:"variants,omitempty"`
+}
+
+// Forwarding is service.Forwarding of Metadata.
+type Forwarding struct {
+	Path  bool                `json:"path"`
+	Query service.QueryPolicy `json:"query"`
+}
+
+// Target is service.Target of Metadata.
+type Target struct {
+	URL       string   `json:"url"`
+	Devices   []string `json:"devices,omitempty"`
+	Languages []string `json:"languages,omitempty"`
+	Countries []string `json:"countries,omitempty"`
+}
+
+// Variant is service.Variant of Metadata.
+type Variant struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
+// Record is exported service.Link.
+type Record struct {
+	ID      string   `json:"id"`
+	URL     string   `json:"url"`
+	Aliases []string `json:"aliases"`
+	Metadata
+}
+
+// NewRecord creates and returns a new Record of service.Link.
+func NewRecord(l service.Link) Record {
+	r := Record{
+		ID:       l.ID,
+		URL:      l.URL,
+		Aliases:  l.Aliases,
+		Metadata: Metadata{Params: l.Params},
+	}
+	if !l.CreatedAt.IsZero() {
+		createdAt := l.CreatedAt.UTC()
+		r.CreatedAt = &createdAt
+	}
+	if l.Forwarding != (service.Forwarding{}) {
+		r.Forwarding = &Forwarding{Path: l.Forwarding.Path, Query: l.Forwarding.Query}
+	}
+	for _, t := range l.Targets {
+		r.Targets = append(r.Targets, Target(t))
+	}
+	for _, v := range l.Variants {
+		r.Variants = append(r.Variants, Variant(v))
+	}
+
+	return r
+}
+
+// Link returns service.Link of the Record.
+func (r Record) Link() service.Link {
+	l := service.Link{
+		ID:      r.ID,
+		URL:     r.URL,
+		Aliases: r.Aliases,
+		Params:  r.Params,
+	}
+	if r.CreatedAt != nil {
+		l.CreatedAt = *r.CreatedAt
+	}
+	if r.Forwarding != nil {
+		l.Forwarding = service.Forwarding{Path: r.Forwarding.Path, Query: r.Forwarding.Query}
+	}
+	for _, t := range r.Targets {
+		l.Targets = append(l.Targets, service.Target(t))
+	}
+	for _, v := range r.Variants {
+		l.Variants = append(l.Variants, service.Variant(v))
+	}
+
+	return l
+}
+
+// ContentType returns MIME type of the format.
+func ContentType(format string) string {
+	if format == FormatCSV {
+		return "text/csv"
+	}
+
+	return "application/x-ndjson"
+}
+
+// Encoder encodes service.Links.
+type Encoder interface {
+	Encode(service.Link) error
+	// Flush writes buffered data to the underlying writer.
+	Flush() error
+}
+
+// NewEncoder creates and returns a new Encoder of the format.
+func NewEncoder(w io.Writer, format string) (Encoder, error) {
+	switch format {
+	case FormatCSV:
+		return &csvEncoder{w: csv.NewWriter(w)}, nil
+	case FormatJSONL:
+		bw := bufio.NewWriter(w)
+		return &jsonlEncoder{w: bw, enc: json.NewEncoder(bw)}, nil
+	}
+
+	return nil, ErrUnknownFormat
+}
+
+// Decoder decodes service.Links. Decode returns io.EOF when there are no
+// more records, and error wrapping service.ErrInvalidRecord when a record
+// is malformed and can be skipped.
+type Decoder interface {
+	Decode() (service.Link, error)
+}
+
+// NewDecoder creates and returns a new Decoder of the format.
+// Besides formats of tinee exports, exports of other URL shorteners
+// are decoded: FormatBitly, FormatYOURLS and FormatKutt.
+func NewDecoder(r io.Reader, format string) (Decoder, error) {
+	switch format {
+	case FormatCSV:
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = -1
+		return &csvDecoder{r: cr}, nil
+	case FormatJSONL:
+		s := bufio.NewScanner(r)
+		s.Buffer(nil, maxLineSize)
+		return &jsonlDecoder{s: s}, nil
+	case FormatBitly:
+		return newColumnDecoder(r, bitlyColumns), nil
+	case FormatYOURLS:
+		return newColumnDecoder(r, yourlsColumns), nil
+	case FormatKutt:
+		return &kuttDecoder{d: json.NewDecoder(r)}, nil
+	}
+
+	return nil, ErrUnknownFormat
+}
+
+// invalidRecord wraps err with service.ErrInvalidRecord.
+func invalidRecord(err error) error {
+	return fmt.Errorf("%w: %v", service.ErrInvalidRecord, err)
+}
+
+// malformed wraps err with ErrMalformedInput.
+func malformed(err error) error {
+	return fmt.Errorf("%w: %v", ErrMalformedInput, err)
+}
+
+// readHeader reads header of CSV input.
+func readHeader(r *csv.Reader) ([]string, error) {
+	header, err := r.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, malformed(err)
+	}
+
+	return header, err
+}
+
+// csvEncoder is Encoder of CSV format.
+type csvEncoder struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+// Encode implements Encoder interface.
+func (e *csvEncoder) Encode(l service.Link) error {
+	if !e.wroteHeader {
+		if err := e.w.Write(csvHeader); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+
+	r := NewRecord(l)
+	var metadata string
+	if b, err := json.Marshal(r.Metadata); err != nil {
+		return err
+	} else if string(b) != "{}" {
+		metadata = string(b)
+	}
+
+	return e.w.Write([]string{r.ID, r.URL, strings.Join(r.Aliases, " "), metadata})
+}
+
+// Flush implements Encoder interface.
+func (e *csvEncoder) Flush() error {
+	if !e.wroteHeader {
+		if err := e.w.Write(csvHeader); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+	e.w.Flush()
+
+	return e.w.Error()
+}
+
+// csvDecoder is Decoder of CSV format.
+type csvDecoder struct {
+	r          *csv.Reader
+	readHeader bool
+}
+
+// Decode implements Decoder interface.
+func (d *csvDecoder) Decode() (service.Link, error) {
+	if !d.readHeader {
+		header, err := readHeader(d.r)
+		if err != nil {
+			return service.Link{}, err
+		}
+		if strings.Join(header, ",") != strings.Join(csvHeader, ",") {
+			return service.Link{}, malformed(fmt.Errorf("invalid CSV header: %v", header))
+		}
+		d.readHeader = true
+	}
+
+	row, err := d.r.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return service.Link{}, invalidRecord(err)
+	} else if err != nil {
+		return service.Link{}, err
+	}
+	if len(row) != len(csvHeader) {
+		return service.Link{}, invalidRecord(fmt.Errorf("expected %d fields, got %d", len(csvHeader), len(row)))
+	}
+
+	r := Record{ID: row[0], URL: row[1], Aliases: strings.Fields(row[2])}
+	if row[3] != "" {
+		if err = json.Unmarshal([]byte(row[3]), &r.Metadata); err != nil {
+			return service.Link{}, invalidRecord(err)
+		}
+	}
+
+	return r.Link(), nil
+}
+
+// jsonlEncoder is Encoder of JSON Lines format.
+type jsonlEncoder struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+// Encode implements Encoder interface.
+func (e *jsonlEncoder) Encode(l service.Link) error {
+	return e.enc.Encode(NewRecord(l))
+}
+
+// Flush implements Encoder interface.
+func (e *jsonlEncoder) Flush() error {
+	return e.w.Flush()
+}
+
+// jsonlDecoder is Decoder of JSON Lines format.
+type jsonlDecoder struct {
+	s *bufio.Scanner
+}
+
+// Decode implements Decoder interface.
+func (d *jsonlDecoder) Decode() (service.Link, error) {
+	var line []byte
+	for len(line) == 0 {
+		if !d.s.Scan() {
+			if err := d.s.Err(); err == bufio.ErrTooLong {
+				return service.Link{}, malformed(fmt.Errorf("line exceeds %d bytes", maxLineSize))
+			} else if err != nil {
+				return service.Link{}, err
+			}
+			return service.Link{}, io.EOF
+		}
+		line = bytes.TrimSpace(d.s.Bytes())
+	}
+
+	var r Record
+	if err := json.Unmarshal(line, &r); err != nil {
+		return service.Link{}, invalidRecord(err)
+	}
+
+	return r.Link(), nil
+}
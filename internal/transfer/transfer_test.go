@@ -0,0 +1,276 @@
+package transfer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"tinee/internal/service"
+)
+
+var links = []service.Link{
+	{ID: "x", URL: "https://x.xx", Aliases: []string{"xxxxxxxx", "xxxx"}},
+	{
+		ID:         "y",
+		URL:        "https://y.yy",
+		Aliases:    []string{"yyyyyyyy"},
+		Forwarding: service.Forwarding{Path: true, Query: service.QueryKeep},
+		Params:     map[string]string{"utm_source": "tinee"},
+		Targets:    []service.Target{{URL: "https://y.yy/ios", Devices: []string{service.DeviceIOS}}},
+		Variants:   []service.Variant{{Name: "aaaa", URL: "https://y.yy/a", Weight: 1}},
+	},
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, format := range []string{FormatCSV, FormatJSONL} {
+		t.Run(format, func(t *testing.T) {
+			is := is.New(t)
+			var b bytes.Buffer
+
+			enc, err := NewEncoder(&b, format)
+			is.NoErr(err)
+			for _, l := range links {
+				is.NoErr(enc.Encode(l))
+			}
+			is.NoErr(enc.Flush())
+
+			dec, err := NewDecoder(&b, format)
+			is.NoErr(err)
+			var decoded []service.Link
+			for {
+				l, err := dec.Decode()
+				if err == io.EOF {
+					break
+				}
+				is.NoErr(err)
+				decoded = append(decoded, l)
+			}
+
+			is.Equal(links, decoded)
+		})
+	}
+}
+
+func TestEncoder_CSV(t *testing.T) {
+	is := is.New(t)
+	var b bytes.Buffer
+
+	enc, err := NewEncoder(&b, FormatCSV)
+	is.NoErr(err)
+	is.NoErr(enc.Encode(links[0]))
+	is.NoErr(enc.Flush())
+
+	is.Equal("id,url,aliases,metadata\nx,https://x.xx,xxxxxxxx xxxx,\n", b.String())
+}
+
+func TestDecoder_InvalidRecords(t *testing.T) {
+	testcases := []struct {
+		name   string
+		format string
+		data   string
+	}{
+		{
+			name:   "CSV with wrong number of fields",
+			format: FormatCSV,
+			data:   "id,url,aliases,metadata\nx,https://x.xx\ny,https://y.yy,yyyy,\n",
+		},
+		{
+			name:   "CSV with invalid metadata",
+			format: FormatCSV,
+			data:   "id,url,aliases,metadata\nx,https://x.xx,xxxx,{\ny,https://y.yy,yyyy,\n",
+		},
+		{
+			name:   "invalid JSON line",
+			format: FormatJSONL,
+			data:   "{\"id\":\n\n{\"id\":\"y\",\"url\":\"https://y.yy\",\"aliases\":[\"yyyy\"]}\n",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			dec, err := NewDecoder(strings.NewReader(tc.data), tc.format)
+			is.NoErr(err)
+
+			_, err = dec.Decode()
+			is.True(errors.Is(err, service.ErrInvalidRecord))
+
+			l, err := dec.Decode()
+			is.NoErr(err)
+			is.Equal(service.Link{ID: "y", URL: "https://y.yy", Aliases: []string{"yyyy"}}, l)
+
+			_, err = dec.Decode()
+			is.Equal(io.EOF, err)
+		})
+	}
+}
+
+func TestDecoder_MalformedInput(t *testing.T) {
+	testcases := []struct {
+		name   string
+		format string
+		data   string
+	}{
+		{name: "invalid CSV header", format: FormatCSV, data: "url,alias\n"},
+		{name: "unparsable CSV header", format: FormatCSV, data: "\"id,url\n"},
+		{name: "too long JSON line", format: FormatJSONL, data: strings.Repeat("x", maxLineSize+1)},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			dec, err := NewDecoder(strings.NewReader(tc.data), tc.format)
+			is.NoErr(err)
+
+			_, err = dec.Decode()
+
+			is.True(errors.Is(err, ErrMalformedInput))
+		})
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	is := is.New(t)
+
+	_, err := NewEncoder(io.Discard, "xml")
+	is.Equal(ErrUnknownFormat, err)
+	_, err = NewDecoder(strings.NewReader(""), "xml")
+	is.Equal(ErrUnknownFormat, err)
+}
+
+func TestEncodeDecode_CreatedAt(t *testing.T) {
+	is := is.New(t)
+	l := service.Link{
+		ID:        "x",
+		URL:       "https://x.xx",
+		Aliases:   []string{"xxxx"},
+		CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	var b bytes.Buffer
+
+	enc, err := NewEncoder(&b, FormatJSONL)
+	is.NoErr(err)
+	is.NoErr(enc.Encode(l))
+	is.NoErr(enc.Flush())
+	is.Equal(`{"id":"x","url":"https://x.xx","aliases":["xxxx"],"createdAt":"2021-01-01T00:00:00Z"}`+"\n", b.String())
+
+	dec, err := NewDecoder(&b, FormatJSONL)
+	is.NoErr(err)
+	decoded, err := dec.Decode()
+	is.NoErr(err)
+	is.Equal(l, decoded)
+}
+
+func TestDecoder_External(t *testing.T) {
+	created := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	testcases := []struct {
+		name     string
+		format   string
+		data     string
+		expLinks []service.Link
+		expErrs  []bool
+	}{
+		{
+			name:   "Bitly",
+			format: FormatBitly,
+			data: "Title,Long URL,Bitlink,Created At\n" +
+				"x,https://x.xx,https://bit.ly/3xXxXxX,2021-01-02T03:04:05+0000\n" +
+				"y,https://y.yy,bit.ly/yyyy,\n" +
+				"z,https://z.zz,bit.ly/zzzz,yesterday\n" +
+				"w,https://w.ww\n",
+			expLinks: []service.Link{
+				{URL: "https://x.xx", Aliases: []string{"3xXxXxX"}, CreatedAt: created},
+				{URL: "https://y.yy", Aliases: []string{"yyyy"}},
+				{},
+				{},
+			},
+			expErrs: []bool{false, false, true, true},
+		},
+		{
+			name:   "YOURLS",
+			format: FormatYOURLS,
+			data: "keyword,url,title,timestamp,ip,clicks\n" +
+				"xxxx,https://x.xx,x,2021-01-02 03:04:05,127.0.0.1,10\n" +
+				"yyyy,https://y.yy,y,2021-01-02T03:04:05Z,127.0.0.1,0\n",
+			expLinks: []service.Link{
+				{URL: "https://x.xx", Aliases: []string{"xxxx"}, CreatedAt: created},
+				{URL: "https://y.yy", Aliases: []string{"yyyy"}, CreatedAt: created},
+			},
+			expErrs: []bool{false, false},
+		},
+		{
+			name:   "Kutt API response",
+			format: FormatKutt,
+			data: `{"limit":10,"skip":0,"data":[` +
+				`{"address":"xxxx","target":"https://x.xx","link":"https://kutt.it/xxxx","created_at":"2021-01-02T03:04:05.000Z"},` +
+				`{"address":"yyyy","target":"https://y.yy","created_at":7}` +
+				`],"total":2}`,
+			expLinks: []service.Link{
+				{URL: "https://x.xx", Aliases: []string{"xxxx"}, CreatedAt: created},
+				{},
+			},
+			expErrs: []bool{false, true},
+		},
+		{
+			name:   "Kutt array",
+			format: FormatKutt,
+			data:   `[{"address":"xxxx","target":"https://x.xx"}]`,
+			expLinks: []service.Link{
+				{URL: "https://x.xx", Aliases: []string{"xxxx"}},
+			},
+			expErrs: []bool{false},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			dec, err := NewDecoder(strings.NewReader(tc.data), tc.format)
+			is.NoErr(err)
+
+			for i, expLink := range tc.expLinks {
+				l, err := dec.Decode()
+				is.Equal(tc.expErrs[i], errors.Is(err, service.ErrInvalidRecord))
+				if !tc.expErrs[i] {
+					is.NoErr(err)
+				}
+				is.Equal(expLink, l)
+			}
+
+			_, err = dec.Decode()
+			is.Equal(io.EOF, err)
+		})
+	}
+}
+
+func TestDecoder_ExternalInvalid(t *testing.T) {
+	testcases := []struct {
+		name   string
+		format string
+		data   string
+	}{
+		{name: "Bitly without link column", format: FormatBitly, data: "long_url,title\n"},
+		{name: "YOURLS without url column", format: FormatYOURLS, data: "keyword,title\n"},
+		{name: "Kutt without data", format: FormatKutt, data: `{"total":0}`},
+		{name: "Kutt with invalid data", format: FormatKutt, data: `{"data":{}}`},
+		{name: "Kutt string", format: FormatKutt, data: `"x"`},
+		{name: "Kutt with invalid JSON", format: FormatKutt, data: `{"data":[}`},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			dec, err := NewDecoder(strings.NewReader(tc.data), tc.format)
+			is.NoErr(err)
+
+			_, err = dec.Decode()
+
+			is.True(errors.Is(err, ErrMalformedInput))
+		})
+	}
+}
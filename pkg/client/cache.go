@@ -0,0 +1,84 @@
+package client
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// cache is LRU cache of resolved aliases with expiration. Nil cache
+// caches nothing.
+type cache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	lru     *list.List
+	now     func() time.Time
+}
+
+// entry is an entry of cache.
+type entry struct {
+	alias   string
+	URL     string
+	expires time.Time
+}
+
+// newCache creates and returns a new cache instance.
+func newCache(size int, ttl time.Duration) *cache {
+	return &cache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element, size),
+		lru:     list.New(),
+		now:     time.Now,
+	}
+}
+
+// get returns URL of alias if it is cached and not expired.
+func (c *cache) get(alias string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[alias]
+	if !ok {
+		return "", false
+	}
+	e := el.Value.(*entry)
+	if c.now().After(e.expires) {
+		c.lru.Remove(el)
+		delete(c.entries, alias)
+		return "", false
+	}
+	c.lru.MoveToFront(el)
+
+	return e.URL, true
+}
+
+// set caches URL of alias, evicting the least recently used alias
+// if the cache is full.
+func (c *cache) set(alias, URL string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.now().Add(c.ttl)
+	if el, ok := c.entries[alias]; ok {
+		e := el.Value.(*entry)
+		e.URL, e.expires = URL, expires
+		c.lru.MoveToFront(el)
+		return
+	}
+
+	c.entries[alias] = c.lru.PushFront(&entry{alias: alias, URL: URL, expires: expires})
+	if c.lru.Len() > c.size {
+		el := c.lru.Back()
+		c.lru.Remove(el)
+		delete(c.entries, el.Value.(*entry).alias)
+	}
+}
@@ -0,0 +1,212 @@
+// Package client provides client of tinee gRPC and HTTP APIs.
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrInvalidURL is returned when URL is invalid.
+	ErrInvalidURL = errors.New("invalid URL")
+	// ErrInvalidAlias is returned when alias is invalid or taken.
+	ErrInvalidAlias = errors.New("invalid alias")
+	// ErrNotFound is returned when there is no link with alias.
+	ErrNotFound = errors.New("link not found")
+	// ErrUnauthenticated is returned when API key is missing or invalid.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrUnavailable is returned when tinee is unavailable after all
+	// retries, it wraps the last error.
+	ErrUnavailable = errors.New("tinee is unavailable")
+)
+
+// Client is client of tinee API.
+type Client interface {
+	// Shorten shortens URL, with custom alias if it is not empty.
+	Shorten(ctx context.Context, URL, alias string) (tineeURL string, err error)
+	// Resolve returns URL that corresponds to alias.
+	Resolve(ctx context.Context, alias string) (URL string, err error)
+	// ResolveMany returns URLs that correspond to aliases, aliases without
+	// URL are omitted.
+	ResolveMany(ctx context.Context, aliases []string) (map[string]string, error)
+	// Close releases resources of the Client.
+	Close() error
+}
+
+// Options are options of Client, zero values disable the corresponding
+// features.
+type Options struct {
+	// APIKey is sent as bearer token of requests.
+	APIKey string
+	// Timeout is deadline of a single attempt of a request.
+	Timeout time.Duration
+	// Retries is max number of retries of requests failed with transient
+	// errors.
+	Retries int
+	// Backoff is delay before the first retry, it doubles with every
+	// next retry.
+	Backoff time.Duration
+	// CacheSize is max number of resolved aliases cached locally.
+	CacheSize int
+	// CacheTTL is time resolved aliases are cached for.
+	CacheTTL time.Duration
+}
+
+// DefaultOptions returns recommended Options.
+func DefaultOptions() Options {
+	return Options{
+		Timeout:   5 * time.Second,
+		Retries:   3,
+		Backoff:   100 * time.Millisecond,
+		CacheSize: 1000,
+		CacheTTL:  time.Minute,
+	}
+}
+
+// transport makes requests to tinee API. Its errors are either errors
+// of the package or transient errors wrapped with transientError.
+type transport interface {
+	shorten(ctx context.Context, URL, alias string) (string, error)
+	resolve(ctx context.Context, alias string) (string, error)
+	resolveMany(ctx context.Context, aliases []string) (map[string]string, error)
+	close() error
+}
+
+// transientError is an error request can be retried after.
+type transientError struct {
+	err error
+}
+
+// Error implements error interface.
+func (e transientError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e transientError) Unwrap() error {
+	return e.err
+}
+
+// client is Client that retries requests of transport and caches
+// resolved aliases.
+type client struct {
+	o     Options
+	t     transport
+	cache *cache
+}
+
+// newClient creates and returns a new client instance.
+func newClient(o Options, t transport) *client {
+	c := &client{o: o, t: t}
+	if o.CacheSize > 0 && o.CacheTTL > 0 {
+		c.cache = newCache(o.CacheSize, o.CacheTTL)
+	}
+
+	return c
+}
+
+// Shorten implements Client interface.
+func (c *client) Shorten(ctx context.Context, URL, alias string) (tineeURL string, err error) {
+	err = c.retry(ctx, func(ctx context.Context) error {
+		tineeURL, err = c.t.shorten(ctx, URL, alias)
+		return err
+	})
+
+	return tineeURL, err
+}
+
+// Resolve implements Client interface.
+func (c *client) Resolve(ctx context.Context, alias string) (URL string, err error) {
+	if URL, ok := c.cache.get(alias); ok {
+		return URL, nil
+	}
+
+	err = c.retry(ctx, func(ctx context.Context) error {
+		URL, err = c.t.resolve(ctx, alias)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	c.cache.set(alias, URL)
+
+	return URL, nil
+}
+
+// ResolveMany implements Client interface.
+func (c *client) ResolveMany(ctx context.Context, aliases []string) (map[string]string, error) {
+	URLs := make(map[string]string, len(aliases))
+	var misses []string
+	for _, a := range aliases {
+		if URL, ok := c.cache.get(a); ok {
+			URLs[a] = URL
+		} else {
+			misses = append(misses, a)
+		}
+	}
+	if len(misses) == 0 {
+		return URLs, nil
+	}
+
+	var resolved map[string]string
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		resolved, err = c.t.resolveMany(ctx, misses)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for a, URL := range resolved {
+		URLs[a] = URL
+		c.cache.set(a, URL)
+	}
+
+	return URLs, nil
+}
+
+// Close implements Client interface.
+func (c *client) Close() error {
+	return c.t.close()
+}
+
+// retry calls fn with attempt deadline until it returns non-transient
+// error or retries are exhausted.
+func (c *client) retry(ctx context.Context, fn func(ctx context.Context) error) error {
+	backoff := c.o.Backoff
+	for attempt := 0; ; attempt++ {
+		err := c.attempt(ctx, fn)
+
+		var t transientError
+		if !errors.As(err, &t) {
+			return err
+		}
+		if attempt >= c.o.Retries || ctx.Err() != nil {
+			return wrapUnavailable(t.err)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return wrapUnavailable(t.err)
+		}
+		backoff *= 2
+	}
+}
+
+// attempt calls fn with attempt deadline.
+func (c *client) attempt(ctx context.Context, fn func(ctx context.Context) error) error {
+	if c.o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.o.Timeout)
+		defer cancel()
+	}
+
+	return fn(ctx)
+}
+
+// wrapUnavailable wraps the last error with ErrUnavailable.
+func wrapUnavailable(err error) error {
+	return fmt.Errorf("%w: %v", ErrUnavailable, err)
+}
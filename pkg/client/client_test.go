@@ -0,0 +1,206 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+type mockTransport struct {
+	shortenFunc     func(ctx context.Context, URL, alias string) (string, error)
+	resolveFunc     func(ctx context.Context, alias string) (string, error)
+	resolveManyFunc func(ctx context.Context, aliases []string) (map[string]string, error)
+}
+
+func (t *mockTransport) shorten(ctx context.Context, URL, alias string) (string, error) {
+	return t.shortenFunc(ctx, URL, alias)
+}
+
+func (t *mockTransport) resolve(ctx context.Context, alias string) (string, error) {
+	return t.resolveFunc(ctx, alias)
+}
+
+func (t *mockTransport) resolveMany(ctx context.Context, aliases []string) (map[string]string, error) {
+	return t.resolveManyFunc(ctx, aliases)
+}
+
+func (t *mockTransport) close() error {
+	return nil
+}
+
+func TestClient_Shorten(t *testing.T) {
+	testcases := []struct {
+		name        string
+		o           Options
+		errs        []error
+		expTineeURL string
+		expAttempts int
+		expErr      error
+	}{
+		{
+			name:        "URL is shortened",
+			o:           Options{Retries: 2},
+			expTineeURL: "tinee.io/xxxx",
+			expAttempts: 1,
+		},
+		{
+			name:        "transient errors are retried",
+			o:           Options{Retries: 2},
+			errs:        []error{transientError{errors.New("x")}, transientError{errors.New("x")}},
+			expTineeURL: "tinee.io/xxxx",
+			expAttempts: 3,
+		},
+		{
+			name:        "retries are exhausted",
+			o:           Options{Retries: 1},
+			errs:        []error{transientError{errors.New("x")}, transientError{errors.New("y")}},
+			expAttempts: 2,
+			expErr:      ErrUnavailable,
+		},
+		{
+			name:        "other errors are not retried",
+			o:           Options{Retries: 2},
+			errs:        []error{ErrInvalidURL},
+			expAttempts: 1,
+			expErr:      ErrInvalidURL,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			attempts := 0
+			tr := &mockTransport{
+				shortenFunc: func(ctx context.Context, URL, alias string) (string, error) {
+					attempts++
+					if len(tc.errs) >= attempts {
+						return "", tc.errs[attempts-1]
+					}
+
+					return "tinee.io/" + alias, nil
+				},
+			}
+			c := newClient(tc.o, tr)
+
+			tineeURL, err := c.Shorten(context.Background(), "https://x.xx", "xxxx")
+
+			is.True(errors.Is(err, tc.expErr))
+			is.Equal(tc.expTineeURL, tineeURL)
+			is.Equal(tc.expAttempts, attempts)
+		})
+	}
+}
+
+func TestClient_Timeout(t *testing.T) {
+	is := is.New(t)
+	tr := &mockTransport{
+		shortenFunc: func(ctx context.Context, URL, alias string) (string, error) {
+			if _, ok := ctx.Deadline(); !ok {
+				return "", errors.New("no deadline")
+			}
+
+			return "tinee.io/xxxx", nil
+		},
+	}
+	c := newClient(Options{Timeout: time.Second}, tr)
+
+	_, err := c.Shorten(context.Background(), "https://x.xx", "")
+
+	is.NoErr(err)
+}
+
+func TestClient_Resolve(t *testing.T) {
+	is := is.New(t)
+	calls := 0
+	tr := &mockTransport{
+		resolveFunc: func(ctx context.Context, alias string) (string, error) {
+			calls++
+			if alias == "none" {
+				return "", ErrNotFound
+			}
+
+			return "https://" + alias, nil
+		},
+	}
+	c := newClient(Options{CacheSize: 1, CacheTTL: time.Minute}, tr)
+	ctx := context.Background()
+
+	URL, err := c.Resolve(ctx, "x.xx")
+	is.NoErr(err)
+	is.Equal("https://x.xx", URL)
+
+	URL, err = c.Resolve(ctx, "x.xx")
+	is.NoErr(err)
+	is.Equal("https://x.xx", URL)
+	is.Equal(1, calls) // resolved alias is cached
+
+	_, err = c.Resolve(ctx, "none")
+	is.Equal(ErrNotFound, err)
+	_, err = c.Resolve(ctx, "none")
+	is.Equal(ErrNotFound, err)
+	is.Equal(3, calls) // missing alias is not cached
+}
+
+func TestClient_ResolveMany(t *testing.T) {
+	is := is.New(t)
+	var requested [][]string
+	tr := &mockTransport{
+		resolveManyFunc: func(ctx context.Context, aliases []string) (map[string]string, error) {
+			requested = append(requested, aliases)
+			URLs := make(map[string]string)
+			for _, a := range aliases {
+				if a != "none" {
+					URLs[a] = "https://" + a
+				}
+			}
+
+			return URLs, nil
+		},
+	}
+	c := newClient(Options{CacheSize: 10, CacheTTL: time.Minute}, tr)
+	ctx := context.Background()
+
+	URLs, err := c.ResolveMany(ctx, []string{"x.xx", "none"})
+	is.NoErr(err)
+	is.Equal(map[string]string{"x.xx": "https://x.xx"}, URLs)
+
+	URLs, err = c.ResolveMany(ctx, []string{"x.xx", "y.yy"})
+	is.NoErr(err)
+	is.Equal(map[string]string{"x.xx": "https://x.xx", "y.yy": "https://y.yy"}, URLs)
+
+	URLs, err = c.ResolveMany(ctx, []string{"y.yy"})
+	is.NoErr(err)
+	is.Equal(map[string]string{"y.yy": "https://y.yy"}, URLs)
+
+	is.Equal([][]string{{"x.xx", "none"}, {"y.yy"}}, requested)
+}
+
+func TestCache(t *testing.T) {
+	is := is.New(t)
+	now := time.Now()
+	c := newCache(2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.set("x", "https://x.xx")
+	c.set("y", "https://y.yy")
+	_, _ = c.get("x")
+	c.set("z", "https://z.zz")
+
+	_, ok := c.get("y")
+	is.True(!ok) // least recently used alias is evicted
+	URL, ok := c.get("x")
+	is.True(ok)
+	is.Equal("https://x.xx", URL)
+
+	now = now.Add(2 * time.Minute)
+	_, ok = c.get("z")
+	is.True(!ok) // expired alias is not returned
+
+	var nilCache *cache
+	nilCache.set("x", "https://x.xx")
+	_, ok = nilCache.get("x")
+	is.True(!ok)
+}
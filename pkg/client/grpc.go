@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"tinee/pkg/pb"
+)
+
+// Dial connects to tinee gRPC API at addr and returns its Client,
+// the connection is closed by Client.Close.
+func Dial(ctx context.Context, o Options, addr string, opts ...grpc.DialOption) (Client, error) {
+	conn, err := grpc.DialContext(ctx, addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClient(o, &grpcTransport{c: pb.NewTineeURLClient(conn), conn: conn, apiKey: o.APIKey}), nil
+}
+
+// NewGRPC creates and returns a new Client of tinee gRPC API.
+func NewGRPC(o Options, c pb.TineeURLClient) Client {
+	return newClient(o, &grpcTransport{c: c, apiKey: o.APIKey})
+}
+
+// grpcTransport is transport of gRPC API.
+type grpcTransport struct {
+	c pb.TineeURLClient
+	// conn is connection owned by the transport, nil if it is not.
+	conn   *grpc.ClientConn
+	apiKey string
+}
+
+// shorten implements transport interface.
+func (t *grpcTransport) shorten(ctx context.Context, URL, alias string) (string, error) {
+	resp, err := t.c.Shorten(t.context(ctx), &pb.ShortenRequest{Url: URL, Alias: alias})
+	if err != nil {
+		return "", grpcError(err)
+	}
+
+	return resp.GetTineeUrl(), nil
+}
+
+// resolve implements transport interface.
+func (t *grpcTransport) resolve(ctx context.Context, alias string) (string, error) {
+	resp, err := t.c.UrlByAlias(t.context(ctx), &pb.UrlByAliasRequest{Alias: alias})
+	if err != nil {
+		return "", grpcError(err)
+	}
+
+	return resp.GetUrl(), nil
+}
+
+// resolveMany implements transport interface.
+func (t *grpcTransport) resolveMany(ctx context.Context, aliases []string) (map[string]string, error) {
+	resp, err := t.c.BatchUrlByAlias(t.context(ctx), &pb.BatchUrlByAliasRequest{Aliases: aliases})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return resp.GetUrls(), nil
+}
+
+// close implements transport interface.
+func (t *grpcTransport) close() error {
+	if t.conn == nil {
+		return nil
+	}
+
+	return t.conn.Close()
+}
+
+// context returns outgoing context with API key.
+func (t *grpcTransport) context(ctx context.Context) context.Context {
+	if t.apiKey == "" {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+t.apiKey)
+}
+
+// grpcError maps gRPC status error to error of the package.
+func grpcError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	switch st.Code() {
+	case codes.NotFound:
+		return ErrNotFound
+	case codes.AlreadyExists:
+		return ErrInvalidAlias
+	case codes.InvalidArgument:
+		for _, d := range st.Details() {
+			if br, ok := d.(*errdetails.BadRequest); ok {
+				for _, v := range br.GetFieldViolations() {
+					switch v.GetField() {
+					case "url":
+						return ErrInvalidURL
+					case "alias":
+						return ErrInvalidAlias
+					}
+				}
+			}
+		}
+		return messageError(st.Message(), err)
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return ErrUnauthenticated
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.DeadlineExceeded:
+		return transientError{err: err}
+	case codes.Unknown:
+		// servers without status mapping return service errors as is
+		return messageError(st.Message(), err)
+	}
+
+	return err
+}
+
+// messageError returns error of the package with message msg,
+// or err if there is no such error.
+func messageError(msg string, err error) error {
+	for _, e := range []error{ErrInvalidURL, ErrInvalidAlias, ErrNotFound} {
+		if msg == e.Error() {
+			return e
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"tinee/pkg/pb"
+)
+
+type mockTineeURLClient struct {
+	pb.TineeURLClient
+	shorten func(ctx context.Context, r *pb.ShortenRequest) (*pb.ShortenResponse, error)
+}
+
+func (c *mockTineeURLClient) Shorten(ctx context.Context, r *pb.ShortenRequest, opts ...grpc.CallOption) (*pb.ShortenResponse, error) {
+	return c.shorten(ctx, r)
+}
+
+func TestGRPC_Shorten(t *testing.T) {
+	is := is.New(t)
+	c := NewGRPC(Options{APIKey: "key"}, &mockTineeURLClient{
+		shorten: func(ctx context.Context, r *pb.ShortenRequest) (*pb.ShortenResponse, error) {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			if a := md.Get("authorization"); len(a) != 1 || a[0] != "Bearer key" {
+				return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+			}
+
+			return &pb.ShortenResponse{TineeUrl: "tinee.io/" + r.GetAlias()}, nil
+		},
+	})
+
+	tineeURL, err := c.Shorten(context.Background(), "https://x.xx", "xxxx")
+
+	is.NoErr(err)
+	is.Equal("tinee.io/xxxx", tineeURL)
+}
+
+func TestGRPCError(t *testing.T) {
+	withField := func(code codes.Code, field string) error {
+		st, _ := status.New(code, "invalid argument").WithDetails(&errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field}},
+		})
+
+		return st.Err()
+	}
+	unexpected := status.Error(codes.Internal, "internal")
+	testcases := []struct {
+		name         string
+		err          error
+		expErr       error
+		expTransient bool
+	}{
+		{name: "not found", err: status.Error(codes.NotFound, "x"), expErr: ErrNotFound},
+		{name: "already exists", err: status.Error(codes.AlreadyExists, "x"), expErr: ErrInvalidAlias},
+		{name: "invalid URL field", err: withField(codes.InvalidArgument, "url"), expErr: ErrInvalidURL},
+		{name: "invalid alias field", err: withField(codes.InvalidArgument, "alias"), expErr: ErrInvalidAlias},
+		{name: "invalid argument message", err: status.Error(codes.InvalidArgument, "invalid URL"), expErr: ErrInvalidURL},
+		{name: "unknown with service error", err: status.Error(codes.Unknown, "link not found"), expErr: ErrNotFound},
+		{name: "unauthenticated", err: status.Error(codes.Unauthenticated, "x"), expErr: ErrUnauthenticated},
+		{name: "permission denied", err: status.Error(codes.PermissionDenied, "x"), expErr: ErrUnauthenticated},
+		{name: "unavailable", err: status.Error(codes.Unavailable, "x"), expTransient: true},
+		{name: "deadline exceeded", err: status.Error(codes.DeadlineExceeded, "x"), expTransient: true},
+		{name: "internal", err: unexpected, expErr: unexpected},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			err := grpcError(tc.err)
+
+			var transient transientError
+			is.Equal(tc.expTransient, errors.As(err, &transient))
+			if !tc.expTransient {
+				is.Equal(tc.expErr, err)
+			}
+		})
+	}
+}
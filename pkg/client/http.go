@@ -0,0 +1,126 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// NewHTTP creates and returns a new Client of tinee HTTP API at baseURL,
+// such as https://tinee.io. http.DefaultClient is used if hc is nil.
+func NewHTTP(o Options, baseURL string, hc *http.Client) Client {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	return newClient(o, &httpTransport{
+		hc:      hc,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  o.APIKey,
+	})
+}
+
+// httpTransport is transport of HTTP API.
+type httpTransport struct {
+	hc      *http.Client
+	baseURL string
+	apiKey  string
+}
+
+// shorten implements transport interface.
+func (t *httpTransport) shorten(ctx context.Context, URL, alias string) (string, error) {
+	var o struct {
+		TineeURL string `json:"tineeUrl"`
+	}
+	i := map[string]string{"url": URL, "alias": alias}
+	if err := t.post(ctx, "/api/v1/shorten", i, &o); err != nil {
+		return "", err
+	}
+
+	return o.TineeURL, nil
+}
+
+// resolve implements transport interface.
+func (t *httpTransport) resolve(ctx context.Context, alias string) (string, error) {
+	URLs, err := t.resolveMany(ctx, []string{alias})
+	if err != nil {
+		return "", err
+	}
+	URL, ok := URLs[alias]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return URL, nil
+}
+
+// resolveMany implements transport interface.
+func (t *httpTransport) resolveMany(ctx context.Context, aliases []string) (map[string]string, error) {
+	var o struct {
+		URLs map[string]string `json:"urls"`
+	}
+	i := map[string][]string{"aliases": aliases}
+	if err := t.post(ctx, "/api/v1/resolve", i, &o); err != nil {
+		return nil, err
+	}
+
+	return o.URLs, nil
+}
+
+// close implements transport interface.
+func (t *httpTransport) close() error {
+	return nil
+}
+
+// post posts JSON of i to the path and decodes JSON response into o.
+func (t *httpTransport) post(ctx context.Context, path string, i, o interface{}) error {
+	b, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+path, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	if t.apiKey != "" {
+		r.Header.Set("Authorization", "Bearer "+t.apiKey)
+	}
+
+	resp, err := t.hc.Do(r)
+	if err != nil {
+		return transientError{err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return httpError(resp)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(o)
+}
+
+// httpError maps error response to error of the package.
+func httpError(resp *http.Response) error {
+	var o struct {
+		Error string `json:"error"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&o)
+	err := fmt.Errorf("unexpected status %d %s", resp.StatusCode, o.Error)
+
+	switch resp.StatusCode {
+	case http.StatusBadRequest:
+		return messageError(o.Error, err)
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthenticated
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return transientError{err: err}
+	}
+
+	return err
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestHTTP(t *testing.T) {
+	unavailable := 1
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "Bearer key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/v1/shorten":
+			var i struct{ URL, Alias string }
+			_ = json.NewDecoder(r.Body).Decode(&i)
+			if unavailable > 0 {
+				unavailable--
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			if i.URL == "x.xx" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid URL"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"tineeUrl":"tinee.io/` + i.Alias + `"}`))
+		case "/api/v1/resolve":
+			_, _ = w.Write([]byte(`{"urls":{"xxxx":"https://x.xx"}}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	is := is.New(t)
+	ctx := context.Background()
+	c := NewHTTP(Options{APIKey: "key", Retries: 1}, srv.URL+"/", nil)
+	defer c.Close()
+
+	tineeURL, err := c.Shorten(ctx, "https://x.xx", "xxxx")
+	is.NoErr(err)
+	is.Equal("tinee.io/xxxx", tineeURL)
+
+	_, err = c.Shorten(ctx, "x.xx", "")
+	is.Equal(ErrInvalidURL, err)
+
+	URL, err := c.Resolve(ctx, "xxxx")
+	is.NoErr(err)
+	is.Equal("https://x.xx", URL)
+
+	_, err = c.Resolve(ctx, "none")
+	is.Equal(ErrNotFound, err)
+
+	URLs, err := c.ResolveMany(ctx, []string{"xxxx", "none"})
+	is.NoErr(err)
+	is.Equal(map[string]string{"xxxx": "https://x.xx"}, URLs)
+
+	_, err = NewHTTP(Options{}, srv.URL, nil).Shorten(ctx, "https://x.xx", "")
+	is.Equal(ErrUnauthenticated, err)
+
+	_, err = NewHTTP(Options{APIKey: "key"}, "http://127.0.0.1:0", nil).Shorten(ctx, "https://x.xx", "")
+	is.True(errors.Is(err, ErrUnavailable))
+}
@@ -8,11 +8,13 @@ package pb
 
 import (
 	context "context"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -131,6 +133,64 @@ func (x *ShortenResponse) GetTineeUrl() string {
 	return ""
 }
 
+// Streaming shortening URL response.
+type ShortenStreamResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Shortened URL, empty if URL was not shortened.
+	TineeUrl string `protobuf:"bytes,1,opt,name=tinee_url,json=tineeUrl,proto3" json:"tinee_url,omitempty"`
+	// Error of shortening, empty if URL was shortened.
+	Error string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *ShortenStreamResponse) Reset() {
+	*x = ShortenStreamResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tinee_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortenStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortenStreamResponse) ProtoMessage() {}
+
+func (x *ShortenStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tinee_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortenStreamResponse.ProtoReflect.Descriptor instead.
+func (*ShortenStreamResponse) Descriptor() ([]byte, []int) {
+	return file_tinee_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ShortenStreamResponse) GetTineeUrl() string {
+	if x != nil {
+		return x.TineeUrl
+	}
+	return ""
+}
+
+func (x *ShortenStreamResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 // Retrieving URL by alias request.
 type UrlByAliasRequest struct {
 	state         protoimpl.MessageState
@@ -144,7 +204,7 @@ type UrlByAliasRequest struct {
 func (x *UrlByAliasRequest) Reset() {
 	*x = UrlByAliasRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_tinee_proto_msgTypes[2]
+		mi := &file_tinee_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -157,146 +217,1216 @@ func (x *UrlByAliasRequest) String() string {
 func (*UrlByAliasRequest) ProtoMessage() {}
 
 func (x *UrlByAliasRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_tinee_proto_msgTypes[2]
+	mi := &file_tinee_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UrlByAliasRequest.ProtoReflect.Descriptor instead.
+func (*UrlByAliasRequest) Descriptor() ([]byte, []int) {
+	return file_tinee_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UrlByAliasRequest) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+// Retrieving URL by alias response.
+type UrlByAliasResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// URL alias corresponds to.
+	Url string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+}
+
+func (x *UrlByAliasResponse) Reset() {
+	*x = UrlByAliasResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tinee_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UrlByAliasResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UrlByAliasResponse) ProtoMessage() {}
+
+func (x *UrlByAliasResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tinee_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UrlByAliasResponse.ProtoReflect.Descriptor instead.
+func (*UrlByAliasResponse) Descriptor() ([]byte, []int) {
+	return file_tinee_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UrlByAliasResponse) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+// Retrieving URLs by aliases request.
+type BatchUrlByAliasRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Aliases of the URLs.
+	Aliases []string `protobuf:"bytes,1,rep,name=aliases,proto3" json:"aliases,omitempty"`
+}
+
+func (x *BatchUrlByAliasRequest) Reset() {
+	*x = BatchUrlByAliasRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tinee_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchUrlByAliasRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchUrlByAliasRequest) ProtoMessage() {}
+
+func (x *BatchUrlByAliasRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tinee_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchUrlByAliasRequest.ProtoReflect.Descriptor instead.
+func (*BatchUrlByAliasRequest) Descriptor() ([]byte, []int) {
+	return file_tinee_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BatchUrlByAliasRequest) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+// Retrieving URLs by aliases response.
+type BatchUrlByAliasResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// URLs by aliases, aliases without URL are omitted.
+	Urls map[string]string `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *BatchUrlByAliasResponse) Reset() {
+	*x = BatchUrlByAliasResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tinee_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchUrlByAliasResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchUrlByAliasResponse) ProtoMessage() {}
+
+func (x *BatchUrlByAliasResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tinee_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchUrlByAliasResponse.ProtoReflect.Descriptor instead.
+func (*BatchUrlByAliasResponse) Descriptor() ([]byte, []int) {
+	return file_tinee_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BatchUrlByAliasResponse) GetUrls() map[string]string {
+	if x != nil {
+		return x.Urls
+	}
+	return nil
+}
+
+// Weighted destination of the link.
+type Variant struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the variant.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// URL of the variant.
+	Url string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	// Weight of the variant.
+	Weight uint32 `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	// Number of clicks the variant received, ignored when setting variants.
+	Clicks int64 `protobuf:"varint,4,opt,name=clicks,proto3" json:"clicks,omitempty"`
+}
+
+func (x *Variant) Reset() {
+	*x = Variant{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tinee_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Variant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Variant) ProtoMessage() {}
+
+func (x *Variant) ProtoReflect() protoreflect.Message {
+	mi := &file_tinee_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Variant.ProtoReflect.Descriptor instead.
+func (*Variant) Descriptor() ([]byte, []int) {
+	return file_tinee_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Variant) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Variant) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Variant) GetWeight() uint32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *Variant) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+// Setting link variants request.
+type SetVariantsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Alias of the link.
+	Alias string `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	// Variants of the link, empty to remove all variants.
+	Variants []*Variant `protobuf:"bytes,2,rep,name=variants,proto3" json:"variants,omitempty"`
+}
+
+func (x *SetVariantsRequest) Reset() {
+	*x = SetVariantsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tinee_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetVariantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVariantsRequest) ProtoMessage() {}
+
+func (x *SetVariantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tinee_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
 		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use UrlByAliasRequest.ProtoReflect.Descriptor instead.
-func (*UrlByAliasRequest) Descriptor() ([]byte, []int) {
-	return file_tinee_proto_rawDescGZIP(), []int{2}
-}
-
-func (x *UrlByAliasRequest) GetAlias() string {
-	if x != nil {
-		return x.Alias
-	}
-	return ""
-}
-
-// Retrieving URL by alias response.
-type UrlByAliasResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	// URL alias corresponds to.
-	Url string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
-}
-
-func (x *UrlByAliasResponse) Reset() {
-	*x = UrlByAliasResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_tinee_proto_msgTypes[3]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
-}
-
-func (x *UrlByAliasResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*UrlByAliasResponse) ProtoMessage() {}
-
-func (x *UrlByAliasResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_tinee_proto_msgTypes[3]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVariantsRequest.ProtoReflect.Descriptor instead.
+func (*SetVariantsRequest) Descriptor() ([]byte, []int) {
+	return file_tinee_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SetVariantsRequest) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *SetVariantsRequest) GetVariants() []*Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+// Setting link variants response.
+type SetVariantsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *SetVariantsResponse) Reset() {
+	*x = SetVariantsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tinee_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetVariantsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVariantsResponse) ProtoMessage() {}
+
+func (x *SetVariantsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tinee_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVariantsResponse.ProtoReflect.Descriptor instead.
+func (*SetVariantsResponse) Descriptor() ([]byte, []int) {
+	return file_tinee_proto_rawDescGZIP(), []int{9}
+}
+
+// Retrieving link variants request.
+type VariantsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Alias of the link.
+	Alias string `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+}
+
+func (x *VariantsRequest) Reset() {
+	*x = VariantsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tinee_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VariantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VariantsRequest) ProtoMessage() {}
+
+func (x *VariantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tinee_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VariantsRequest.ProtoReflect.Descriptor instead.
+func (*VariantsRequest) Descriptor() ([]byte, []int) {
+	return file_tinee_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *VariantsRequest) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+// Retrieving link variants response.
+type VariantsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Variants of the link.
+	Variants []*Variant `protobuf:"bytes,1,rep,name=variants,proto3" json:"variants,omitempty"`
+}
+
+func (x *VariantsResponse) Reset() {
+	*x = VariantsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tinee_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VariantsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VariantsResponse) ProtoMessage() {}
+
+func (x *VariantsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tinee_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VariantsResponse.ProtoReflect.Descriptor instead.
+func (*VariantsResponse) Descriptor() ([]byte, []int) {
+	return file_tinee_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *VariantsResponse) GetVariants() []*Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+// QR code request, zero options are replaced by defaults.
+type QRCodeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Alias of shortened URL.
+	Alias string `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	// Image format, "png" (default) or "svg".
+	Format string `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	// Width and height of image in pixels, 256 by default.
+	Size uint32 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	// Error correction level, one of "L", "M" (default), "Q" or "H".
+	Level string `protobuf:"bytes,4,opt,name=level,proto3" json:"level,omitempty"`
+	// Color of dark modules in RRGGBB hex format, "000000" by default.
+	Foreground string `protobuf:"bytes,5,opt,name=foreground,proto3" json:"foreground,omitempty"`
+	// Color of light modules in RRGGBB hex format, "ffffff" by default.
+	Background string `protobuf:"bytes,6,opt,name=background,proto3" json:"background,omitempty"`
+	// Adds the standard 4 modules wide quiet zone around the code.
+	QuietZone bool `protobuf:"varint,7,opt,name=quiet_zone,json=quietZone,proto3" json:"quiet_zone,omitempty"`
+}
+
+func (x *QRCodeRequest) Reset() {
+	*x = QRCodeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tinee_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QRCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QRCodeRequest) ProtoMessage() {}
+
+func (x *QRCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tinee_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QRCodeRequest.ProtoReflect.Descriptor instead.
+func (*QRCodeRequest) Descriptor() ([]byte, []int) {
+	return file_tinee_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *QRCodeRequest) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *QRCodeRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *QRCodeRequest) GetSize() uint32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *QRCodeRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *QRCodeRequest) GetForeground() string {
+	if x != nil {
+		return x.Foreground
+	}
+	return ""
+}
+
+func (x *QRCodeRequest) GetBackground() string {
+	if x != nil {
+		return x.Background
+	}
+	return ""
+}
+
+func (x *QRCodeRequest) GetQuietZone() bool {
+	if x != nil {
+		return x.QuietZone
+	}
+	return false
+}
+
+// QR code response.
+type QRCodeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// MIME type of image.
+	ContentType string `protobuf:"bytes,1,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// Image data.
+	Image []byte `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+}
+
+func (x *QRCodeResponse) Reset() {
+	*x = QRCodeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tinee_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QRCodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QRCodeResponse) ProtoMessage() {}
+
+func (x *QRCodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tinee_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QRCodeResponse.ProtoReflect.Descriptor instead.
+func (*QRCodeResponse) Descriptor() ([]byte, []int) {
+	return file_tinee_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *QRCodeResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *QRCodeResponse) GetImage() []byte {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+// Link between URL and its aliases.
+type Link struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the link.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// URL of the link.
+	Url string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	// Aliases of the URL.
+	Aliases []string `protobuf:"bytes,3,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	// Time the link was created at.
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+}
+
+func (x *Link) Reset() {
+	*x = Link{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tinee_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Link) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Link) ProtoMessage() {}
+
+func (x *Link) ProtoReflect() protoreflect.Message {
+	mi := &file_tinee_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Link.ProtoReflect.Descriptor instead.
+func (*Link) Descriptor() ([]byte, []int) {
+	return file_tinee_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Link) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Link) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Link) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *Link) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+// Listing links request, empty filters match all links.
+type ListLinksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Filters links with URL host being the domain or its subdomain.
+	Domain string `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	// Filters links with any alias starting with the prefix.
+	AliasPrefix string `protobuf:"bytes,2,opt,name=alias_prefix,json=aliasPrefix,proto3" json:"alias_prefix,omitempty"`
+	// Filters links with URL containing the substring, ignoring case.
+	Search string `protobuf:"bytes,3,opt,name=search,proto3" json:"search,omitempty"`
+	// Order of links by creation time, "asc" (default) or "desc".
+	Order string `protobuf:"bytes,4,opt,name=order,proto3" json:"order,omitempty"`
+	// Max number of links in the page, 50 by default.
+	PageSize uint32 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Token of the page, next_page_token of the previous page or empty
+	// for the first page.
+	PageToken string `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+}
+
+func (x *ListLinksRequest) Reset() {
+	*x = ListLinksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tinee_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListLinksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLinksRequest) ProtoMessage() {}
+
+func (x *ListLinksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tinee_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLinksRequest.ProtoReflect.Descriptor instead.
+func (*ListLinksRequest) Descriptor() ([]byte, []int) {
+	return file_tinee_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListLinksRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *ListLinksRequest) GetAliasPrefix() string {
+	if x != nil {
+		return x.AliasPrefix
+	}
+	return ""
+}
+
+func (x *ListLinksRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *ListLinksRequest) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+func (x *ListLinksRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListLinksRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// Listing links response.
+type ListLinksResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Links of the page.
+	Links []*Link `protobuf:"bytes,1,rep,name=links,proto3" json:"links,omitempty"`
+	// Token of the next page, empty for the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+}
+
+func (x *ListLinksResponse) Reset() {
+	*x = ListLinksResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tinee_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListLinksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLinksResponse) ProtoMessage() {}
+
+func (x *ListLinksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tinee_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLinksResponse.ProtoReflect.Descriptor instead.
+func (*ListLinksResponse) Descriptor() ([]byte, []int) {
+	return file_tinee_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListLinksResponse) GetLinks() []*Link {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+func (x *ListLinksResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+var File_tinee_proto protoreflect.FileDescriptor
+
+var file_tinee_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x74,
+	0x69, 0x6e, 0x65, 0x65, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69,
+	0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x22, 0x38, 0x0a, 0x0e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x22, 0x2e, 0x0a,
+	0x0f, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x1b, 0x0a, 0x09, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x55, 0x72, 0x6c, 0x22, 0x4a, 0x0a,
+	0x15, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x5f,
+	0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x69, 0x6e, 0x65, 0x65,
+	0x55, 0x72, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x29, 0x0a, 0x11, 0x55, 0x72, 0x6c,
+	0x42, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61,
+	0x6c, 0x69, 0x61, 0x73, 0x22, 0x26, 0x0a, 0x12, 0x55, 0x72, 0x6c, 0x42, 0x79, 0x41, 0x6c, 0x69,
+	0x61, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72,
+	0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x22, 0x32, 0x0a, 0x16,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x72, 0x6c, 0x42, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73,
+	0x22, 0x90, 0x01, 0x0a, 0x17, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x72, 0x6c, 0x42, 0x79, 0x41,
+	0x6c, 0x69, 0x61, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x04,
+	0x75, 0x72, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x74, 0x69, 0x6e,
+	0x65, 0x65, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x72, 0x6c, 0x42, 0x79, 0x41, 0x6c, 0x69,
+	0x61, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x55, 0x72, 0x6c, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x04, 0x75, 0x72, 0x6c, 0x73, 0x1a, 0x37, 0x0a, 0x09, 0x55, 0x72,
+	0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0x5f, 0x0a, 0x07, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x75, 0x72, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x63, 0x6c,
+	0x69, 0x63, 0x6b, 0x73, 0x22, 0x56, 0x0a, 0x12, 0x53, 0x65, 0x74, 0x56, 0x61, 0x72, 0x69, 0x61,
+	0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c,
+	0x69, 0x61, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73,
+	0x12, 0x2a, 0x0a, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x2e, 0x56, 0x61, 0x72, 0x69, 0x61,
+	0x6e, 0x74, 0x52, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x22, 0x15, 0x0a, 0x13,
+	0x53, 0x65, 0x74, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x27, 0x0a, 0x0f, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x22, 0x3e, 0x0a, 0x10,
+	0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x2a, 0x0a, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x2e, 0x56, 0x61, 0x72, 0x69, 0x61,
+	0x6e, 0x74, 0x52, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x22, 0xc6, 0x01, 0x0a,
+	0x0d, 0x51, 0x52, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61,
+	0x6c, 0x69, 0x61, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x1e, 0x0a, 0x0a, 0x66, 0x6f, 0x72, 0x65, 0x67, 0x72,
+	0x6f, 0x75, 0x6e, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x6f, 0x72, 0x65,
+	0x67, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x67, 0x72,
+	0x6f, 0x75, 0x6e, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x61, 0x63, 0x6b,
+	0x67, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x71, 0x75, 0x69, 0x65, 0x74, 0x5f,
+	0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x71, 0x75, 0x69, 0x65,
+	0x74, 0x5a, 0x6f, 0x6e, 0x65, 0x22, 0x49, 0x0a, 0x0e, 0x51, 0x52, 0x43, 0x6f, 0x64, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d,
+	0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65,
+	0x22, 0x7f, 0x0a, 0x04, 0x4c, 0x69, 0x6e, 0x6b, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x6c,
+	0x69, 0x61, 0x73, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x61, 0x6c, 0x69,
+	0x61, 0x73, 0x65, 0x73, 0x12, 0x3b, 0x0a, 0x0b, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d,
+	0x65, 0x22, 0xb7, 0x01, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x69, 0x6e, 0x6b, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x12, 0x21,
+	0x0a, 0x0c, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x50, 0x72, 0x65, 0x66, 0x69,
+	0x78, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x72, 0x64,
+	0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x12,
+	0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a,
+	0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x5e, 0x0a, 0x11, 0x4c,
+	0x69, 0x73, 0x74, 0x4c, 0x69, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x21, 0x0a, 0x05, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0b, 0x2e, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x2e, 0x4c, 0x69, 0x6e, 0x6b, 0x52, 0x05, 0x6c, 0x69,
+	0x6e, 0x6b, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65,
+	0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65,
+	0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x32, 0xfc, 0x05, 0x0a, 0x08,
+	0x54, 0x69, 0x6e, 0x65, 0x65, 0x55, 0x52, 0x4c, 0x12, 0x4e, 0x0a, 0x07, 0x53, 0x68, 0x6f, 0x72,
+	0x74, 0x65, 0x6e, 0x12, 0x15, 0x2e, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x2e, 0x53, 0x68, 0x6f, 0x72,
+	0x74, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x74, 0x69, 0x6e,
+	0x65, 0x65, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x14, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0e, 0x22, 0x09, 0x2f, 0x76, 0x31, 0x2f,
+	0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x3a, 0x01, 0x2a, 0x12, 0x65, 0x0a, 0x0d, 0x53, 0x68, 0x6f, 0x72,
+	0x74, 0x65, 0x6e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x15, 0x2e, 0x74, 0x69, 0x6e, 0x65,
+	0x65, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1c, 0x2e, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1b,
+	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x15, 0x22, 0x10, 0x2f, 0x76, 0x31, 0x2f, 0x6c, 0x69, 0x6e, 0x6b,
+	0x73, 0x2f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x3a, 0x01, 0x2a, 0x28, 0x01, 0x30, 0x01, 0x12,
+	0x5b, 0x0a, 0x0a, 0x55, 0x72, 0x6c, 0x42, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x18, 0x2e,
+	0x74, 0x69, 0x6e, 0x65, 0x65, 0x2e, 0x55, 0x72, 0x6c, 0x42, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x2e,
+	0x55, 0x72, 0x6c, 0x42, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x18, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x12, 0x12, 0x10, 0x2f, 0x76, 0x31, 0x2f,
+	0x75, 0x72, 0x6c, 0x73, 0x2f, 0x7b, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x7d, 0x12, 0x62, 0x0a, 0x0f,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x72, 0x6c, 0x42, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12,
+	0x1d, 0x2e, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x72, 0x6c,
+	0x42, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e,
+	0x2e, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x72, 0x6c, 0x42,
+	0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x10,
+	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0a, 0x12, 0x08, 0x2f, 0x76, 0x31, 0x2f, 0x75, 0x72, 0x6c, 0x73,
+	0x12, 0x6b, 0x0a, 0x0b, 0x53, 0x65, 0x74, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x12,
+	0x19, 0x2e, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x2e, 0x53, 0x65, 0x74, 0x56, 0x61, 0x72, 0x69, 0x61,
+	0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x74, 0x69, 0x6e,
+	0x65, 0x65, 0x2e, 0x53, 0x65, 0x74, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x25, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1f, 0x1a, 0x1a,
+	0x2f, 0x76, 0x31, 0x2f, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x2f, 0x7b, 0x61, 0x6c, 0x69, 0x61, 0x73,
+	0x7d, 0x2f, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x3a, 0x01, 0x2a, 0x12, 0x5f, 0x0a,
+	0x08, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x16, 0x2e, 0x74, 0x69, 0x6e, 0x65,
+	0x65, 0x2e, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x17, 0x2e, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x2e, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e,
+	0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x22, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x1c, 0x12, 0x1a, 0x2f, 0x76, 0x31, 0x2f, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x2f, 0x7b, 0x61,
+	0x6c, 0x69, 0x61, 0x73, 0x7d, 0x2f, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x57,
+	0x0a, 0x06, 0x51, 0x52, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x2e, 0x74, 0x69, 0x6e, 0x65, 0x65,
+	0x2e, 0x51, 0x52, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15,
+	0x2e, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x2e, 0x51, 0x52, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x20, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1a, 0x12, 0x18, 0x2f,
+	0x76, 0x31, 0x2f, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x2f, 0x7b, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x7d,
+	0x2f, 0x71, 0x72, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x51, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x4c,
+	0x69, 0x6e, 0x6b, 0x73, 0x12, 0x17, 0x2e, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x4c, 0x69, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e,
+	0x74, 0x69, 0x6e, 0x65, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x69, 0x6e, 0x6b, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x11, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0b, 0x12,
+	0x09, 0x2f, 0x76, 0x31, 0x2f, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x42, 0x0e, 0x5a, 0x0c, 0x74, 0x69,
+	0x6e, 0x65, 0x65, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_tinee_proto_rawDescOnce sync.Once
+	file_tinee_proto_rawDescData = file_tinee_proto_rawDesc
+)
+
+func file_tinee_proto_rawDescGZIP() []byte {
+	file_tinee_proto_rawDescOnce.Do(func() {
+		file_tinee_proto_rawDescData = protoimpl.X.CompressGZIP(file_tinee_proto_rawDescData)
+	})
+	return file_tinee_proto_rawDescData
+}
+
+var file_tinee_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_tinee_proto_goTypes = []interface{}{
+	(*ShortenRequest)(nil),          // 0: tinee.ShortenRequest
+	(*ShortenResponse)(nil),         // 1: tinee.ShortenResponse
+	(*ShortenStreamResponse)(nil),   // 2: tinee.ShortenStreamResponse
+	(*UrlByAliasRequest)(nil),       // 3: tinee.UrlByAliasRequest
+	(*UrlByAliasResponse)(nil),      // 4: tinee.UrlByAliasResponse
+	(*BatchUrlByAliasRequest)(nil),  // 5: tinee.BatchUrlByAliasRequest
+	(*BatchUrlByAliasResponse)(nil), // 6: tinee.BatchUrlByAliasResponse
+	(*Variant)(nil),                 // 7: tinee.Variant
+	(*SetVariantsRequest)(nil),      // 8: tinee.SetVariantsRequest
+	(*SetVariantsResponse)(nil),     // 9: tinee.SetVariantsResponse
+	(*VariantsRequest)(nil),         // 10: tinee.VariantsRequest
+	(*VariantsResponse)(nil),        // 11: tinee.VariantsResponse
+	(*QRCodeRequest)(nil),           // 12: tinee.QRCodeRequest
+	(*QRCodeResponse)(nil),          // 13: tinee.QRCodeResponse
+	(*Link)(nil),                    // 14: tinee.Link
+	(*ListLinksRequest)(nil),        // 15: tinee.ListLinksRequest
+	(*ListLinksResponse)(nil),       // 16: tinee.ListLinksResponse
+	nil,                             // 17: tinee.BatchUrlByAliasResponse.UrlsEntry
+	(*timestamppb.Timestamp)(nil),   // 18: google.protobuf.Timestamp
+}
+var file_tinee_proto_depIdxs = []int32{
+	17, // 0: tinee.BatchUrlByAliasResponse.urls:type_name -> tinee.BatchUrlByAliasResponse.UrlsEntry
+	7,  // 1: tinee.SetVariantsRequest.variants:type_name -> tinee.Variant
+	7,  // 2: tinee.VariantsResponse.variants:type_name -> tinee.Variant
+	18, // 3: tinee.Link.create_time:type_name -> google.protobuf.Timestamp
+	14, // 4: tinee.ListLinksResponse.links:type_name -> tinee.Link
+	0,  // 5: tinee.TineeURL.Shorten:input_type -> tinee.ShortenRequest
+	0,  // 6: tinee.TineeURL.ShortenStream:input_type -> tinee.ShortenRequest
+	3,  // 7: tinee.TineeURL.UrlByAlias:input_type -> tinee.UrlByAliasRequest
+	5,  // 8: tinee.TineeURL.BatchUrlByAlias:input_type -> tinee.BatchUrlByAliasRequest
+	8,  // 9: tinee.TineeURL.SetVariants:input_type -> tinee.SetVariantsRequest
+	10, // 10: tinee.TineeURL.Variants:input_type -> tinee.VariantsRequest
+	12, // 11: tinee.TineeURL.QRCode:input_type -> tinee.QRCodeRequest
+	15, // 12: tinee.TineeURL.ListLinks:input_type -> tinee.ListLinksRequest
+	1,  // 13: tinee.TineeURL.Shorten:output_type -> tinee.ShortenResponse
+	2,  // 14: tinee.TineeURL.ShortenStream:output_type -> tinee.ShortenStreamResponse
+	4,  // 15: tinee.TineeURL.UrlByAlias:output_type -> tinee.UrlByAliasResponse
+	6,  // 16: tinee.TineeURL.BatchUrlByAlias:output_type -> tinee.BatchUrlByAliasResponse
+	9,  // 17: tinee.TineeURL.SetVariants:output_type -> tinee.SetVariantsResponse
+	11, // 18: tinee.TineeURL.Variants:output_type -> tinee.VariantsResponse
+	13, // 19: tinee.TineeURL.QRCode:output_type -> tinee.QRCodeResponse
+	16, // 20: tinee.TineeURL.ListLinks:output_type -> tinee.ListLinksResponse
+	13, // [13:21] is the sub-list for method output_type
+	5,  // [5:13] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_tinee_proto_init() }
+func file_tinee_proto_init() {
+	if File_tinee_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_tinee_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortenRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tinee_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortenResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tinee_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortenStreamResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tinee_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UrlByAliasRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tinee_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UrlByAliasResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tinee_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchUrlByAliasRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tinee_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchUrlByAliasResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tinee_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Variant); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tinee_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetVariantsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tinee_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetVariantsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tinee_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VariantsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tinee_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VariantsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tinee_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QRCodeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
 		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use UrlByAliasResponse.ProtoReflect.Descriptor instead.
-func (*UrlByAliasResponse) Descriptor() ([]byte, []int) {
-	return file_tinee_proto_rawDescGZIP(), []int{3}
-}
-
-func (x *UrlByAliasResponse) GetUrl() string {
-	if x != nil {
-		return x.Url
-	}
-	return ""
-}
-
-var File_tinee_proto protoreflect.FileDescriptor
-
-var file_tinee_proto_rawDesc = []byte{
-	0x0a, 0x0b, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x74,
-	0x69, 0x6e, 0x65, 0x65, 0x22, 0x38, 0x0a, 0x0e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x69, 0x61,
-	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x22, 0x2e,
-	0x0a, 0x0f, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x55, 0x72, 0x6c, 0x22, 0x29,
-	0x0a, 0x11, 0x55, 0x72, 0x6c, 0x42, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x22, 0x26, 0x0a, 0x12, 0x55, 0x72, 0x6c,
-	0x42, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72,
-	0x6c, 0x32, 0x87, 0x01, 0x0a, 0x08, 0x54, 0x69, 0x6e, 0x65, 0x65, 0x55, 0x52, 0x4c, 0x12, 0x38,
-	0x0a, 0x07, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x12, 0x15, 0x2e, 0x74, 0x69, 0x6e, 0x65,
-	0x65, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x16, 0x2e, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a, 0x0a, 0x55, 0x72, 0x6c, 0x42,
-	0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x18, 0x2e, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x2e, 0x55,
-	0x72, 0x6c, 0x42, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x19, 0x2e, 0x74, 0x69, 0x6e, 0x65, 0x65, 0x2e, 0x55, 0x72, 0x6c, 0x42, 0x79, 0x41, 0x6c,
-	0x69, 0x61, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x0e, 0x5a, 0x0c, 0x74,
-	0x69, 0x6e, 0x65, 0x65, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
-}
-
-var (
-	file_tinee_proto_rawDescOnce sync.Once
-	file_tinee_proto_rawDescData = file_tinee_proto_rawDesc
-)
-
-func file_tinee_proto_rawDescGZIP() []byte {
-	file_tinee_proto_rawDescOnce.Do(func() {
-		file_tinee_proto_rawDescData = protoimpl.X.CompressGZIP(file_tinee_proto_rawDescData)
-	})
-	return file_tinee_proto_rawDescData
-}
-
-var file_tinee_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
-var file_tinee_proto_goTypes = []interface{}{
-	(*ShortenRequest)(nil),     // 0: tinee.ShortenRequest
-	(*ShortenResponse)(nil),    // 1: tinee.ShortenResponse
-	(*UrlByAliasRequest)(nil),  // 2: tinee.UrlByAliasRequest
-	(*UrlByAliasResponse)(nil), // 3: tinee.UrlByAliasResponse
-}
-var file_tinee_proto_depIdxs = []int32{
-	0, // 0: tinee.TineeURL.Shorten:input_type -> tinee.ShortenRequest
-	2, // 1: tinee.TineeURL.UrlByAlias:input_type -> tinee.UrlByAliasRequest
-	1, // 2: tinee.TineeURL.Shorten:output_type -> tinee.ShortenResponse
-	3, // 3: tinee.TineeURL.UrlByAlias:output_type -> tinee.UrlByAliasResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
-}
-
-func init() { file_tinee_proto_init() }
-func file_tinee_proto_init() {
-	if File_tinee_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_tinee_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ShortenRequest); i {
+		file_tinee_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QRCodeResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -307,8 +1437,8 @@ func file_tinee_proto_init() {
 				return nil
 			}
 		}
-		file_tinee_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ShortenResponse); i {
+		file_tinee_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Link); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -319,8 +1449,8 @@ func file_tinee_proto_init() {
 				return nil
 			}
 		}
-		file_tinee_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UrlByAliasRequest); i {
+		file_tinee_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListLinksRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -331,8 +1461,8 @@ func file_tinee_proto_init() {
 				return nil
 			}
 		}
-		file_tinee_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UrlByAliasResponse); i {
+		file_tinee_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListLinksResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -350,7 +1480,7 @@ func file_tinee_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_tinee_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -378,8 +1508,20 @@ const _ = grpc.SupportPackageIsVersion6
 type TineeURLClient interface {
 	// Shortens URL.
 	Shorten(ctx context.Context, in *ShortenRequest, opts ...grpc.CallOption) (*ShortenResponse, error)
+	// Shortens stream of URLs, responses are sent in the order of requests.
+	ShortenStream(ctx context.Context, opts ...grpc.CallOption) (TineeURL_ShortenStreamClient, error)
 	// Returns URL that corresponds to alias from request.
 	UrlByAlias(ctx context.Context, in *UrlByAliasRequest, opts ...grpc.CallOption) (*UrlByAliasResponse, error)
+	// Returns URLs that correspond to aliases from request.
+	BatchUrlByAlias(ctx context.Context, in *BatchUrlByAliasRequest, opts ...grpc.CallOption) (*BatchUrlByAliasResponse, error)
+	// Sets weighted destinations of the link with alias from request.
+	SetVariants(ctx context.Context, in *SetVariantsRequest, opts ...grpc.CallOption) (*SetVariantsResponse, error)
+	// Returns weighted destinations of the link with alias from request.
+	Variants(ctx context.Context, in *VariantsRequest, opts ...grpc.CallOption) (*VariantsResponse, error)
+	// Returns QR code of shortened URL with alias from request.
+	QRCode(ctx context.Context, in *QRCodeRequest, opts ...grpc.CallOption) (*QRCodeResponse, error)
+	// Lists links matching request filters page by page.
+	ListLinks(ctx context.Context, in *ListLinksRequest, opts ...grpc.CallOption) (*ListLinksResponse, error)
 }
 
 type tineeURLClient struct {
@@ -399,6 +1541,37 @@ func (c *tineeURLClient) Shorten(ctx context.Context, in *ShortenRequest, opts .
 	return out, nil
 }
 
+func (c *tineeURLClient) ShortenStream(ctx context.Context, opts ...grpc.CallOption) (TineeURL_ShortenStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TineeURL_serviceDesc.Streams[0], "/tinee.TineeURL/ShortenStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tineeURLShortenStreamClient{stream}
+	return x, nil
+}
+
+type TineeURL_ShortenStreamClient interface {
+	Send(*ShortenRequest) error
+	Recv() (*ShortenStreamResponse, error)
+	grpc.ClientStream
+}
+
+type tineeURLShortenStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *tineeURLShortenStreamClient) Send(m *ShortenRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *tineeURLShortenStreamClient) Recv() (*ShortenStreamResponse, error) {
+	m := new(ShortenStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *tineeURLClient) UrlByAlias(ctx context.Context, in *UrlByAliasRequest, opts ...grpc.CallOption) (*UrlByAliasResponse, error) {
 	out := new(UrlByAliasResponse)
 	err := c.cc.Invoke(ctx, "/tinee.TineeURL/UrlByAlias", in, out, opts...)
@@ -408,12 +1581,69 @@ func (c *tineeURLClient) UrlByAlias(ctx context.Context, in *UrlByAliasRequest,
 	return out, nil
 }
 
+func (c *tineeURLClient) BatchUrlByAlias(ctx context.Context, in *BatchUrlByAliasRequest, opts ...grpc.CallOption) (*BatchUrlByAliasResponse, error) {
+	out := new(BatchUrlByAliasResponse)
+	err := c.cc.Invoke(ctx, "/tinee.TineeURL/BatchUrlByAlias", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tineeURLClient) SetVariants(ctx context.Context, in *SetVariantsRequest, opts ...grpc.CallOption) (*SetVariantsResponse, error) {
+	out := new(SetVariantsResponse)
+	err := c.cc.Invoke(ctx, "/tinee.TineeURL/SetVariants", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tineeURLClient) Variants(ctx context.Context, in *VariantsRequest, opts ...grpc.CallOption) (*VariantsResponse, error) {
+	out := new(VariantsResponse)
+	err := c.cc.Invoke(ctx, "/tinee.TineeURL/Variants", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tineeURLClient) QRCode(ctx context.Context, in *QRCodeRequest, opts ...grpc.CallOption) (*QRCodeResponse, error) {
+	out := new(QRCodeResponse)
+	err := c.cc.Invoke(ctx, "/tinee.TineeURL/QRCode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tineeURLClient) ListLinks(ctx context.Context, in *ListLinksRequest, opts ...grpc.CallOption) (*ListLinksResponse, error) {
+	out := new(ListLinksResponse)
+	err := c.cc.Invoke(ctx, "/tinee.TineeURL/ListLinks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TineeURLServer is the server API for TineeURL service.
 type TineeURLServer interface {
 	// Shortens URL.
 	Shorten(context.Context, *ShortenRequest) (*ShortenResponse, error)
+	// Shortens stream of URLs, responses are sent in the order of requests.
+	ShortenStream(TineeURL_ShortenStreamServer) error
 	// Returns URL that corresponds to alias from request.
 	UrlByAlias(context.Context, *UrlByAliasRequest) (*UrlByAliasResponse, error)
+	// Returns URLs that correspond to aliases from request.
+	BatchUrlByAlias(context.Context, *BatchUrlByAliasRequest) (*BatchUrlByAliasResponse, error)
+	// Sets weighted destinations of the link with alias from request.
+	SetVariants(context.Context, *SetVariantsRequest) (*SetVariantsResponse, error)
+	// Returns weighted destinations of the link with alias from request.
+	Variants(context.Context, *VariantsRequest) (*VariantsResponse, error)
+	// Returns QR code of shortened URL with alias from request.
+	QRCode(context.Context, *QRCodeRequest) (*QRCodeResponse, error)
+	// Lists links matching request filters page by page.
+	ListLinks(context.Context, *ListLinksRequest) (*ListLinksResponse, error)
 }
 
 // UnimplementedTineeURLServer can be embedded to have forward compatible implementations.
@@ -423,9 +1653,27 @@ type UnimplementedTineeURLServer struct {
 func (*UnimplementedTineeURLServer) Shorten(context.Context, *ShortenRequest) (*ShortenResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Shorten not implemented")
 }
+func (*UnimplementedTineeURLServer) ShortenStream(TineeURL_ShortenStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ShortenStream not implemented")
+}
 func (*UnimplementedTineeURLServer) UrlByAlias(context.Context, *UrlByAliasRequest) (*UrlByAliasResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UrlByAlias not implemented")
 }
+func (*UnimplementedTineeURLServer) BatchUrlByAlias(context.Context, *BatchUrlByAliasRequest) (*BatchUrlByAliasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchUrlByAlias not implemented")
+}
+func (*UnimplementedTineeURLServer) SetVariants(context.Context, *SetVariantsRequest) (*SetVariantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVariants not implemented")
+}
+func (*UnimplementedTineeURLServer) Variants(context.Context, *VariantsRequest) (*VariantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Variants not implemented")
+}
+func (*UnimplementedTineeURLServer) QRCode(context.Context, *QRCodeRequest) (*QRCodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QRCode not implemented")
+}
+func (*UnimplementedTineeURLServer) ListLinks(context.Context, *ListLinksRequest) (*ListLinksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLinks not implemented")
+}
 
 func RegisterTineeURLServer(s *grpc.Server, srv TineeURLServer) {
 	s.RegisterService(&_TineeURL_serviceDesc, srv)
@@ -449,6 +1697,32 @@ func _TineeURL_Shorten_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TineeURL_ShortenStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TineeURLServer).ShortenStream(&tineeURLShortenStreamServer{stream})
+}
+
+type TineeURL_ShortenStreamServer interface {
+	Send(*ShortenStreamResponse) error
+	Recv() (*ShortenRequest, error)
+	grpc.ServerStream
+}
+
+type tineeURLShortenStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *tineeURLShortenStreamServer) Send(m *ShortenStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *tineeURLShortenStreamServer) Recv() (*ShortenRequest, error) {
+	m := new(ShortenRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _TineeURL_UrlByAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UrlByAliasRequest)
 	if err := dec(in); err != nil {
@@ -467,6 +1741,96 @@ func _TineeURL_UrlByAlias_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TineeURL_BatchUrlByAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchUrlByAliasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TineeURLServer).BatchUrlByAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tinee.TineeURL/BatchUrlByAlias",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TineeURLServer).BatchUrlByAlias(ctx, req.(*BatchUrlByAliasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TineeURL_SetVariants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVariantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TineeURLServer).SetVariants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tinee.TineeURL/SetVariants",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TineeURLServer).SetVariants(ctx, req.(*SetVariantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TineeURL_Variants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VariantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TineeURLServer).Variants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tinee.TineeURL/Variants",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TineeURLServer).Variants(ctx, req.(*VariantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TineeURL_QRCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QRCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TineeURLServer).QRCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tinee.TineeURL/QRCode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TineeURLServer).QRCode(ctx, req.(*QRCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TineeURL_ListLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TineeURLServer).ListLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tinee.TineeURL/ListLinks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TineeURLServer).ListLinks(ctx, req.(*ListLinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _TineeURL_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "tinee.TineeURL",
 	HandlerType: (*TineeURLServer)(nil),
@@ -479,7 +1843,34 @@ var _TineeURL_serviceDesc = grpc.ServiceDesc{
 			MethodName: "UrlByAlias",
 			Handler:    _TineeURL_UrlByAlias_Handler,
 		},
+		{
+			MethodName: "BatchUrlByAlias",
+			Handler:    _TineeURL_BatchUrlByAlias_Handler,
+		},
+		{
+			MethodName: "SetVariants",
+			Handler:    _TineeURL_SetVariants_Handler,
+		},
+		{
+			MethodName: "Variants",
+			Handler:    _TineeURL_Variants_Handler,
+		},
+		{
+			MethodName: "QRCode",
+			Handler:    _TineeURL_QRCode_Handler,
+		},
+		{
+			MethodName: "ListLinks",
+			Handler:    _TineeURL_ListLinks_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ShortenStream",
+			Handler:       _TineeURL_ShortenStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "tinee.proto",
 }
@@ -0,0 +1,241 @@
+// Package tineetest provides in-process tinee server for tests of its
+// clients.
+package tineetest
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	stdgrpc "google.golang.org/grpc"
+
+	"tinee/internal/config"
+	"tinee/internal/grpc"
+	tineehttp "tinee/internal/http"
+	"tinee/internal/memory"
+	"tinee/internal/service"
+	"tinee/pkg/pb"
+)
+
+// Config is configuration of Server.
+type Config struct {
+	// Domain is domain of shortened URLs, "tinee.test" if empty.
+	Domain string
+	// AdminToken is bearer token of admin HTTP endpoints and gRPC
+	// methods, they are disabled if it is empty.
+	AdminToken string
+}
+
+// Link is a link stored by Server.
+type Link struct {
+	ID        string
+	URL       string
+	Aliases   []string
+	CreatedAt time.Time
+}
+
+const (
+	// ProtocolGRPC is protocol of gRPC calls.
+	ProtocolGRPC = "grpc"
+	// ProtocolHTTP is protocol of HTTP calls.
+	ProtocolHTTP = "http"
+)
+
+// Call is a call of Server API.
+type Call struct {
+	Protocol string
+	// Method is full gRPC method, such as /tinee.TineeURL/Shorten, or HTTP
+	// method and path, such as POST /api/v1/shorten.
+	Method string
+}
+
+// Server is in-process tinee server with gRPC and HTTP APIs backed by
+// in-memory storage.
+type Server struct {
+	// GRPCAddr is address of gRPC API, such as 127.0.0.1:1234.
+	GRPCAddr string
+	// HTTPURL is base URL of HTTP API, such as http://127.0.0.1:1234.
+	HTTPURL string
+	// Domain is domain of shortened URLs.
+	Domain string
+
+	tb    testing.TB
+	repo  *memory.LinkRepo
+	cache *memory.LinkCache
+	grpc  *stdgrpc.Server
+	http  *httptest.Server
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewServer starts and returns a new Server listening on random ports
+// of the loopback interface, it is closed when the test finishes.
+func NewServer(tb testing.TB, cfg Config) *Server {
+	tb.Helper()
+	if cfg.Domain == "" {
+		cfg.Domain = "tinee.test"
+	}
+
+	s := &Server{
+		Domain: cfg.Domain,
+		tb:     tb,
+		repo:   memory.NewLinkRepo(),
+		cache:  memory.NewLinkCache(),
+	}
+	svc := service.New(
+		config.Service{Domain: cfg.Domain, BatchWorkers: 8, MaxBatchSize: 1000},
+		s.repo, s.cache, memory.NewVariantCounter(),
+	)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatalf("tineetest: listening: %v", err)
+	}
+	s.GRPCAddr = l.Addr().String()
+	s.grpc = stdgrpc.NewServer(
+		stdgrpc.UnaryInterceptor(s.recordUnary),
+		stdgrpc.StreamInterceptor(s.recordStream),
+	)
+	pb.RegisterTineeURLServer(s.grpc, grpc.NewHandler(config.GRPCServer{AdminToken: cfg.AdminToken}, svc))
+	go func() {
+		_ = s.grpc.Serve(l)
+	}()
+
+	h := tineehttp.NewHandler(config.HTTPServer{AdminToken: cfg.AdminToken}, svc, nil)
+	s.http = httptest.NewServer(s.recordHTTP(h))
+	s.HTTPURL = s.http.URL
+
+	tb.Cleanup(s.Close)
+
+	return s
+}
+
+// Close stops the Server.
+func (s *Server) Close() {
+	s.grpc.Stop()
+	s.http.Close()
+}
+
+// Seed stores a link with URL and aliases and returns it. The test fails
+// if any of aliases is taken.
+func (s *Server) Seed(URL string, aliases ...string) Link {
+	s.tb.Helper()
+	l := service.NewLink(URL)
+	if len(aliases) > 0 {
+		l.Aliases = aliases
+	}
+
+	ctx := context.Background()
+	if err := s.repo.Save(ctx, l); err != nil {
+		s.tb.Fatalf("tineetest: seeding %s: %v", URL, err)
+	}
+	// cached links of the aliases could have changed
+	_ = s.cache.Delete(ctx, l.Aliases)
+
+	return newLink(l)
+}
+
+// Links returns all stored links in the order they were created.
+func (s *Server) Links() []Link {
+	var links []Link
+	for _, l := range s.repo.All() {
+		links = append(links, newLink(l))
+	}
+
+	return links
+}
+
+// Link returns stored link with alias.
+func (s *Server) Link(alias string) (Link, bool) {
+	l, err := s.repo.FindByAlias(context.Background(), alias)
+	if err != nil {
+		return Link{}, false
+	}
+
+	return newLink(l), true
+}
+
+// TineeURL returns shortened URL with alias.
+func (s *Server) TineeURL(alias string) string {
+	return s.Domain + "/" + alias
+}
+
+// Calls returns calls of the Server APIs in the order they were made.
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Call(nil), s.calls...)
+}
+
+// CallCount returns number of calls of the method.
+func (s *Server) CallCount(method string) int {
+	n := 0
+	for _, c := range s.Calls() {
+		if c.Method == method {
+			n++
+		}
+	}
+
+	return n
+}
+
+// AssertCalled fails the test if the method was not called n times.
+func (s *Server) AssertCalled(tb testing.TB, method string, n int) {
+	tb.Helper()
+	if got := s.CallCount(method); got != n {
+		tb.Errorf("tineetest: %s called %d times, expected %d", method, got, n)
+	}
+}
+
+// Reset removes all stored links and recorded calls.
+func (s *Server) Reset() {
+	for _, l := range s.repo.All() {
+		_ = s.cache.Delete(context.Background(), l.Aliases)
+	}
+	s.repo.Clear()
+
+	s.mu.Lock()
+	s.calls = nil
+	s.mu.Unlock()
+}
+
+// record records the call.
+func (s *Server) record(c Call) {
+	s.mu.Lock()
+	s.calls = append(s.calls, c)
+	s.mu.Unlock()
+}
+
+// recordUnary is gRPC interceptor recording unary calls.
+func (s *Server) recordUnary(ctx context.Context, req interface{}, info *stdgrpc.UnaryServerInfo, handler stdgrpc.UnaryHandler) (interface{}, error) {
+	s.record(Call{Protocol: ProtocolGRPC, Method: info.FullMethod})
+
+	return handler(ctx, req)
+}
+
+// recordStream is gRPC interceptor recording streaming calls.
+func (s *Server) recordStream(srv interface{}, ss stdgrpc.ServerStream, info *stdgrpc.StreamServerInfo, handler stdgrpc.StreamHandler) error {
+	s.record(Call{Protocol: ProtocolGRPC, Method: info.FullMethod})
+
+	return handler(srv, ss)
+}
+
+// recordHTTP is middleware recording HTTP calls.
+func (s *Server) recordHTTP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.record(Call{Protocol: ProtocolHTTP, Method: r.Method + " " + r.URL.Path})
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// newLink returns Link of service.Link.
+func newLink(l service.Link) Link {
+	return Link{ID: l.ID, URL: l.URL, Aliases: l.Aliases, CreatedAt: l.CreatedAt}
+}
@@ -0,0 +1,194 @@
+package tineetest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"tinee/pkg/client"
+	"tinee/pkg/pb"
+)
+
+func TestServer(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	s := NewServer(t, Config{})
+
+	seeded := s.Seed("https://x.xx", "xxxx")
+	is.Equal([]Link{seeded}, s.Links())
+
+	gc, err := client.Dial(ctx, client.Options{}, s.GRPCAddr, grpc.WithInsecure())
+	is.NoErr(err)
+	defer gc.Close()
+	hc := client.NewHTTP(client.Options{}, s.HTTPURL, nil)
+
+	URL, err := gc.Resolve(ctx, "xxxx")
+	is.NoErr(err)
+	is.Equal("https://x.xx", URL)
+
+	tineeURL, err := hc.Shorten(ctx, "https://y.yy", "yyyy")
+	is.NoErr(err)
+	is.Equal(s.TineeURL("yyyy"), tineeURL)
+	l, ok := s.Link("yyyy")
+	is.True(ok)
+	is.Equal("https://y.yy", l.URL)
+
+	_, err = gc.Resolve(ctx, "none")
+	is.Equal(client.ErrNotFound, err)
+
+	s.AssertCalled(t, "/tinee.TineeURL/UrlByAlias", 2)
+	s.AssertCalled(t, "POST /api/v1/shorten", 1)
+	is.Equal([]Call{
+		{Protocol: ProtocolGRPC, Method: "/tinee.TineeURL/UrlByAlias"},
+		{Protocol: ProtocolHTTP, Method: "POST /api/v1/shorten"},
+		{Protocol: ProtocolGRPC, Method: "/tinee.TineeURL/UrlByAlias"},
+	}, s.Calls())
+
+	s.Reset()
+	is.Equal(0, len(s.Links()))
+	is.Equal(0, len(s.Calls()))
+	_, err = gc.Resolve(ctx, "xxxx")
+	is.Equal(client.ErrNotFound, err) // cached link is removed too
+}
+
+// fatalTB is testing.TB recording fatal errors instead of failing test.
+type fatalTB struct {
+	testing.TB
+	fatal string
+}
+
+func (tb *fatalTB) Fatalf(format string, args ...interface{}) {
+	tb.fatal = fmt.Sprintf(format, args...)
+}
+
+func TestServer_SeedTakenAlias(t *testing.T) {
+	is := is.New(t)
+	tb := &fatalTB{TB: t}
+	s := NewServer(tb, Config{})
+	s.Seed("https://x.xx", "xxxx")
+
+	s.Seed("https://y.yy", "yyyy", "xxxx")
+
+	is.Equal("tineetest: seeding https://y.yy: invalid alias", tb.fatal)
+	is.Equal(1, len(s.Links())) // link with taken alias is not stored
+}
+
+func TestServer_Redirect(t *testing.T) {
+	is := is.New(t)
+	s := NewServer(t, Config{Domain: "sho.rt"})
+	s.Seed("https://x.xx", "xxxx")
+
+	hc := &http.Client{CheckRedirect: func(r *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := hc.Get(s.HTTPURL + "/xxxx")
+	is.NoErr(err)
+	defer resp.Body.Close()
+
+	is.Equal(http.StatusSeeOther, resp.StatusCode)
+	is.Equal("https://x.xx", resp.Header.Get("Location"))
+	is.True(strings.HasPrefix(s.TineeURL("xxxx"), "sho.rt/"))
+}
+
+func TestServer_ListLinks(t *testing.T) {
+	is := is.New(t)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer token")
+	s := NewServer(t, Config{AdminToken: "token"})
+	s.Seed("https://docs.example.com/x", "xxxx")
+	s.Seed("https://example.com/y", "yyyy")
+	s.Seed("https://other.io/z", "zzzz")
+
+	conn, err := grpc.DialContext(ctx, s.GRPCAddr, grpc.WithInsecure())
+	is.NoErr(err)
+	defer conn.Close()
+	c := pb.NewTineeURLClient(conn)
+
+	listed := make(map[string]bool)
+	r := &pb.ListLinksRequest{Domain: "example.com", Order: "desc", PageSize: 1}
+	for pages := 1; ; pages++ {
+		resp, err := c.ListLinks(ctx, r)
+		is.NoErr(err)
+		is.Equal(1, len(resp.GetLinks()))
+		listed[resp.GetLinks()[0].GetUrl()] = true
+
+		if resp.GetNextPageToken() == "" {
+			is.Equal(2, pages)
+			break
+		}
+		r.PageToken = resp.GetNextPageToken()
+	}
+
+	is.Equal(map[string]bool{"https://docs.example.com/x": true, "https://example.com/y": true}, listed)
+}
+
+func TestServer_Import(t *testing.T) {
+	testcases := []struct {
+		name    string
+		format  string
+		body    string
+		expBody string
+	}{
+		{
+			name:    "short alias of tinee export is invalid",
+			format:  "csv",
+			body:    "id,url,aliases,metadata\nx,https://x.xx,a,\n",
+			expBody: `{"imported":0,"skipped":0,"failed":1,"errors":[{"row":1,"error":"invalid alias"}]}`,
+		},
+		{
+			name:    "short code of other URL shortener is imported",
+			format:  "yourls",
+			body:    "url,keyword\nhttps://x.xx,a\n",
+			expBody: `{"imported":1,"skipped":0,"failed":0}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			s := NewServer(t, Config{AdminToken: "token"})
+
+			r, err := http.NewRequest(http.MethodPost, s.HTTPURL+"/admin/links/import?format="+tc.format, strings.NewReader(tc.body))
+			is.NoErr(err)
+			r.Header.Set("Authorization", "Bearer token")
+			resp, err := http.DefaultClient.Do(r)
+			is.NoErr(err)
+			defer resp.Body.Close()
+			b, err := io.ReadAll(resp.Body)
+			is.NoErr(err)
+
+			is.Equal(http.StatusOK, resp.StatusCode)
+			is.Equal(tc.expBody, strings.TrimSpace(string(b)))
+		})
+	}
+}
+
+func TestServer_ConcurrentAliases(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	s := NewServer(t, Config{})
+	s.Seed("https://x.xx", "xxxx")
+	hc := client.NewHTTP(client.Options{}, s.HTTPURL, nil)
+
+	aliases := []string{"aaaa", "bbbb", "cccc", "dddd", "eeee"}
+	errs := make(chan error, len(aliases))
+	for _, a := range aliases {
+		go func(a string) {
+			_, err := hc.Shorten(ctx, "https://x.xx", a)
+			errs <- err
+		}(a)
+	}
+	for range aliases {
+		is.NoErr(<-errs)
+	}
+
+	l, ok := s.Link("xxxx")
+	is.True(ok)
+	is.Equal(len(aliases)+1, len(l.Aliases)) // no alias is lost
+}